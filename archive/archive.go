@@ -0,0 +1,222 @@
+package archive
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/miss-you/codetok/provider"
+)
+
+// Store is an append-only JSONL archive of usage events. Records are never
+// rewritten: a changed event is appended again and the latest record wins.
+type Store struct {
+	Path string
+}
+
+// record is the on-disk form of one archived usage event.
+type record struct {
+	Provider    string              `json:"provider"`
+	Model       string              `json:"model,omitempty"`
+	SessionID   string              `json:"session_id,omitempty"`
+	Title       string              `json:"title,omitempty"`
+	WorkDirHash string              `json:"work_dir_hash,omitempty"`
+	ProjectPath string              `json:"project_path,omitempty"`
+	Account     string              `json:"account,omitempty"`
+	Timestamp   time.Time           `json:"timestamp"`
+	TokenUsage  provider.TokenUsage `json:"token_usage"`
+	SourcePath  string              `json:"source_path,omitempty"`
+	EventID     string              `json:"event_id,omitempty"`
+	ArchivedAt  time.Time           `json:"archived_at"`
+}
+
+// NewStore returns a Store for the archive file at path. An empty path
+// resolves to the default ~/.codetok/archive/usage-events.jsonl.
+func NewStore(path string) Store {
+	return Store{Path: path}
+}
+
+// DefaultPath returns the default codetok-owned archive file.
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".codetok", "archive", "usage-events.jsonl"), nil
+}
+
+// EventKey identifies an event across runs by provider and event ID, so an
+// event keeps its key when a resumed or re-synced log carries it in another
+// file. Providers whose IDs depend on the file already include the path in the
+// ID. Events without an EventID fall back to their source file and timestamp.
+func EventKey(e provider.UsageEvent) string {
+	if e.EventID != "" {
+		return e.ProviderName + "\x00" + e.EventID
+	}
+	return e.ProviderName + "\x00" + e.SourcePath + "\x00" + e.SessionID + "@" + e.Timestamp.UTC().Format(time.RFC3339Nano)
+}
+
+// Load returns the latest archived record for every event key, in first-archived order.
+// A missing archive yields no events; malformed lines are skipped.
+func (s Store) Load() ([]provider.UsageEvent, error) {
+	path, err := s.path()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var events []provider.UsageEvent
+	index := make(map[string]int)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var rec record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			continue
+		}
+		event := rec.usageEvent()
+		key := EventKey(event)
+		if i, ok := index[key]; ok {
+			events[i] = event
+			continue
+		}
+		index[key] = len(events)
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// Append archives events that are new or differ from their latest archived
+// record, and returns how many records were written.
+func (s Store) Append(events []provider.UsageEvent, now time.Time) (int, error) {
+	existing, err := s.Load()
+	if err != nil {
+		return 0, err
+	}
+	latest := make(map[string]provider.UsageEvent, len(existing))
+	for _, e := range existing {
+		latest[EventKey(e)] = e
+	}
+
+	var pending []record
+	for _, e := range events {
+		key := EventKey(e)
+		if prev, ok := latest[key]; ok && sameEvent(prev, e) {
+			continue
+		}
+		latest[key] = e
+		pending = append(pending, newRecord(e, now))
+	}
+	if len(pending) == 0 {
+		return 0, nil
+	}
+
+	path, err := s.path()
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return 0, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o600)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	if needsNewline, err := endsWithoutNewline(f); err != nil {
+		return 0, err
+	} else if needsNewline {
+		// Start fresh after a line cut short by an interrupted write.
+		if err := w.WriteByte('\n'); err != nil {
+			return 0, err
+		}
+	}
+	enc := json.NewEncoder(w)
+	for _, rec := range pending {
+		if err := enc.Encode(rec); err != nil {
+			return 0, err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return 0, err
+	}
+	return len(pending), f.Sync()
+}
+
+func (s Store) path() (string, error) {
+	if s.Path != "" {
+		return s.Path, nil
+	}
+	return DefaultPath()
+}
+
+func newRecord(e provider.UsageEvent, now time.Time) record {
+	return record{
+		Provider:    e.ProviderName,
+		Model:       e.ModelName,
+		SessionID:   e.SessionID,
+		Title:       e.Title,
+		WorkDirHash: e.WorkDirHash,
+		ProjectPath: e.ProjectPath,
+		Account:     e.Account,
+		Timestamp:   e.Timestamp,
+		TokenUsage:  e.TokenUsage,
+		SourcePath:  e.SourcePath,
+		EventID:     e.EventID,
+		ArchivedAt:  now,
+	}
+}
+
+func (r record) usageEvent() provider.UsageEvent {
+	return provider.UsageEvent{
+		ProviderName: r.Provider,
+		ModelName:    r.Model,
+		SessionID:    r.SessionID,
+		Title:        r.Title,
+		WorkDirHash:  r.WorkDirHash,
+		ProjectPath:  r.ProjectPath,
+		Account:      r.Account,
+		Timestamp:    r.Timestamp,
+		TokenUsage:   r.TokenUsage,
+		SourcePath:   r.SourcePath,
+		EventID:      r.EventID,
+	}
+}
+
+func sameEvent(a, b provider.UsageEvent) bool {
+	return a.ModelName == b.ModelName &&
+		a.SessionID == b.SessionID &&
+		a.Title == b.Title &&
+		a.WorkDirHash == b.WorkDirHash &&
+		a.ProjectPath == b.ProjectPath &&
+		a.Account == b.Account &&
+		a.Timestamp.Equal(b.Timestamp) &&
+		a.TokenUsage == b.TokenUsage
+}
+
+func endsWithoutNewline(f *os.File) (bool, error) {
+	info, err := f.Stat()
+	if err != nil || info.Size() == 0 {
+		return false, err
+	}
+	last := make([]byte, 1)
+	if _, err := f.ReadAt(last, info.Size()-1); err != nil && err != io.EOF {
+		return false, err
+	}
+	return last[0] != '\n', nil
+}
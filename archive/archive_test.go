@@ -0,0 +1,106 @@
+package archive
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/miss-you/codetok/provider"
+)
+
+func archiveTestEvent(id string, output int) provider.UsageEvent {
+	return provider.UsageEvent{
+		ProviderName: "claude",
+		ModelName:    "claude-sonnet-4-5",
+		SessionID:    "s1",
+		ProjectPath:  "/work/x",
+		Timestamp:    time.Date(2026, 4, 16, 9, 0, 0, 0, time.UTC),
+		TokenUsage:   provider.TokenUsage{InputOther: 10, Output: output},
+		SourcePath:   "/logs/s1.jsonl",
+		EventID:      id,
+	}
+}
+
+func TestStore_AppendSkipsKnownEventsAndKeepsLatestCopy(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "archive", "usage-events.jsonl"))
+	now := time.Date(2026, 4, 17, 0, 0, 0, 0, time.UTC)
+
+	added, err := store.Append([]provider.UsageEvent{archiveTestEvent("a", 5), archiveTestEvent("b", 7)}, now)
+	if err != nil || added != 2 {
+		t.Fatalf("first Append = %d, %v; want 2 records", added, err)
+	}
+	added, err = store.Append([]provider.UsageEvent{archiveTestEvent("a", 5), archiveTestEvent("b", 7)}, now)
+	if err != nil || added != 0 {
+		t.Fatalf("repeated Append = %d, %v; want nothing new", added, err)
+	}
+	added, err = store.Append([]provider.UsageEvent{archiveTestEvent("a", 30)}, now)
+	if err != nil || added != 1 {
+		t.Fatalf("changed Append = %d, %v; want the updated event appended", added, err)
+	}
+
+	events, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2: %#v", len(events), events)
+	}
+	if events[0].EventID != "a" || events[0].TokenUsage.Output != 30 {
+		t.Fatalf("first event = %#v, want latest copy of a", events[0])
+	}
+	if events[1].EventID != "b" || !events[1].Timestamp.Equal(archiveTestEvent("b", 7).Timestamp) || events[1].ProjectPath != "/work/x" {
+		t.Fatalf("second event = %#v, want b with metadata preserved", events[1])
+	}
+}
+
+func TestStore_AppendRecoversFromTruncatedLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usage-events.jsonl")
+	if err := os.WriteFile(path, []byte(`{"provider":"claude","event_id":"cut`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	store := NewStore(path)
+	if _, err := store.Append([]provider.UsageEvent{archiveTestEvent("a", 5)}, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].EventID != "a" {
+		t.Fatalf("events = %#v, want only the intact record", events)
+	}
+}
+
+func TestStore_AppendMatchesEventIDAcrossSourceFiles(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "usage-events.jsonl"))
+	if _, err := store.Append([]provider.UsageEvent{archiveTestEvent("msg-1:req-1", 5)}, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	// A resumed session repeats the message in a new transcript.
+	resumed := archiveTestEvent("msg-1:req-1", 5)
+	resumed.SourcePath = "/logs/s2.jsonl"
+	written, err := store.Append([]provider.UsageEvent{resumed}, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if written != 0 {
+		t.Fatalf("written = %d, want the resumed copy to match the archived event", written)
+	}
+
+	unnamed := archiveTestEvent("", 5)
+	moved := unnamed
+	moved.SourcePath = "/logs/s2.jsonl"
+	if EventKey(unnamed) == EventKey(moved) {
+		t.Fatal("events without an ID should stay keyed by their source file")
+	}
+}
+
+func TestStore_LoadMissingArchive(t *testing.T) {
+	events, err := NewStore(filepath.Join(t.TempDir(), "missing.jsonl")).Load()
+	if err != nil || events != nil {
+		t.Fatalf("Load = %#v, %v; want empty archive", events, err)
+	}
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/miss-you/codetok/archive"
+	"github.com/miss-you/codetok/provider"
+)
+
+const archiveFlagUsage = "Merge usage events saved by 'codetok archive' with live local sources"
+
+var archiveCmd = &cobra.Command{
+	Use:   "archive",
+	Short: "Copy newly seen usage events into the local archive",
+	Long: `Copy newly seen usage events into the local archive.
+
+Archive reads every live local source, like 'daily --all', and appends events it has not stored before to ~/.codetok/archive/usage-events.jsonl. Events are deduplicated by provider, source file, and event ID; an event whose usage changed is appended again and the latest copy wins.
+
+Coding tools prune their own logs over time. Run archive periodically, then pass --archive to daily, weekly, monthly, or session to merge archived events with live sources so older reports stay reproducible.`,
+	Args: cobra.NoArgs,
+	RunE: runArchive,
+}
+
+func init() {
+	addUsageSourceFlags(archiveCmd)
+	rootCmd.AddCommand(archiveCmd)
+}
+
+func runArchive(cmd *cobra.Command, args []string) error {
+	return runArchiveWithProviders(cmd, args, provider.Registry(), archive.NewStore(""), time.Now())
+}
+
+func runArchiveWithProviders(cmd *cobra.Command, args []string, providers []provider.Provider, store archive.Store, now time.Time) error {
+	events, err := collectUsageEventsFromProviders(cmd, providers)
+	if err != nil {
+		return err
+	}
+	added, err := store.Append(events, now)
+	if err != nil {
+		return fmt.Errorf("writing archive: %w", err)
+	}
+	path := store.Path
+	if path == "" {
+		if path, err = archive.DefaultPath(); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("Archived %d new usage events from %d live events to %s\n", added, len(events), path)
+	return nil
+}
+
+// archivedUsageEventsForMerge returns archived events for providers in
+// providerNames that are in range and have no live counterpart in liveKeys.
+func archivedUsageEventsForMerge(store archive.Store, providerNames map[string]struct{}, liveKeys map[string]struct{}, opts provider.UsageEventCollectOptions) ([]provider.UsageEvent, error) {
+	archived, err := store.Load()
+	if err != nil {
+		return nil, fmt.Errorf("reading archive: %w", err)
+	}
+	var events []provider.UsageEvent
+	for _, e := range archived {
+		if _, ok := providerNames[e.ProviderName]; !ok {
+			continue
+		}
+		if _, ok := liveKeys[archive.EventKey(e)]; ok {
+			continue
+		}
+		if !opts.ContainsTimestamp(e.Timestamp) {
+			continue
+		}
+		events = append(events, e)
+	}
+	return events, nil
+}
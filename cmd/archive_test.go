@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/miss-you/codetok/archive"
+	cursorapi "github.com/miss-you/codetok/cursor"
+	"github.com/miss-you/codetok/provider"
+)
+
+func newArchiveTestCommand() *cobra.Command {
+	cmd := &cobra.Command{}
+	addUsageSourceFlags(cmd)
+	disableTestUsageEventCache(cmd)
+	return cmd
+}
+
+func archiveTestEvents() []provider.UsageEvent {
+	ts := time.Date(2025, 10, 3, 9, 0, 0, 0, time.UTC)
+	return []provider.UsageEvent{
+		{ProviderName: "claude", SessionID: "pruned", SourcePath: "/logs/pruned.jsonl", EventID: "m1", Timestamp: ts, TokenUsage: provider.TokenUsage{InputOther: 100}},
+		{ProviderName: "claude", SessionID: "live", SourcePath: "/logs/live.jsonl", EventID: "m2", Timestamp: ts, TokenUsage: provider.TokenUsage{InputOther: 20}},
+	}
+}
+
+func TestRunArchive_AppendsOnlyNewEvents(t *testing.T) {
+	store := archive.NewStore(filepath.Join(t.TempDir(), "usage-events.jsonl"))
+	eventProvider := &collectTestUsageEventProvider{
+		collectTestProvider: collectTestProvider{name: "claude"},
+		events:              archiveTestEvents(),
+	}
+
+	for _, want := range []string{"Archived 2 new usage events", "Archived 0 new usage events"} {
+		output := captureStdout(t, func() {
+			if err := runArchiveWithProviders(newArchiveTestCommand(), nil, []provider.Provider{eventProvider}, store, time.Now()); err != nil {
+				t.Fatalf("runArchiveWithProviders returned error: %v", err)
+			}
+		})
+		if !strings.Contains(output, want) {
+			t.Fatalf("output = %q, want %q", output, want)
+		}
+	}
+}
+
+func TestRunDaily_ArchiveMergesPrunedEvents(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if _, err := archive.NewStore("").Append(archiveTestEvents(), time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	// Only the live session is still on disk; its usage grew since it was archived.
+	live := archiveTestEvents()[1]
+	live.TokenUsage.InputOther = 50
+	eventProvider := &collectTestUsageEventProvider{
+		collectTestProvider: collectTestProvider{name: "claude"},
+		events:              []provider.UsageEvent{live},
+	}
+
+	run := func(mergeArchive string) []provider.DailyStats {
+		t.Helper()
+		cmd := newDailyTestCommand()
+		for name, value := range map[string]string{"json": "true", "since": "2025-10-01", "until": "2025-10-31", "timezone": "UTC", "archive": mergeArchive} {
+			if err := cmd.Flags().Set(name, value); err != nil {
+				t.Fatalf("setting --%s: %v", name, err)
+			}
+		}
+		output := captureStdout(t, func() {
+			if err := runDailyWithProviders(cmd, nil, []provider.Provider{eventProvider}, time.Date(2026, 4, 16, 0, 0, 0, 0, time.UTC)); err != nil {
+				t.Fatalf("runDailyWithProviders returned error: %v", err)
+			}
+		})
+		return decodeDailyJSON(t, output)
+	}
+
+	if rows := run("false"); len(rows) != 1 || rows[0].Sessions != 1 || rows[0].TokenUsage.Total() != 50 {
+		t.Fatalf("live-only rows = %#v, want only the live session", rows)
+	}
+	rows := run("true")
+	if len(rows) != 1 || rows[0].Sessions != 2 || rows[0].TokenUsage.Total() != 150 {
+		t.Fatalf("merged rows = %#v, want pruned archive session plus live usage", rows)
+	}
+}
+
+func TestRunDaily_ArchiveMatchesCursorRowsAfterSync(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	store := cursorapi.NewStore("")
+	header := "Date,Kind,Model,Input (w/ Cache Write),Input (w/o Cache Write),Cache Read,Output Tokens\n"
+	if _, _, err := store.MergeSyncedCSV([]byte(header +
+		`"2025-10-03T10:00:00.000Z","Included","gpt-5","0","100","0","10"` + "\n" +
+		`"2025-10-02T10:00:00.000Z","Included","gpt-5","0","200","0","20"` + "\n")); err != nil {
+		t.Fatal(err)
+	}
+	providers := provider.FilterProviders(provider.Registry(), "cursor")
+	captureStdout(t, func() {
+		if err := runArchiveWithProviders(newArchiveTestCommand(), nil, providers, archive.NewStore(""), time.Now()); err != nil {
+			t.Fatalf("runArchiveWithProviders returned error: %v", err)
+		}
+	})
+
+	// The next sync puts a newer row, outside the report range, ahead of every
+	// archived row in the ledger.
+	if _, _, err := store.MergeSyncedCSV([]byte(header +
+		`"2025-11-04T10:00:00.000Z","Included","gpt-5","0","400","0","40"` + "\n" +
+		`"2025-10-03T10:00:00.000Z","Included","gpt-5","0","100","0","10"` + "\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := newDailyTestCommand()
+	for name, value := range map[string]string{"json": "true", "since": "2025-10-01", "until": "2025-10-31", "timezone": "UTC", "group-by": "cli", "archive": "true"} {
+		if err := cmd.Flags().Set(name, value); err != nil {
+			t.Fatalf("setting --%s: %v", name, err)
+		}
+	}
+	output := captureStdout(t, func() {
+		if err := runDailyWithProviders(cmd, nil, providers, time.Date(2026, 4, 16, 0, 0, 0, 0, time.UTC)); err != nil {
+			t.Fatalf("runDailyWithProviders returned error: %v", err)
+		}
+	})
+	total := 0
+	for _, row := range decodeDailyJSON(t, output) {
+		total += row.TokenUsage.Total()
+	}
+	if total != 330 {
+		t.Fatalf("total = %d, want 330 with every Cursor row counted once\n%s", total, output)
+	}
+}
@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/miss-you/codetok/provider"
+	"github.com/miss-you/codetok/stats"
+)
+
+var blocksCmd = &cobra.Command{
+	Use:   "blocks",
+	Short: "Show token usage in 5-hour rate-limit windows",
+	Long: `Show token usage in 5-hour rate-limit windows.
+
+Claude and Codex subscriptions limit usage in rolling windows that open with the first message after the previous window ended. blocks splits each provider's usage events into such windows: a block starts at the hour of the first activity after a gap and lasts --window (default 5h). For the block still open now, it shows the burn rate in tokens per minute since the block's first activity and the total projected at the window end if that rate holds.
+
+Date filters select events like daily does, so a block that started before --since only shows its events from --since on.
+
+Reporting commands read only local session files and Cursor CSV exports already on disk. They never trigger implicit Cursor login or sync.`,
+	RunE: runBlocks,
+}
+
+func init() {
+	addBlocksFlags(blocksCmd)
+	rootCmd.AddCommand(blocksCmd)
+}
+
+func addBlocksFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("json", false, "Output as JSON")
+	cmd.Flags().Bool("active", false, "Show only blocks that are still open")
+	cmd.Flags().Duration("window", stats.DefaultBlockWindow, "Length of a usage block")
+	cmd.Flags().String("since", "", "Start date filter (format: 2006-01-02)")
+	cmd.Flags().String("until", "", "End date filter (format: 2006-01-02)")
+	cmd.Flags().Int("days", defaultDailyDays, "Lookback window in days when --since/--until are not set")
+	cmd.Flags().Bool("all", false, "Include all historical sessions")
+	cmd.Flags().String("timezone", "", "Timezone for date filters and displayed times (IANA name, default: local)")
+	addUsageReportFlags(cmd)
+}
+
+func runBlocks(cmd *cobra.Command, args []string) error {
+	return runBlocksWithProviders(cmd, provider.Registry(), time.Now())
+}
+
+func runBlocksWithProviders(cmd *cobra.Command, providers []provider.Provider, now time.Time) error {
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	activeOnly, _ := cmd.Flags().GetBool("active")
+	window, _ := cmd.Flags().GetDuration("window")
+	sinceStr, _ := cmd.Flags().GetString("since")
+	untilStr, _ := cmd.Flags().GetString("until")
+	days, _ := cmd.Flags().GetInt("days")
+	allHistory, _ := cmd.Flags().GetBool("all")
+	timezoneStr, _ := cmd.Flags().GetString("timezone")
+	if window < time.Hour {
+		return fmt.Errorf("invalid --window: must be at least 1h")
+	}
+	loc, err := resolveTimezone(timezoneStr)
+	if err != nil {
+		return err
+	}
+	prices, err := resolvePricingTable(cmd)
+	if err != nil {
+		return err
+	}
+
+	since, until, err := resolveDailyDateRange(
+		sinceStr,
+		untilStr,
+		days,
+		allHistory,
+		cmd.Flags().Changed("days"),
+		now,
+		loc,
+	)
+	if err != nil {
+		return err
+	}
+
+	collectOpts := provider.UsageEventCollectOptions{
+		Since:    since,
+		Until:    until,
+		Location: loc,
+	}
+	sinceDate, untilDate := dailyEventFilterDates(since, until, loc)
+	dateFilter := stats.NewEventDateRangeFilter(sinceDate, untilDate, loc)
+	var events []provider.UsageEvent
+	err = forEachUsageEventFromProvidersInRange(cmd, providers, collectOpts, func(event provider.UsageEvent) error {
+		if dateFilter.Contains(event) {
+			events = append(events, event)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	blocks := stats.BuildUsageBlocks(events, window, now, prices)
+	if activeOnly {
+		active := blocks[:0]
+		for _, block := range blocks {
+			if block.Active {
+				active = append(active, block)
+			}
+		}
+		blocks = active
+	}
+
+	out := cmd.OutOrStdout()
+	if jsonOutput {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(blocks)
+	}
+	printBlocks(out, blocks, now, loc)
+	return nil
+}
+
+func printBlocks(out io.Writer, blocks []stats.UsageBlock, now time.Time, loc *time.Location) {
+	if len(blocks) == 0 {
+		fmt.Fprintln(out, "No usage blocks found.")
+		return
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Start\tEnd\tProvider\tModels\tSessions\tInput\tOutput\tTotal\tCost\tStatus")
+	var total provider.CostEstimate
+	for _, block := range blocks {
+		status := ""
+		if block.Active {
+			status = "ACTIVE"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%d\t%d\t%d\t%s\t%s\n",
+			block.Start.In(loc).Format("2006-01-02 15:04"),
+			block.End.In(loc).Format("15:04"),
+			block.ProviderName,
+			truncate(strings.Join(block.Models, ", "), 40),
+			block.Sessions,
+			block.TokenUsage.TotalInput(),
+			block.TokenUsage.Output,
+			block.TokenUsage.Total(),
+			formatCost(block.Cost),
+			status,
+		)
+		total.Add(block.Cost)
+	}
+	_ = w.Flush()
+
+	for _, block := range blocks {
+		if !block.Active {
+			continue
+		}
+		projection := fmt.Sprintf("%d tokens", block.ProjectedTokens)
+		if block.ProjectedCost != nil {
+			projection += " (" + formatCost(*block.ProjectedCost) + ")"
+		}
+		fmt.Fprintf(out, "\nActive %s block: %d tokens, burn rate %.0f tokens/min, projected %s by %s (%s left)\n",
+			block.ProviderName,
+			block.TokenUsage.Total(),
+			block.TokensPerMinute,
+			projection,
+			block.End.In(loc).Format("15:04"),
+			formatHoursMinutes(block.End.Sub(now)),
+		)
+	}
+	printUnpricedModelsNote(out, total)
+}
+
+// formatHoursMinutes formats d as e.g. 3h05m.
+func formatHoursMinutes(d time.Duration) string {
+	minutes := int(d / time.Minute)
+	return fmt.Sprintf("%dh%02dm", minutes/60, minutes%60)
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/miss-you/codetok/provider"
+	"github.com/miss-you/codetok/stats"
+)
+
+func blocksTestProviders() []provider.Provider {
+	return []provider.Provider{
+		&collectTestUsageEventProvider{
+			collectTestProvider: collectTestProvider{name: "claude"},
+			events: []provider.UsageEvent{
+				{ProviderName: "claude", ModelName: "claude-sonnet-4-5", SessionID: "a", Timestamp: time.Date(2026, 4, 16, 1, 15, 0, 0, time.UTC), TokenUsage: provider.TokenUsage{InputOther: 500, Output: 100}},
+				{ProviderName: "claude", ModelName: "claude-sonnet-4-5", SessionID: "b", Timestamp: time.Date(2026, 4, 16, 10, 30, 0, 0, time.UTC), TokenUsage: provider.TokenUsage{InputOther: 2000, Output: 1000}},
+				{ProviderName: "claude", ModelName: "claude-sonnet-4-5", SessionID: "old", Timestamp: time.Date(2026, 4, 1, 10, 0, 0, 0, time.UTC), TokenUsage: provider.TokenUsage{InputOther: 9999}},
+			},
+		},
+	}
+}
+
+func TestRunBlocks_PrintsBlocksAndActiveProjection(t *testing.T) {
+	now := time.Date(2026, 4, 16, 11, 0, 0, 0, time.UTC)
+	cmd, out := newFlagTestCommand(t, addBlocksFlags, map[string]string{"timezone": "UTC"})
+	if err := runBlocksWithProviders(cmd, blocksTestProviders(), now); err != nil {
+		t.Fatalf("runBlocksWithProviders: %v", err)
+	}
+
+	assertContainsAll(t, out.String(),
+		"Start", "Status",
+		"2026-04-16 01:00  06:00  claude",
+		"2026-04-16 10:00  15:00  claude",
+		"ACTIVE",
+		// 3000 tokens over the 30 minutes since 10:30 is 100/min, with 240 minutes left.
+		"Active claude block: 3000 tokens, burn rate 100 tokens/min, projected 27000 tokens",
+		"by 15:00 (4h00m left)",
+	)
+	if bytes.Contains(out.Bytes(), []byte("9999")) {
+		t.Fatalf("output = %q, want events outside --days excluded", out.String())
+	}
+}
+
+func TestRunBlocks_ActiveJSON(t *testing.T) {
+	now := time.Date(2026, 4, 16, 11, 0, 0, 0, time.UTC)
+	cmd, out := newFlagTestCommand(t, addBlocksFlags, map[string]string{"timezone": "UTC", "json": "true", "active": "true"})
+	if err := runBlocksWithProviders(cmd, blocksTestProviders(), now); err != nil {
+		t.Fatalf("runBlocksWithProviders: %v", err)
+	}
+
+	var blocks []stats.UsageBlock
+	if err := json.Unmarshal(out.Bytes(), &blocks); err != nil {
+		t.Fatalf("decoding %q: %v", out.String(), err)
+	}
+	if len(blocks) != 1 || !blocks[0].Active || blocks[0].ProjectedTokens != 27000 || blocks[0].Sessions != 1 {
+		t.Fatalf("blocks = %+v, want only the active block", blocks)
+	}
+
+	cmd, _ = newFlagTestCommand(t, addBlocksFlags, map[string]string{"window": "30m"})
+	if err := runBlocksWithProviders(cmd, blocksTestProviders(), now); err == nil {
+		t.Fatal("expected error for a window shorter than an hour")
+	}
+}
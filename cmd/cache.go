@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/miss-you/codetok/provider"
+)
+
+const noCacheFlagUsage = "Reparse every local log instead of reusing the usage event cache"
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the local usage event cache",
+	Long: `Manage the local usage event cache.
+
+Reporting commands cache usage events parsed from each local log under ~/.codetok/cache/, keyed by file path, size, modification time, and parser version. Only new or changed logs are reparsed, and append-only JSONL logs resume from the last parsed line. Use --no-cache on a report to bypass the cache.`,
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Delete the local usage event cache",
+	Args:  cobra.NoArgs,
+	RunE:  runCacheClear,
+}
+
+func init() {
+	cacheCmd.AddCommand(cacheClearCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
+
+func runCacheClear(cmd *cobra.Command, args []string) error {
+	path, err := provider.DefaultUsageEventCachePath()
+	if err != nil {
+		return fmt.Errorf("resolving cache path: %w", err)
+	}
+	if err := provider.ClearUsageEventCache(path); err != nil {
+		return fmt.Errorf("clearing cache: %w", err)
+	}
+	fmt.Printf("Cleared usage event cache: %s\n", path)
+	return nil
+}
+
+// openUsageEventCache returns the default usage event cache for commands that
+// define --no-cache, or nil when caching is off or the cache cannot be opened.
+func openUsageEventCache(cmd *cobra.Command) *provider.UsageEventCache {
+	if cmd.Flags().Lookup("no-cache") == nil {
+		return nil
+	}
+	if noCache, _ := cmd.Flags().GetBool("no-cache"); noCache {
+		return nil
+	}
+	path, err := provider.DefaultUsageEventCachePath()
+	if err != nil {
+		return nil
+	}
+	cache, err := provider.OpenUsageEventCache(path)
+	if err != nil {
+		return nil
+	}
+	return cache
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestRunCacheClear_RemovesDefaultCacheFile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	cachePath := filepath.Join(home, ".codetok", "cache", "usage-events.gob")
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(cachePath, []byte("stale"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	output := captureStdout(t, func() {
+		if err := runCacheClear(cacheClearCmd, nil); err != nil {
+			t.Fatalf("runCacheClear returned error: %v", err)
+		}
+	})
+
+	if !strings.Contains(output, "Cleared usage event cache") {
+		t.Fatalf("output = %q, want confirmation", output)
+	}
+	if _, err := os.Stat(cachePath); !os.IsNotExist(err) {
+		t.Fatalf("cache file still exists: %v", err)
+	}
+}
+
+func TestOpenUsageEventCache_RespectsNoCacheFlag(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	if cache := openUsageEventCache(&cobra.Command{}); cache != nil {
+		t.Fatal("commands without --no-cache should not use the cache")
+	}
+
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool("no-cache", false, "")
+	if cache := openUsageEventCache(cmd); cache == nil {
+		t.Fatal("expected a cache when --no-cache is not set")
+	}
+	if err := cmd.Flags().Set("no-cache", "true"); err != nil {
+		t.Fatal(err)
+	}
+	if cache := openUsageEventCache(cmd); cache != nil {
+		t.Fatal("expected no cache with --no-cache")
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/miss-you/codetok/archive"
 	"github.com/miss-you/codetok/provider"
 )
 
@@ -66,12 +67,60 @@ func forEachUsageEventFromProvidersInRange(cmd *cobra.Command, providers []provi
 	})
 }
 
+const cursorDirFlagUsage = "Override Cursor CSV directory; scans only this local path and skips default Cursor imports/synced roots"
+
+// addUsageSourceFlags registers the flags forEachUsageEventFromProvidersInRange
+// reads to find and parse local usage data.
+func addUsageSourceFlags(cmd *cobra.Command) {
+	cmd.Flags().String("provider", "", "Filter by provider name (e.g. kimi, claude, codex, gemini, opencode, cursor)")
+	cmd.Flags().String("base-dir", "", "Override default data directory (applies to all providers)")
+	cmd.Flags().String("kimi-dir", "", "Override Kimi data directory")
+	cmd.Flags().String("claude-dir", "", "Override Claude Code data directory")
+	cmd.Flags().String("codex-dir", "", "Override Codex CLI data directory")
+	cmd.Flags().String("opencode-dir", "", "Override OpenCode storage directory")
+	cmd.Flags().String("gemini-dir", "", "Override Gemini CLI tmp directory")
+	cmd.Flags().String("cursor-dir", "", cursorDirFlagUsage)
+	cmd.Flags().Bool("no-cache", false, noCacheFlagUsage)
+	cmd.Flags().Bool("strict", false, strictFlagUsage)
+	cmd.Flags().Bool("diagnostics", false, diagnosticsFlagUsage)
+}
+
+// addUsageReportFlags registers the usage source flags plus the pricing and
+// archive flags of commands that report collected usage.
+func addUsageReportFlags(cmd *cobra.Command) {
+	addUsageSourceFlags(cmd)
+	cmd.Flags().String("pricing-file", "", pricingFileFlagUsage)
+	cmd.Flags().Bool("archive", false, archiveFlagUsage)
+}
+
 func forEachUsageEventBatchFromProvidersInRange(cmd *cobra.Command, providers []provider.Provider, opts provider.UsageEventCollectOptions, consume func([]provider.UsageEvent) error) error {
 	providerFilter, _ := cmd.Flags().GetString("provider")
 	baseDir, _ := cmd.Flags().GetString("base-dir")
 
 	filtered := provider.FilterProviders(providers, providerFilter)
 
+	if opts.Cache == nil {
+		opts.Cache = openUsageEventCache(cmd)
+		// The cache only speeds up later runs, so failing to save it is not an error.
+		defer opts.Cache.Save()
+	}
+	if opts.Diagnostics == nil {
+		opts.Diagnostics = &provider.Diagnostics{}
+	}
+
+	mergeArchive, _ := cmd.Flags().GetBool("archive")
+	var liveKeys map[string]struct{}
+	if mergeArchive {
+		liveKeys = make(map[string]struct{})
+		consumeLive := consume
+		consume = func(events []provider.UsageEvent) error {
+			for _, e := range events {
+				liveKeys[archive.EventKey(e)] = struct{}{}
+			}
+			return consumeLive(events)
+		}
+	}
+
 	for _, p := range filtered {
 		dir := baseDir
 		if providerDir, _ := cmd.Flags().GetString(providerDirFlag(p.Name())); providerDir != "" {
@@ -115,12 +164,29 @@ func forEachUsageEventBatchFromProvidersInRange(cmd *cobra.Command, providers []
 			return fmt.Errorf("processing usage events from %s: %w", p.Name(), err)
 		}
 	}
+	if err := reportUsageEventDiagnostics(cmd, opts.Diagnostics); err != nil {
+		return err
+	}
+
+	if mergeArchive {
+		providerNames := make(map[string]struct{}, len(filtered))
+		for _, p := range filtered {
+			providerNames[p.Name()] = struct{}{}
+		}
+		events, err := archivedUsageEventsForMerge(archive.NewStore(""), providerNames, liveKeys, opts)
+		if err != nil {
+			return err
+		}
+		if err := consume(events); err != nil {
+			return fmt.Errorf("processing archived usage events: %w", err)
+		}
+	}
 
 	return nil
 }
 
 func collectProviderUsageEvents(eventProvider provider.UsageEventProvider, dir string, opts provider.UsageEventCollectOptions) ([]provider.UsageEvent, error) {
-	if opts.HasRange() {
+	if opts.HasRange() || opts.Cache != nil {
 		if rangeProvider, ok := eventProvider.(provider.RangeAwareUsageEventProvider); ok {
 			return rangeProvider.CollectUsageEventsInRange(dir, opts)
 		}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"bytes"
 	"errors"
 	"os"
 	"reflect"
@@ -467,3 +468,28 @@ func newCollectTestCommand(providerNames ...string) *cobra.Command {
 	}
 	return cmd
 }
+
+// newFlagTestCommand builds a bare command with the flags registered by
+// addFlags, so tests run against a command's real flag set and defaults.
+func newFlagTestCommand(t *testing.T, addFlags func(*cobra.Command), flags map[string]string) (*cobra.Command, *bytes.Buffer) {
+	t.Helper()
+	cmd := &cobra.Command{}
+	addFlags(cmd)
+	disableTestUsageEventCache(cmd)
+	for name, value := range flags {
+		if err := cmd.Flags().Set(name, value); err != nil {
+			t.Fatalf("setting --%s: %v", name, err)
+		}
+	}
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	return cmd, &out
+}
+
+// disableTestUsageEventCache keeps commands built from real flag sets away
+// from the user's usage event cache.
+func disableTestUsageEventCache(cmd *cobra.Command) {
+	if flag := cmd.Flags().Lookup("no-cache"); flag != nil {
+		flag.Value.Set("true")
+	}
+}
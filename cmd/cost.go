@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/miss-you/codetok/pricing"
+	"github.com/miss-you/codetok/provider"
+)
+
+const pricingFileFlagUsage = "Model pricing JSON file overriding the embedded prices (default: ~/.codetok/pricing.json when present)"
+
+// resolvePricingTable loads the embedded price table plus any user override file.
+func resolvePricingTable(cmd *cobra.Command) (*pricing.Table, error) {
+	path, _ := cmd.Flags().GetString("pricing-file")
+	return pricing.Load(path)
+}
+
+// formatCost renders an estimate for tables. Partial estimates are marked with
+// "*" because they exclude usage from unpriced models.
+func formatCost(cost provider.CostEstimate) string {
+	switch cost.Status {
+	case provider.CostStatusUnknown:
+		return "unknown"
+	case provider.CostStatusPartial:
+		return fmt.Sprintf("$%.2f*", cost.USD)
+	default:
+		return fmt.Sprintf("$%.2f", cost.USD)
+	}
+}
+
+// printUnpricedModelsNote explains "unknown" and "*" cost cells, if any were printed.
+func printUnpricedModelsNote(w io.Writer, cost provider.CostEstimate) {
+	if len(cost.UnpricedModels) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "Cost excludes models without a price (* = partial): %s\n", strings.Join(cost.UnpricedModels, ", "))
+}
@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/miss-you/codetok/provider"
+)
+
+func writeTestPricingFile(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "pricing.json")
+	content := `{"house-model": {"input": 2, "output": 10, "cache_read": 0.2, "cache_write": 2.5}}`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func costTestEvents() []provider.UsageEvent {
+	return []provider.UsageEvent{
+		{
+			ProviderName: "codex",
+			ModelName:    "house-model",
+			SessionID:    "priced",
+			Timestamp:    time.Date(2026, 4, 16, 9, 0, 0, 0, time.UTC),
+			TokenUsage:   provider.TokenUsage{InputOther: 1_000_000, Output: 100_000},
+		},
+		{
+			ProviderName: "codex",
+			ModelName:    "mystery-model",
+			SessionID:    "unpriced",
+			Timestamp:    time.Date(2026, 4, 16, 10, 0, 0, 0, time.UTC),
+			TokenUsage:   provider.TokenUsage{InputOther: 500},
+		},
+	}
+}
+
+func TestRunDaily_JSONIncludesCostEstimate(t *testing.T) {
+	eventProvider := &collectTestUsageEventProvider{
+		collectTestProvider: collectTestProvider{name: "codex"},
+		events:              costTestEvents(),
+	}
+	cmd := newDailyTestCommand()
+	for name, value := range map[string]string{
+		"json":         "true",
+		"all":          "true",
+		"timezone":     "UTC",
+		"group-by":     "model",
+		"pricing-file": writeTestPricingFile(t),
+	} {
+		if err := cmd.Flags().Set(name, value); err != nil {
+			t.Fatalf("setting --%s: %v", name, err)
+		}
+	}
+
+	output := captureStdout(t, func() {
+		if err := runDailyWithProviders(cmd, nil, []provider.Provider{eventProvider}, time.Date(2026, 4, 16, 12, 0, 0, 0, time.UTC)); err != nil {
+			t.Fatalf("runDailyWithProviders returned error: %v", err)
+		}
+	})
+
+	rows := decodeDailyJSON(t, output)
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2: %s", len(rows), output)
+	}
+	byGroup := make(map[string]provider.DailyStats)
+	for _, row := range rows {
+		byGroup[row.Group] = row
+	}
+	priced := byGroup["house-model"].Cost
+	if priced.Status != provider.CostStatusKnown || math.Abs(priced.USD-3) > 1e-9 {
+		t.Fatalf("house-model cost = %+v, want $3 known", priced)
+	}
+	unpriced := byGroup["mystery-model"].Cost
+	if unpriced.Status != provider.CostStatusUnknown || len(unpriced.UnpricedModels) != 1 || unpriced.UnpricedModels[0] != "mystery-model" {
+		t.Fatalf("mystery-model cost = %+v, want unknown", unpriced)
+	}
+}
+
+func TestRunDaily_DashboardShowsCostColumns(t *testing.T) {
+	eventProvider := &collectTestUsageEventProvider{
+		collectTestProvider: collectTestProvider{name: "codex"},
+		events:              costTestEvents(),
+	}
+	cmd := newDailyTestCommand()
+	for name, value := range map[string]string{
+		"all":          "true",
+		"timezone":     "UTC",
+		"pricing-file": writeTestPricingFile(t),
+	} {
+		if err := cmd.Flags().Set(name, value); err != nil {
+			t.Fatalf("setting --%s: %v", name, err)
+		}
+	}
+
+	output := captureStdout(t, func() {
+		if err := runDailyWithProviders(cmd, nil, []provider.Provider{eventProvider}, time.Date(2026, 4, 16, 12, 0, 0, 0, time.UTC)); err != nil {
+			t.Fatalf("runDailyWithProviders returned error: %v", err)
+		}
+	})
+
+	assertContainsAll(t, output,
+		"Estimated cost: $3.00*",
+		"Cost",
+		"Cost excludes models without a price (* = partial): mystery-model",
+	)
+}
+
+func TestRunDaily_InvalidPricingFileReturnsError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pricing.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cmd := newDailyTestCommand()
+	if err := cmd.Flags().Set("pricing-file", path); err != nil {
+		t.Fatalf("setting --pricing-file: %v", err)
+	}
+
+	err := runDailyWithProviders(cmd, nil, nil, time.Now())
+	if err == nil || !strings.Contains(err.Error(), "parse pricing file") {
+		t.Fatalf("err = %v, want pricing file parse error", err)
+	}
+}
+
+func TestRunSession_ReportsCostPerSession(t *testing.T) {
+	eventProvider := &collectTestUsageEventProvider{
+		collectTestProvider: collectTestProvider{name: "codex"},
+		events:              costTestEvents(),
+	}
+
+	cmd := newSessionTestCommand()
+	for name, value := range map[string]string{
+		"json":         "true",
+		"timezone":     "UTC",
+		"pricing-file": writeTestPricingFile(t),
+	} {
+		if err := cmd.Flags().Set(name, value); err != nil {
+			t.Fatalf("setting --%s: %v", name, err)
+		}
+	}
+	output := captureStdout(t, func() {
+		if err := runSessionWithProviders(cmd, nil, []provider.Provider{eventProvider}); err != nil {
+			t.Fatalf("runSessionWithProviders returned error: %v", err)
+		}
+	})
+	var sessions []sessionJSON
+	if err := json.Unmarshal([]byte(output), &sessions); err != nil {
+		t.Fatalf("failed to decode JSON: %v\n%s", err, output)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("got %d sessions, want 2", len(sessions))
+	}
+	if sessions[0].Cost.Status != provider.CostStatusKnown || math.Abs(sessions[0].Cost.USD-3) > 1e-9 {
+		t.Fatalf("priced session cost = %+v, want $3 known", sessions[0].Cost)
+	}
+	if sessions[1].Cost.Status != provider.CostStatusUnknown {
+		t.Fatalf("unpriced session cost = %+v, want unknown", sessions[1].Cost)
+	}
+
+	tableCmd := newSessionTestCommand()
+	for name, value := range map[string]string{
+		"timezone":     "UTC",
+		"pricing-file": writeTestPricingFile(t),
+	} {
+		if err := tableCmd.Flags().Set(name, value); err != nil {
+			t.Fatalf("setting --%s: %v", name, err)
+		}
+	}
+	table := captureStdout(t, func() {
+		if err := runSessionWithProviders(tableCmd, nil, []provider.Provider{eventProvider}); err != nil {
+			t.Fatalf("runSessionWithProviders returned error: %v", err)
+		}
+	})
+	assertContainsAll(t, table, "Cost", "$3.00", "unknown", "$3.00*", "mystery-model")
+}
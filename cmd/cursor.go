@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strconv"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -15,11 +17,12 @@ import (
 )
 
 type cursorCommandService interface {
-	Login(context.Context, string) (cursorapi.ValidationResult, error)
-	Status(context.Context) (cursorapi.StatusResult, error)
-	Activity(context.Context, string) (cursorapi.ActivityResult, error)
-	Sync(context.Context) (cursorapi.SyncResult, error)
-	Logout() error
+	Login(ctx context.Context, profile, token string) (cursorapi.ValidationResult, error)
+	Status(ctx context.Context, profile string) (cursorapi.StatusResult, error)
+	Activity(ctx context.Context, dbPath string, query cursorapi.ActivityQuery) (cursorapi.ActivityResult, error)
+	Usage(ctx context.Context, profile string) (cursorapi.UsageSummary, error)
+	Sync(ctx context.Context, profile string, r cursorapi.UsageRange) (cursorapi.SyncResult, error)
+	Logout(profile string) error
 }
 
 func init() {
@@ -39,14 +42,20 @@ func newCursorCommand(service cursorCommandService) *cobra.Command {
 		Short: "Cursor auth, sync, and local activity tools",
 		Long: `Manage Cursor authentication, local dashboard sync, and local activity attribution.
 
-Only 'login', 'status', and 'sync' may contact the remote Cursor API.
-'activity' plus daily and session reporting remain local-file based.`,
+Only 'login', 'status', 'usage', and 'sync' may contact the remote Cursor API.
+'activity', 'efficiency', and daily and session reporting remain local-file based.
+
+Use --profile on login, status, usage, sync, and logout to keep several Cursor accounts
+(for example work and personal) side by side. Reports label Cursor usage with its
+profile, so 'daily --group-by account' separates them.`,
 	}
 
 	cmd.AddCommand(
 		newCursorLoginCommand(service),
 		newCursorStatusCommand(service),
 		newCursorActivityCommand(service),
+		newCursorUsageCommand(service),
+		newCursorEfficiencyCommand(service),
 		newCursorSyncCommand(service),
 		newCursorLogoutCommand(service),
 	)
@@ -55,7 +64,7 @@ Only 'login', 'status', and 'sync' may contact the remote Cursor API.
 }
 
 func newCursorLoginCommand(service cursorCommandService) *cobra.Command {
-	var token string
+	var token, profile string
 
 	cmd := &cobra.Command{
 		Use:   "login",
@@ -69,69 +78,101 @@ Provide the token with --token or pipe it on stdin.`,
 				return err
 			}
 
-			result, err := service.Login(cmd.Context(), tokenValue)
+			result, err := service.Login(cmd.Context(), profile, tokenValue)
 			if err != nil {
 				return err
 			}
 
 			if result.MembershipType != "" {
-				fmt.Printf("Cursor login successful (membership: %s)\n", result.MembershipType)
+				fmt.Printf("%s login successful (membership: %s)\n", cursorProfileLabel(profile), result.MembershipType)
 				return nil
 			}
-			fmt.Println("Cursor login successful")
+			fmt.Printf("%s login successful\n", cursorProfileLabel(profile))
 			return nil
 		},
 	}
 
 	cmd.Flags().StringVar(&token, "token", "", "Cursor WorkosCursorSessionToken")
+	addCursorProfileFlag(cmd, &profile)
 	return cmd
 }
 
 func newCursorStatusCommand(service cursorCommandService) *cobra.Command {
-	return &cobra.Command{
+	var profile string
+
+	cmd := &cobra.Command{
 		Use:   "status",
 		Short: "Check saved Cursor credentials and remote validity",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			status, err := service.Status(cmd.Context())
+			status, err := service.Status(cmd.Context(), profile)
 			if err != nil {
 				return err
 			}
 
+			label := cursorProfileLabel(profile)
 			if !status.HasCredentials {
-				fmt.Println("Cursor is not logged in")
+				fmt.Printf("%s is not logged in\n", label)
 				return nil
 			}
 
 			if status.RemoteValid {
 				if status.MembershipType != "" {
-					fmt.Printf("Cursor credentials saved and valid (membership: %s)\n", status.MembershipType)
+					fmt.Printf("%s credentials saved and valid (membership: %s)\n", label, status.MembershipType)
 					return nil
 				}
-				fmt.Println("Cursor credentials saved and valid")
+				fmt.Printf("%s credentials saved and valid\n", label)
 				return nil
 			}
 
 			if status.Message != "" {
-				fmt.Printf("Cursor credentials are saved locally but remote validation failed: %s\n", status.Message)
+				fmt.Printf("%s credentials are saved locally but remote validation failed: %s\n", label, status.Message)
 				return nil
 			}
-			fmt.Println("Cursor credentials are saved locally but remote validation failed")
+			fmt.Printf("%s credentials are saved locally but remote validation failed\n", label)
 			return nil
 		},
 	}
+
+	addCursorProfileFlag(cmd, &profile)
+	return cmd
 }
 
 func newCursorActivityCommand(service cursorCommandService) *cobra.Command {
 	var (
-		jsonOutput bool
-		dbPath     string
+		jsonOutput  bool
+		dbPath      string
+		sinceStr    string
+		untilStr    string
+		timezoneStr string
+		by          string
 	)
 
 	cmd := &cobra.Command{
 		Use:   "activity",
 		Short: "Show Cursor activity attribution from the local tracking database",
+		Long: `Show Cursor activity attribution from the local tracking database.
+
+Totals cover every scored commit unless --since/--until narrow them by commit date. --by day, --by branch, or --by commit adds a breakdown. Dates and days use the selected timezone like 'daily': --timezone accepts an IANA timezone name and defaults to local time.
+
+The tracking database does not record which repository a commit belongs to, so branch is the closest per-repository view.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			result, err := service.Activity(cmd.Context(), dbPath)
+			loc, err := resolveTimezone(timezoneStr)
+			if err != nil {
+				return err
+			}
+			query := cursorapi.ActivityQuery{Location: loc}
+			if sinceStr != "" || untilStr != "" {
+				query.Since, query.Until, err = resolveDailyDateRange(sinceStr, untilStr, 1, false, false, time.Now(), loc)
+				if err != nil {
+					return err
+				}
+			}
+			query.By, err = resolveCursorActivityBy(by)
+			if err != nil {
+				return err
+			}
+
+			result, err := service.Activity(cmd.Context(), dbPath, query)
 			if err != nil {
 				return err
 			}
@@ -149,41 +190,158 @@ func newCursorActivityCommand(service cursorCommandService) *cobra.Command {
 
 	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output Cursor activity attribution as JSON")
 	cmd.Flags().StringVar(&dbPath, "db-path", "", "Override Cursor tracking database path")
+	cmd.Flags().StringVar(&sinceStr, "since", "", "Start date filter (format: 2006-01-02)")
+	cmd.Flags().StringVar(&untilStr, "until", "", "End date filter (format: 2006-01-02)")
+	cmd.Flags().StringVar(&timezoneStr, "timezone", "", "Timezone for date filters and days (IANA name, default: local)")
+	cmd.Flags().StringVar(&by, "by", "", "Break attribution down by: day, branch, commit")
+	return cmd
+}
+
+func resolveCursorActivityBy(by string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(by)) {
+	case "":
+		return "", nil
+	case cursorapi.ActivityByDay:
+		return cursorapi.ActivityByDay, nil
+	case cursorapi.ActivityByBranch:
+		return cursorapi.ActivityByBranch, nil
+	case cursorapi.ActivityByCommit:
+		return cursorapi.ActivityByCommit, nil
+	case "repo", "repository":
+		return "", fmt.Errorf("invalid --by: %q (the Cursor tracking database does not record repositories; use branch or commit)", by)
+	default:
+		return "", fmt.Errorf("invalid --by: %q (allowed: day, branch, commit)", by)
+	}
+}
+
+func newCursorUsageCommand(service cursorCommandService) *cobra.Command {
+	var (
+		jsonOutput bool
+		profile    string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "usage",
+		Short: "Show the current Cursor billing cycle and remaining allowance",
+		Long: `Show the current Cursor billing cycle and remaining allowance.
+
+Reads Cursor's usage summary for the saved credential: the billing cycle window, how much of the included plan allowance is used, what remains, and any on-demand allowance. Amounts are reported in the units Cursor uses for the plan. Use --json to feed alerts.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			summary, err := service.Usage(cmd.Context(), profile)
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(summary)
+			}
+
+			printCursorUsage(cursorProfileLabel(profile), summary)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output the Cursor usage summary as JSON")
+	addCursorProfileFlag(cmd, &profile)
 	return cmd
 }
 
 func newCursorSyncCommand(service cursorCommandService) *cobra.Command {
-	return &cobra.Command{
+	var sinceStr, untilStr, profile string
+
+	cmd := &cobra.Command{
 		Use:   "sync",
-		Short: "Fetch Cursor dashboard CSV into the local cache",
+		Short: "Fetch Cursor dashboard CSV and merge it into the local ledger",
+		Long: `Fetch Cursor dashboard CSV and merge it into the local ledger.
+
+Each download is saved as a dated snapshot under ~/.codetok/cursor/snapshots/ and its rows are merged into ~/.codetok/cursor/synced/usage.csv; a named --profile uses the same layout under ~/.codetok/cursor/profiles/<name>/. Rows are matched on date, kind, model, and token columns, so rows that have aged out of the dashboard's export window stay in the ledger.
+
+Without --since/--until, sync fetches the dashboard's default export window. Use --since and --until (format: 2006-01-02, local time, both inclusive) to backfill a specific range such as a past billing month; ranges longer than 30 days are fetched in 30-day chunks. --since without --until runs up to now.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			result, err := service.Sync(cmd.Context())
+			usageRange, err := resolveCursorSyncRange(sinceStr, untilStr, time.Local)
+			if err != nil {
+				return err
+			}
+
+			result, err := service.Sync(cmd.Context(), profile, usageRange)
 			if err != nil {
 				return err
 			}
 
-			if result.Bytes > 0 {
-				fmt.Printf("Cursor sync complete: %s (%d bytes)\n", result.Path, result.Bytes)
+			if result.Path == "" {
+				fmt.Printf("%s sync complete: no usage in the requested range\n", cursorProfileLabel(profile))
 				return nil
 			}
-			fmt.Printf("Cursor sync complete: %s\n", result.Path)
+			fmt.Printf("%s sync complete: %s (%d new rows, %d already known, %d total)\n", cursorProfileLabel(profile), result.Path, result.NewRows, result.KnownRows, result.TotalRows)
+			if result.SnapshotPath != "" {
+				fmt.Printf("Snapshot saved: %s (%d bytes)\n", result.SnapshotPath, result.Bytes)
+			}
 			return nil
 		},
 	}
+
+	cmd.Flags().StringVar(&sinceStr, "since", "", "Fetch usage from this date (format: 2006-01-02)")
+	cmd.Flags().StringVar(&untilStr, "until", "", "Fetch usage through this date (format: 2006-01-02)")
+	addCursorProfileFlag(cmd, &profile)
+	return cmd
+}
+
+// resolveCursorSyncRange turns the sync date flags into an export range whose
+// Until is the start of the day after --until, so that day is included.
+func resolveCursorSyncRange(sinceStr, untilStr string, loc *time.Location) (cursorapi.UsageRange, error) {
+	var usageRange cursorapi.UsageRange
+	if sinceStr != "" {
+		since, err := time.ParseInLocation("2006-01-02", sinceStr, loc)
+		if err != nil {
+			return cursorapi.UsageRange{}, fmt.Errorf("invalid --since date: %w", err)
+		}
+		usageRange.Since = since
+	}
+	if untilStr != "" {
+		until, err := time.ParseInLocation("2006-01-02", untilStr, loc)
+		if err != nil {
+			return cursorapi.UsageRange{}, fmt.Errorf("invalid --until date: %w", err)
+		}
+		usageRange.Until = until.AddDate(0, 0, 1)
+	}
+	if !usageRange.Since.IsZero() && !usageRange.Until.IsZero() && !usageRange.Until.After(usageRange.Since) {
+		return cursorapi.UsageRange{}, fmt.Errorf("--since must not be after --until")
+	}
+	return usageRange, nil
 }
 
 func newCursorLogoutCommand(service cursorCommandService) *cobra.Command {
-	return &cobra.Command{
+	var profile string
+
+	cmd := &cobra.Command{
 		Use:   "logout",
 		Short: "Remove saved Cursor credentials",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := service.Logout(); err != nil {
+			if err := service.Logout(profile); err != nil {
 				return err
 			}
-			fmt.Println("Cursor logged out")
+			fmt.Printf("%s logged out\n", cursorProfileLabel(profile))
 			return nil
 		},
 	}
+
+	addCursorProfileFlag(cmd, &profile)
+	return cmd
+}
+
+func addCursorProfileFlag(cmd *cobra.Command, profile *string) {
+	cmd.Flags().StringVar(profile, "profile", "", "Cursor profile to use (default: "+cursorapi.DefaultProfile+")")
+}
+
+// cursorProfileLabel names the profile in command output; the default profile
+// keeps the plain "Cursor" wording.
+func cursorProfileLabel(profile string) string {
+	if profile == "" || profile == cursorapi.DefaultProfile {
+		return "Cursor"
+	}
+	return fmt.Sprintf("Cursor profile %q", profile)
 }
 
 func resolveCursorToken(cmd *cobra.Command, flagValue string) (string, error) {
@@ -231,4 +389,74 @@ func printCursorActivity(result cursorapi.ActivityResult) {
 	fmt.Fprintf(w, "composer\t%d\t%d\n", result.Composer.LinesAdded, result.Composer.LinesDeleted)
 	fmt.Fprintf(w, "tab\t%d\t%d\n", result.Tab.LinesAdded, result.Tab.LinesDeleted)
 	_ = w.Flush()
+
+	if len(result.Groups) == 0 {
+		return
+	}
+	fmt.Println()
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	header := cursorActivityGroupTitle(result.By) + "\tCommits\tComposer Added\tComposer Deleted\tTab Added\tTab Deleted"
+	if result.By == cursorapi.ActivityByCommit {
+		header += "\tBranch\tMessage"
+	}
+	fmt.Fprintln(w, header)
+	for _, group := range result.Groups {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%d",
+			group.Key,
+			group.ScoredCommits,
+			group.Composer.LinesAdded,
+			group.Composer.LinesDeleted,
+			group.Tab.LinesAdded,
+			group.Tab.LinesDeleted,
+		)
+		if result.By == cursorapi.ActivityByCommit {
+			fmt.Fprintf(w, "\t%s\t%s", group.Branch, group.Message)
+		}
+		fmt.Fprintln(w)
+	}
+	_ = w.Flush()
+}
+
+func cursorActivityGroupTitle(by string) string {
+	switch by {
+	case cursorapi.ActivityByDay:
+		return "Day"
+	case cursorapi.ActivityByBranch:
+		return "Branch"
+	default:
+		return "Commit"
+	}
+}
+
+func printCursorUsage(label string, summary cursorapi.UsageSummary) {
+	if summary.MembershipType != "" {
+		fmt.Printf("%s usage (membership: %s)\n", label, summary.MembershipType)
+	} else {
+		fmt.Printf("%s usage\n", label)
+	}
+	fmt.Printf("Billing cycle: %s to %s (%s left)\n",
+		summary.BillingCycleStart.Local().Format("2006-01-02"),
+		summary.BillingCycleEnd.Local().Format("2006-01-02"),
+		pluralCount(summary.CycleDaysRemaining, "day"),
+	)
+	fmt.Printf("Included: %s\n", formatCursorAllowance(summary.Included))
+	fmt.Printf("On-demand: %s\n", formatCursorAllowance(summary.OnDemand))
+}
+
+func formatCursorAllowance(a cursorapi.UsageAllowance) string {
+	if !a.Enabled {
+		return "disabled"
+	}
+	used := strconv.FormatFloat(a.Used, 'f', -1, 64)
+	if a.Limit == nil {
+		return used + " used (no limit)"
+	}
+	out := fmt.Sprintf("%s of %s used", used, strconv.FormatFloat(*a.Limit, 'f', -1, 64))
+	if a.Remaining != nil {
+		out += fmt.Sprintf(", %s remaining", strconv.FormatFloat(*a.Remaining, 'f', -1, 64))
+	}
+	if a.UsedPercent != nil {
+		out += fmt.Sprintf(" (%.1f%%)", *a.UsedPercent)
+	}
+	return out
 }
@@ -0,0 +1,227 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	cursorapi "github.com/miss-you/codetok/cursor"
+	"github.com/miss-you/codetok/provider"
+	"github.com/miss-you/codetok/stats"
+)
+
+const defaultEfficiencyDays = 30
+
+// cursorEfficiencyRow lines up one day of Cursor token usage with the lines
+// Cursor attributes to composer and tab for commits dated that day. AILines
+// counts lines added by either source; the ratios are nil on days without
+// AI-authored lines or, for cost, without priced usage.
+type cursorEfficiencyRow struct {
+	Date            string                   `json:"date"`
+	TokenUsage      provider.TokenUsage      `json:"token_usage"`
+	Cost            provider.CostEstimate    `json:"cost"`
+	ScoredCommits   int                      `json:"scored_commits"`
+	Composer        cursorapi.ActivityMetric `json:"composer"`
+	Tab             cursorapi.ActivityMetric `json:"tab"`
+	AILines         int                      `json:"ai_lines"`
+	TokensPerAILine *float64                 `json:"tokens_per_ai_line"`
+	CostPerAILine   *float64                 `json:"cost_per_ai_line_usd"`
+}
+
+type cursorEfficiencyReport struct {
+	Days  []cursorEfficiencyRow `json:"days"`
+	Total cursorEfficiencyRow   `json:"total"`
+}
+
+func newCursorEfficiencyCommand(service cursorCommandService) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "efficiency",
+		Short: "Compare Cursor token usage with AI-authored lines per day",
+		Long: `Compare Cursor token usage with AI-authored lines per day.
+
+Joins the daily totals of local Cursor usage CSVs with the composer and tab lines from the local tracking database, and reports tokens and estimated cost per AI-authored line (lines added by composer or tab). Lines are dated by commit, tokens by request, so single days can be skewed by work committed later; longer ranges and the total row are more meaningful.
+
+Token accounting is the same as 'daily --provider cursor'. This command reads only local files.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCursorEfficiency(cmd, service, provider.Registry(), time.Now())
+		},
+	}
+
+	cmd.Flags().Bool("json", false, "Output as JSON")
+	cmd.Flags().String("since", "", "Start date filter (format: 2006-01-02)")
+	cmd.Flags().String("until", "", "End date filter (format: 2006-01-02)")
+	cmd.Flags().Int("days", defaultEfficiencyDays, "Lookback window in days when --since/--until are not set")
+	cmd.Flags().Bool("all", false, "Include all history")
+	cmd.Flags().String("timezone", "", "Timezone for date filters and days (IANA name, default: local)")
+	cmd.Flags().String("db-path", "", "Override Cursor tracking database path")
+	cmd.Flags().String("cursor-dir", "", cursorDirFlagUsage)
+	cmd.Flags().String("pricing-file", "", pricingFileFlagUsage)
+	cmd.Flags().Bool("no-cache", false, noCacheFlagUsage)
+	cmd.Flags().Bool("strict", false, strictFlagUsage)
+	cmd.Flags().Bool("diagnostics", false, diagnosticsFlagUsage)
+	return cmd
+}
+
+func runCursorEfficiency(cmd *cobra.Command, service cursorCommandService, providers []provider.Provider, now time.Time) error {
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	sinceStr, _ := cmd.Flags().GetString("since")
+	untilStr, _ := cmd.Flags().GetString("until")
+	days, _ := cmd.Flags().GetInt("days")
+	allHistory, _ := cmd.Flags().GetBool("all")
+	timezoneStr, _ := cmd.Flags().GetString("timezone")
+	dbPath, _ := cmd.Flags().GetString("db-path")
+
+	loc, err := resolveTimezone(timezoneStr)
+	if err != nil {
+		return err
+	}
+	prices, err := resolvePricingTable(cmd)
+	if err != nil {
+		return err
+	}
+	since, until, err := resolveDailyDateRange(sinceStr, untilStr, days, allHistory, cmd.Flags().Changed("days"), now, loc)
+	if err != nil {
+		return err
+	}
+
+	activity, err := service.Activity(cmd.Context(), dbPath, cursorapi.ActivityQuery{
+		Since:    since,
+		Until:    until,
+		Location: loc,
+		By:       cursorapi.ActivityByDay,
+	})
+	if err != nil {
+		return err
+	}
+
+	collectOpts := provider.UsageEventCollectOptions{
+		Since:    since,
+		Until:    until,
+		Location: loc,
+	}
+	sinceDate, untilDate := dailyEventFilterDates(since, until, loc)
+	aggregator := stats.NewDailyEventAggregator(stats.AggregateDimensionCLI, loc)
+	aggregator.SetPricing(prices)
+	usage, err := aggregateUsageEventsFromProvidersInRange(cmd, provider.FilterProviders(providers, "cursor"), collectOpts, aggregator, loc, sinceDate, untilDate)
+	if err != nil {
+		return err
+	}
+
+	report := buildCursorEfficiencyReport(usage, activity)
+	if jsonOutput {
+		if report.Days == nil {
+			report.Days = []cursorEfficiencyRow{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+
+	printCursorEfficiency(report)
+	return nil
+}
+
+// buildCursorEfficiencyReport joins daily Cursor usage with daily activity
+// groups. Activity without a commit date cannot be placed on a day and is left
+// out.
+func buildCursorEfficiencyReport(usage []provider.DailyStats, activity cursorapi.ActivityResult) cursorEfficiencyReport {
+	rows := make(map[string]*cursorEfficiencyRow)
+	row := func(date string) *cursorEfficiencyRow {
+		r, ok := rows[date]
+		if !ok {
+			r = &cursorEfficiencyRow{Date: date}
+			rows[date] = r
+		}
+		return r
+	}
+	for _, day := range usage {
+		r := row(day.Date)
+		mergeTokenUsage(&r.TokenUsage, day.TokenUsage)
+		r.Cost.Add(day.Cost)
+	}
+	for _, group := range activity.Groups {
+		if group.Key == "unknown" {
+			continue
+		}
+		r := row(group.Key)
+		r.ScoredCommits += group.ScoredCommits
+		r.Composer.LinesAdded += group.Composer.LinesAdded
+		r.Composer.LinesDeleted += group.Composer.LinesDeleted
+		r.Tab.LinesAdded += group.Tab.LinesAdded
+		r.Tab.LinesDeleted += group.Tab.LinesDeleted
+	}
+
+	var report cursorEfficiencyReport
+	report.Total.Date = "total"
+	for _, r := range rows {
+		r.fillRatios()
+		report.Days = append(report.Days, *r)
+
+		mergeTokenUsage(&report.Total.TokenUsage, r.TokenUsage)
+		report.Total.Cost.Add(r.Cost)
+		report.Total.ScoredCommits += r.ScoredCommits
+		report.Total.Composer.LinesAdded += r.Composer.LinesAdded
+		report.Total.Composer.LinesDeleted += r.Composer.LinesDeleted
+		report.Total.Tab.LinesAdded += r.Tab.LinesAdded
+		report.Total.Tab.LinesDeleted += r.Tab.LinesDeleted
+	}
+	report.Total.fillRatios()
+	sort.Slice(report.Days, func(i, j int) bool {
+		return report.Days[i].Date < report.Days[j].Date
+	})
+	return report
+}
+
+func (r *cursorEfficiencyRow) fillRatios() {
+	r.AILines = r.Composer.LinesAdded + r.Tab.LinesAdded
+	r.TokensPerAILine, r.CostPerAILine = nil, nil
+	if r.AILines <= 0 {
+		return
+	}
+	tokens := float64(r.TokenUsage.Total()) / float64(r.AILines)
+	r.TokensPerAILine = &tokens
+	if r.Cost.Status == provider.CostStatusKnown || r.Cost.Status == provider.CostStatusPartial {
+		cost := r.Cost.USD / float64(r.AILines)
+		r.CostPerAILine = &cost
+	}
+}
+
+func printCursorEfficiency(report cursorEfficiencyReport) {
+	fmt.Println("Cursor Efficiency")
+	if len(report.Days) == 0 {
+		fmt.Println("No Cursor usage or activity attribution data found.")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Date\tTokens\tCost\tCommits\tAI Lines\tTokens/Line\tCost/Line")
+	for _, r := range append(report.Days, report.Total) {
+		date := r.Date
+		if date == "total" {
+			date = "TOTAL"
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t%d\t%d\t%s\t%s\n",
+			date,
+			r.TokenUsage.Total(),
+			formatCost(r.Cost),
+			r.ScoredCommits,
+			r.AILines,
+			formatOptionalRatio(r.TokensPerAILine, "%.0f"),
+			formatOptionalRatio(r.CostPerAILine, "$%.4f"),
+		)
+	}
+	_ = w.Flush()
+	printUnpricedModelsNote(os.Stdout, report.Total.Cost)
+}
+
+func formatOptionalRatio(value *float64, format string) string {
+	if value == nil {
+		return "-"
+	}
+	return fmt.Sprintf(format, *value)
+}
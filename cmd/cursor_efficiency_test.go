@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	cursorapi "github.com/miss-you/codetok/cursor"
+	"github.com/miss-you/codetok/provider"
+)
+
+func newCursorEfficiencyTestInputs() (*stubCursorCommandService, []provider.Provider) {
+	svc := &stubCursorCommandService{
+		activityResult: cursorapi.ActivityResult{
+			HasData: true,
+			By:      cursorapi.ActivityByDay,
+			Groups: []cursorapi.ActivityGroup{
+				{Key: "2026-04-15", ScoredCommits: 2, Composer: cursorapi.ActivityMetric{LinesAdded: 80, LinesDeleted: 5}, Tab: cursorapi.ActivityMetric{LinesAdded: 20}},
+				{Key: "2026-04-17", ScoredCommits: 1, Tab: cursorapi.ActivityMetric{LinesAdded: 10}},
+				{Key: "unknown", ScoredCommits: 4, Composer: cursorapi.ActivityMetric{LinesAdded: 999}},
+			},
+		},
+	}
+	cursorEvents := &collectTestUsageEventProvider{
+		collectTestProvider: collectTestProvider{name: "cursor"},
+		events: []provider.UsageEvent{
+			{ProviderName: "cursor", ModelName: "custom-model", SessionID: "usage:1", Timestamp: time.Date(2026, 4, 15, 9, 0, 0, 0, time.UTC), TokenUsage: provider.TokenUsage{InputOther: 900, Output: 100}},
+			{ProviderName: "cursor", ModelName: "custom-model", SessionID: "usage:2", Timestamp: time.Date(2026, 4, 16, 9, 0, 0, 0, time.UTC), TokenUsage: provider.TokenUsage{InputOther: 400, Output: 100}},
+		},
+	}
+	otherEvents := &collectTestUsageEventProvider{
+		collectTestProvider: collectTestProvider{name: "codex"},
+		events: []provider.UsageEvent{
+			{ProviderName: "codex", ModelName: "gpt-5.4", SessionID: "codex", Timestamp: time.Date(2026, 4, 15, 9, 0, 0, 0, time.UTC), TokenUsage: provider.TokenUsage{InputOther: 50000}},
+		},
+	}
+	return svc, []provider.Provider{cursorEvents, otherEvents}
+}
+
+func TestCursorEfficiency_JoinsUsageAndActivityPerDay(t *testing.T) {
+	svc, providers := newCursorEfficiencyTestInputs()
+	cmd := newCursorEfficiencyCommand(svc)
+	if err := cmd.ParseFlags([]string{"--json", "--since", "2026-04-15", "--until", "2026-04-17", "--timezone", "UTC"}); err != nil {
+		t.Fatalf("parsing flags: %v", err)
+	}
+
+	output := captureStdout(t, func() {
+		if err := runCursorEfficiency(cmd, svc, providers, time.Date(2026, 4, 20, 0, 0, 0, 0, time.UTC)); err != nil {
+			t.Fatalf("efficiency failed: %v", err)
+		}
+	})
+
+	if svc.activityQuery.By != cursorapi.ActivityByDay || svc.activityQuery.Location != time.UTC {
+		t.Fatalf("activity query = %+v, want day breakdown in UTC", svc.activityQuery)
+	}
+
+	var report cursorEfficiencyReport
+	if err := json.Unmarshal([]byte(output), &report); err != nil {
+		t.Fatalf("decoding json: %v\n%s", err, output)
+	}
+	if len(report.Days) != 3 {
+		t.Fatalf("days = %+v, want 2026-04-15..17 without the unknown commit date", report.Days)
+	}
+
+	first := report.Days[0]
+	if first.Date != "2026-04-15" || first.TokenUsage.Total() != 1000 || first.AILines != 100 || first.ScoredCommits != 2 {
+		t.Fatalf("first day = %+v, want cursor-only tokens joined with 100 AI lines", first)
+	}
+	if first.TokensPerAILine == nil || *first.TokensPerAILine != 10 {
+		t.Fatalf("first day tokens per line = %v, want 10", first.TokensPerAILine)
+	}
+	if first.CostPerAILine != nil {
+		t.Fatalf("first day cost per line = %v, want nil for unpriced usage", *first.CostPerAILine)
+	}
+	if noLines := report.Days[1]; noLines.Date != "2026-04-16" || noLines.TokensPerAILine != nil {
+		t.Fatalf("second day = %+v, want usage without a ratio", noLines)
+	}
+	if noTokens := report.Days[2]; noTokens.Date != "2026-04-17" || noTokens.TokensPerAILine == nil || *noTokens.TokensPerAILine != 0 {
+		t.Fatalf("third day = %+v, want lines with zero tokens per line", noTokens)
+	}
+	if report.Total.TokenUsage.Total() != 1500 || report.Total.AILines != 110 ||
+		report.Total.TokensPerAILine == nil || *report.Total.TokensPerAILine != 1500.0/110 {
+		t.Fatalf("total = %+v, want 1500 tokens over 110 lines", report.Total)
+	}
+}
+
+func TestCursorEfficiency_TablePrintsRatiosAndTotal(t *testing.T) {
+	svc, providers := newCursorEfficiencyTestInputs()
+	cmd := newCursorEfficiencyCommand(svc)
+	if err := cmd.ParseFlags([]string{"--since", "2026-04-15", "--until", "2026-04-17", "--timezone", "UTC"}); err != nil {
+		t.Fatalf("parsing flags: %v", err)
+	}
+
+	output := captureStdout(t, func() {
+		if err := runCursorEfficiency(cmd, svc, providers, time.Date(2026, 4, 20, 0, 0, 0, 0, time.UTC)); err != nil {
+			t.Fatalf("efficiency failed: %v", err)
+		}
+	})
+
+	assertContainsAll(t, output, "Cursor Efficiency", "Tokens/Line", "Cost/Line", "2026-04-15", "TOTAL", "14")
+	if strings.Contains(output, "51000") {
+		t.Fatalf("efficiency output should only count cursor tokens:\n%s", output)
+	}
+}
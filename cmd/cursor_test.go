@@ -2,8 +2,11 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
+	"io"
 	"strings"
 	"testing"
+	"time"
 
 	cursorapi "github.com/miss-you/codetok/cursor"
 )
@@ -15,34 +18,50 @@ type stubCursorCommandService struct {
 	statusErr      error
 	activityResult cursorapi.ActivityResult
 	activityErr    error
+	usageResult    cursorapi.UsageSummary
+	usageErr       error
 	syncResult     cursorapi.SyncResult
 	syncErr        error
 	logoutErr      error
 
 	loginToken     string
 	activityDBPath string
+	activityQuery  cursorapi.ActivityQuery
+	syncRange      cursorapi.UsageRange
+	profile        string
 	logoutDone     bool
 }
 
-func (s *stubCursorCommandService) Login(_ context.Context, token string) (cursorapi.ValidationResult, error) {
+func (s *stubCursorCommandService) Login(_ context.Context, profile, token string) (cursorapi.ValidationResult, error) {
+	s.profile = profile
 	s.loginToken = token
 	return s.loginResult, s.loginErr
 }
 
-func (s *stubCursorCommandService) Status(context.Context) (cursorapi.StatusResult, error) {
+func (s *stubCursorCommandService) Status(_ context.Context, profile string) (cursorapi.StatusResult, error) {
+	s.profile = profile
 	return s.statusResult, s.statusErr
 }
 
-func (s *stubCursorCommandService) Activity(_ context.Context, dbPath string) (cursorapi.ActivityResult, error) {
+func (s *stubCursorCommandService) Activity(_ context.Context, dbPath string, query cursorapi.ActivityQuery) (cursorapi.ActivityResult, error) {
 	s.activityDBPath = dbPath
+	s.activityQuery = query
 	return s.activityResult, s.activityErr
 }
 
-func (s *stubCursorCommandService) Sync(context.Context) (cursorapi.SyncResult, error) {
+func (s *stubCursorCommandService) Usage(_ context.Context, profile string) (cursorapi.UsageSummary, error) {
+	s.profile = profile
+	return s.usageResult, s.usageErr
+}
+
+func (s *stubCursorCommandService) Sync(_ context.Context, profile string, r cursorapi.UsageRange) (cursorapi.SyncResult, error) {
+	s.profile = profile
+	s.syncRange = r
 	return s.syncResult, s.syncErr
 }
 
-func (s *stubCursorCommandService) Logout() error {
+func (s *stubCursorCommandService) Logout(profile string) error {
+	s.profile = profile
 	s.logoutDone = true
 	return s.logoutErr
 }
@@ -55,7 +74,7 @@ func TestNewCursorCommand_IncludesLifecycleSubcommands(t *testing.T) {
 		got[child.Name()] = true
 	}
 
-	for _, name := range []string{"login", "status", "activity", "sync", "logout"} {
+	for _, name := range []string{"login", "status", "activity", "usage", "sync", "logout"} {
 		if !got[name] {
 			t.Fatalf("expected subcommand %q to be registered", name)
 		}
@@ -120,7 +139,14 @@ func TestCursorStatusCommand_PrintsLoggedOutState(t *testing.T) {
 
 func TestCursorSyncCommand_PrintsSyncedPath(t *testing.T) {
 	svc := &stubCursorCommandService{
-		syncResult: cursorapi.SyncResult{Path: "/tmp/cursor/synced/usage.csv", Bytes: 128},
+		syncResult: cursorapi.SyncResult{
+			Path:         "/tmp/cursor/synced/usage.csv",
+			SnapshotPath: "/tmp/cursor/snapshots/usage-20260417T101500Z.csv",
+			Bytes:        128,
+			NewRows:      3,
+			KnownRows:    40,
+			TotalRows:    95,
+		},
 	}
 	cmd := newCursorCommand(svc)
 	cmd.SetArgs([]string{"sync"})
@@ -134,6 +160,155 @@ func TestCursorSyncCommand_PrintsSyncedPath(t *testing.T) {
 	if !strings.Contains(output, "/tmp/cursor/synced/usage.csv") {
 		t.Fatalf("sync output = %q, want synced path", output)
 	}
+	assertContainsAll(t, output,
+		"3 new rows, 40 already known, 95 total",
+		"/tmp/cursor/snapshots/usage-20260417T101500Z.csv",
+	)
+}
+
+func TestCursorCommands_PassProfile(t *testing.T) {
+	for _, args := range [][]string{
+		{"login", "--profile", "work", "--token", "token-123"},
+		{"status", "--profile", "work"},
+		{"sync", "--profile", "work"},
+		{"logout", "--profile", "work"},
+	} {
+		svc := &stubCursorCommandService{
+			loginResult:  cursorapi.ValidationResult{Valid: true},
+			statusResult: cursorapi.StatusResult{HasCredentials: true, RemoteValid: true},
+		}
+		cmd := newCursorCommand(svc)
+		cmd.SetArgs(args)
+
+		output := captureStdout(t, func() {
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("%s command failed: %v", args[0], err)
+			}
+		})
+		if svc.profile != "work" {
+			t.Fatalf("%s passed profile %q, want work", args[0], svc.profile)
+		}
+		if !strings.Contains(output, `Cursor profile "work"`) {
+			t.Fatalf("%s output = %q, want profile named", args[0], output)
+		}
+	}
+}
+
+func TestCursorUsageCommand_PrintsAllowanceAndJSON(t *testing.T) {
+	limit, remaining, percent := 500.0, 350.0, 30.0
+	svc := &stubCursorCommandService{
+		usageResult: cursorapi.UsageSummary{
+			Profile:            "work",
+			MembershipType:     "pro",
+			BillingCycleStart:  time.Date(2026, 4, 1, 12, 0, 0, 0, time.Local),
+			BillingCycleEnd:    time.Date(2026, 5, 1, 12, 0, 0, 0, time.Local),
+			CycleDaysRemaining: 12,
+			Included:           cursorapi.UsageAllowance{Enabled: true, Used: 150, Limit: &limit, Remaining: &remaining, UsedPercent: &percent},
+		},
+	}
+
+	cmd := newCursorCommand(svc)
+	cmd.SetArgs([]string{"usage", "--profile", "work"})
+	output := captureStdout(t, func() {
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("usage command failed: %v", err)
+		}
+	})
+	assertContainsAll(t, output,
+		`Cursor profile "work" usage (membership: pro)`,
+		"Billing cycle: 2026-04-01 to 2026-05-01 (12 days left)",
+		"Included: 150 of 500 used, 350 remaining (30.0%)",
+		"On-demand: disabled",
+	)
+
+	cmd = newCursorCommand(svc)
+	cmd.SetArgs([]string{"usage", "--json"})
+	output = captureStdout(t, func() {
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("usage --json command failed: %v", err)
+		}
+	})
+	var decoded map[string]any
+	if err := json.Unmarshal([]byte(output), &decoded); err != nil {
+		t.Fatalf("usage --json output is not JSON: %v\n%s", err, output)
+	}
+	included, _ := decoded["included"].(map[string]any)
+	if decoded["cycle_days_remaining"] != 12.0 || included["remaining"] != 350.0 || included["used_percent"] != 30.0 {
+		t.Fatalf("usage --json = %s, want days remaining and included allowance", output)
+	}
+}
+
+func TestCursorSyncCommand_PassesDateRange(t *testing.T) {
+	svc := &stubCursorCommandService{}
+	cmd := newCursorCommand(svc)
+	cmd.SetArgs([]string{"sync", "--since", "2026-01-01", "--until", "2026-01-31"})
+
+	captureStdout(t, func() {
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("sync command failed: %v", err)
+		}
+	})
+
+	wantSince := time.Date(2026, 1, 1, 0, 0, 0, 0, time.Local)
+	wantUntil := time.Date(2026, 2, 1, 0, 0, 0, 0, time.Local)
+	if !svc.syncRange.Since.Equal(wantSince) || !svc.syncRange.Until.Equal(wantUntil) {
+		t.Fatalf("sync range = %+v, want %s to %s", svc.syncRange, wantSince, wantUntil)
+	}
+}
+
+func TestResolveCursorSyncRange_RejectsInvalidDates(t *testing.T) {
+	for _, tc := range []struct {
+		since, until, want string
+	}{
+		{since: "2026/01/01", want: "invalid --since"},
+		{until: "tomorrow", want: "invalid --until"},
+		{since: "2026-02-01", until: "2026-01-31", want: "--since must not be after --until"},
+	} {
+		_, err := resolveCursorSyncRange(tc.since, tc.until, time.UTC)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("resolveCursorSyncRange(%q, %q) error = %v, want %q", tc.since, tc.until, err, tc.want)
+		}
+	}
+}
+
+func TestCursorActivityCommand_PassesRangeAndPrintsBreakdown(t *testing.T) {
+	svc := &stubCursorCommandService{
+		activityResult: cursorapi.ActivityResult{
+			HasData:       true,
+			ScoredCommits: 3,
+			Composer:      cursorapi.ActivityMetric{LinesAdded: 22},
+			By:            cursorapi.ActivityByDay,
+			Groups: []cursorapi.ActivityGroup{
+				{Key: "2026-04-15", ScoredCommits: 2, Composer: cursorapi.ActivityMetric{LinesAdded: 15}},
+				{Key: "2026-04-16", ScoredCommits: 1, Composer: cursorapi.ActivityMetric{LinesAdded: 7}},
+			},
+		},
+	}
+	cmd := newCursorCommand(svc)
+	cmd.SetArgs([]string{"activity", "--since", "2026-04-15", "--until", "2026-04-16", "--timezone", "UTC", "--by", "day"})
+
+	output := captureStdout(t, func() {
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("activity command failed: %v", err)
+		}
+	})
+
+	query := svc.activityQuery
+	if query.By != cursorapi.ActivityByDay || query.Location != time.UTC {
+		t.Fatalf("activity query = %+v, want day breakdown in UTC", query)
+	}
+	if !query.Since.Equal(time.Date(2026, 4, 15, 0, 0, 0, 0, time.UTC)) ||
+		!query.Until.Equal(time.Date(2026, 4, 17, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond)) {
+		t.Fatalf("activity range = %s to %s, want both days included", query.Since, query.Until)
+	}
+	assertContainsAll(t, output, "Day", "Composer Added", "2026-04-15", "2026-04-16")
+
+	cmd = newCursorCommand(&stubCursorCommandService{})
+	cmd.SetArgs([]string{"activity", "--by", "repo"})
+	cmd.SetErr(io.Discard)
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "branch") {
+		t.Fatalf("activity --by repo error = %v, want pointer to branch breakdown", err)
+	}
 }
 
 func TestCursorActivityCommand_UsesDBPathOverrideAndPrintsJSON(t *testing.T) {
@@ -13,11 +13,14 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/miss-you/codetok/pricing"
 	"github.com/miss-you/codetok/provider"
 	_ "github.com/miss-you/codetok/provider/claude"
 	_ "github.com/miss-you/codetok/provider/codex"
 	_ "github.com/miss-you/codetok/provider/cursor"
+	_ "github.com/miss-you/codetok/provider/gemini"
 	_ "github.com/miss-you/codetok/provider/kimi"
+	_ "github.com/miss-you/codetok/provider/opencode"
 	"github.com/miss-you/codetok/stats"
 )
 
@@ -32,6 +35,10 @@ Reporting commands read only local session files and Cursor CSV exports already
 
 Codex reads $CODEX_HOME/sessions when CODEX_HOME is set, otherwise ~/.codex/sessions.
 
+OpenCode reads $XDG_DATA_HOME/opencode/storage when XDG_DATA_HOME is set, otherwise ~/.local/share/opencode/storage.
+
+Gemini CLI reads the chat recordings in ~/.gemini/tmp/<project-hash>/chats/. Checkpoints (/chat save and --checkpointing) store conversation history without token counts, so they are not read.
+
 By default Cursor reporting scans legacy CSV files in ~/.codetok/cursor/ plus imports/ and synced/ subdirectories. Use --cursor-dir to scan only a custom local directory.`,
 	RunE: runDaily,
 }
@@ -40,26 +47,26 @@ const defaultDailyDays = 7
 const defaultTokenUnit = "m"
 const defaultGroupBy = "cli"
 const defaultTopN = 5
+const groupByFlagUsage = "Group by dimension for aggregation: cli, model, project, account; comma-separate to group by several (e.g. cli,model)"
 
 func init() {
-	dailyCmd.Flags().Bool("json", false, "Output as JSON")
-	dailyCmd.Flags().String("since", "", "Start date filter (format: 2006-01-02)")
-	dailyCmd.Flags().String("until", "", "End date filter (format: 2006-01-02)")
-	dailyCmd.Flags().Int("days", defaultDailyDays, "Lookback window in days when --since/--until are not set")
-	dailyCmd.Flags().Bool("all", false, "Include all historical sessions")
-	dailyCmd.Flags().String("timezone", "", "Timezone for date filters (IANA name, default: local)")
-	dailyCmd.Flags().String("unit", defaultTokenUnit, "Token display unit for dashboard output: raw, k, m, g")
-	dailyCmd.Flags().String("group-by", defaultGroupBy, "Group by dimension for aggregation: cli, model")
-	dailyCmd.Flags().Int("top", defaultTopN, "Top N groups to show in dashboard share section")
-	dailyCmd.Flags().String("provider", "", "Filter by provider name (e.g. kimi, claude, codex, cursor)")
-	dailyCmd.Flags().String("base-dir", "", "Override default data directory (applies to all providers)")
-	dailyCmd.Flags().String("kimi-dir", "", "Override Kimi data directory")
-	dailyCmd.Flags().String("claude-dir", "", "Override Claude Code data directory")
-	dailyCmd.Flags().String("codex-dir", "", "Override Codex CLI data directory")
-	dailyCmd.Flags().String("cursor-dir", "", "Override Cursor CSV directory; scans only this local path and skips default Cursor imports/synced roots")
+	addDailyFlags(dailyCmd)
 	rootCmd.AddCommand(dailyCmd)
 }
 
+func addDailyFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("json", false, "Output as JSON")
+	cmd.Flags().String("since", "", "Start date filter (format: 2006-01-02)")
+	cmd.Flags().String("until", "", "End date filter (format: 2006-01-02)")
+	cmd.Flags().Int("days", defaultDailyDays, "Lookback window in days when --since/--until are not set")
+	cmd.Flags().Bool("all", false, "Include all historical sessions")
+	cmd.Flags().String("timezone", "", "Timezone for date filters (IANA name, default: local)")
+	cmd.Flags().String("unit", defaultTokenUnit, "Token display unit for dashboard output: raw, k, m, g")
+	cmd.Flags().String("group-by", defaultGroupBy, groupByFlagUsage)
+	cmd.Flags().Int("top", defaultTopN, "Top N groups to show in dashboard share section")
+	addUsageReportFlags(cmd)
+}
+
 // providerDirFlag returns the per-provider directory override flag name.
 func providerDirFlag(name string) string {
 	return name + "-dir"
@@ -90,6 +97,10 @@ func runDailyWithProviders(cmd *cobra.Command, args []string, providers []provid
 	if err != nil {
 		return err
 	}
+	prices, err := resolvePricingTable(cmd)
+	if err != nil {
+		return err
+	}
 
 	since, until, err := resolveDailyDateRange(
 		sinceStr,
@@ -110,7 +121,7 @@ func runDailyWithProviders(cmd *cobra.Command, args []string, providers []provid
 		Location: loc,
 	}
 	sinceDate, untilDate := dailyEventFilterDates(since, until, loc)
-	daily, err := aggregateDailyUsageEventsFromProvidersInRange(cmd, providers, collectOpts, groupBy, loc, sinceDate, untilDate)
+	daily, err := aggregateDailyUsageEventsFromProvidersInRange(cmd, providers, collectOpts, groupBy, loc, sinceDate, untilDate, prices)
 	if err != nil {
 		return err
 	}
@@ -140,8 +151,22 @@ func aggregateDailyUsageEventsFromProvidersInRange(
 	groupBy stats.AggregateDimension,
 	loc *time.Location,
 	sinceDate, untilDate string,
+	prices *pricing.Table,
 ) ([]provider.DailyStats, error) {
 	aggregator := stats.NewDailyEventAggregator(groupBy, loc)
+	aggregator.SetPricing(prices)
+	return aggregateUsageEventsFromProvidersInRange(cmd, providers, opts, aggregator, loc, sinceDate, untilDate)
+}
+
+// aggregateUsageEventsFromProvidersInRange streams in-range events into a preconfigured aggregator.
+func aggregateUsageEventsFromProvidersInRange(
+	cmd *cobra.Command,
+	providers []provider.Provider,
+	opts provider.UsageEventCollectOptions,
+	aggregator *stats.DailyEventAggregator,
+	loc *time.Location,
+	sinceDate, untilDate string,
+) ([]provider.DailyStats, error) {
 	dateFilter := stats.NewEventDateRangeFilter(sinceDate, untilDate, loc)
 	err := forEachUsageEventFromProvidersInRange(cmd, providers, opts, func(event provider.UsageEvent) error {
 		if dateFilter.Contains(event) {
@@ -181,17 +206,38 @@ func resolveTimezone(timezone string) (*time.Location, error) {
 	return loc, nil
 }
 
+// resolveGroupBy parses --group-by as one dimension or a comma-separated list
+// of dimensions grouped as a tuple (e.g. "cli,model").
 func resolveGroupBy(groupBy string) (stats.AggregateDimension, error) {
-	switch strings.ToLower(strings.TrimSpace(groupBy)) {
-	case "":
-		return stats.AggregateDimensionCLI, nil
-	case "model":
-		return stats.AggregateDimensionModel, nil
-	case "cli":
+	if strings.TrimSpace(groupBy) == "" {
 		return stats.AggregateDimensionCLI, nil
-	default:
-		return "", fmt.Errorf("invalid --group-by: %q (allowed: model, cli)", groupBy)
 	}
+	var dimensions []stats.AggregateDimension
+	for _, part := range strings.Split(groupBy, ",") {
+		var dimension stats.AggregateDimension
+		switch strings.ToLower(strings.TrimSpace(part)) {
+		case "model":
+			dimension = stats.AggregateDimensionModel
+		case "cli":
+			dimension = stats.AggregateDimensionCLI
+		case "project":
+			dimension = stats.AggregateDimensionProject
+		case "account":
+			dimension = stats.AggregateDimensionAccount
+		default:
+			return "", fmt.Errorf("invalid --group-by: %q (allowed: model, cli, project, account, or a comma-separated list such as cli,model)", groupBy)
+		}
+		for _, existing := range dimensions {
+			if existing == dimension {
+				return "", fmt.Errorf("invalid --group-by: %q (dimension %q listed more than once)", groupBy, dimension)
+			}
+		}
+		dimensions = append(dimensions, dimension)
+	}
+	if len(dimensions) == 1 {
+		return dimensions[0], nil
+	}
+	return stats.CompositeDimension(dimensions...), nil
 }
 
 type tokenUnit string
@@ -302,12 +348,14 @@ type dayTotal struct {
 	Date       string
 	Sessions   int
 	TokenUsage provider.TokenUsage
+	Cost       provider.CostEstimate
 }
 
 type groupTotal struct {
 	Name       string
 	Sessions   int
 	TokenUsage provider.TokenUsage
+	Cost       provider.CostEstimate
 }
 
 func aggregateTotalsByDate(daily []provider.DailyStats) []dayTotal {
@@ -324,6 +372,7 @@ func aggregateTotalsByDate(daily []provider.DailyStats) []dayTotal {
 		}
 		t.Sessions += d.Sessions
 		mergeTokenUsage(&t.TokenUsage, d.TokenUsage)
+		t.Cost.Add(d.Cost)
 	}
 
 	result := make([]dayTotal, 0, len(dayMap))
@@ -355,6 +404,7 @@ func aggregateTotalsByGroup(daily []provider.DailyStats) []groupTotal {
 		}
 		t.Sessions += d.Sessions
 		mergeTokenUsage(&t.TokenUsage, d.TokenUsage)
+		t.Cost.Add(d.Cost)
 	}
 
 	result := make([]groupTotal, 0, len(groupMap))
@@ -379,6 +429,9 @@ func shortDateLabel(date string) string {
 	if len(date) == len("2006-01-02") {
 		return date[5:]
 	}
+	if i := strings.Index(date, "-W"); i >= 0 {
+		return date[i+1:]
+	}
 	return date
 }
 
@@ -400,10 +453,24 @@ func trendBar(value, maxValue, width int) string {
 }
 
 func groupColumnTitle(groupBy stats.AggregateDimension) string {
-	if groupBy == stats.AggregateDimensionCLI {
+	components := groupBy.Components()
+	if len(components) > 1 {
+		titles := make([]string, 0, len(components))
+		for _, d := range components {
+			titles = append(titles, groupColumnTitle(d))
+		}
+		return strings.Join(titles, " / ")
+	}
+	switch groupBy {
+	case stats.AggregateDimensionCLI:
 		return "CLI"
+	case stats.AggregateDimensionProject:
+		return "Project"
+	case stats.AggregateDimensionAccount:
+		return "Account"
+	default:
+		return "Model"
 	}
-	return "Model"
 }
 
 func formatPercent(part, whole int) string {
@@ -414,16 +481,22 @@ func formatPercent(part, whole int) string {
 }
 
 func printDailyDashboard(daily []provider.DailyStats, unit tokenUnit, groupBy stats.AggregateDimension, topN int) {
+	printPeriodDashboard("Daily", daily, unit, groupBy, topN)
+}
+
+// printPeriodDashboard renders the trend, ranking, and share sections for
+// stats keyed by day, week, or month.
+func printPeriodDashboard(periodTitle string, daily []provider.DailyStats, unit tokenUnit, groupBy stats.AggregateDimension, topN int) {
 	dateTotals := aggregateTotalsByDate(daily)
 	groupTotals := aggregateTotalsByGroup(daily)
 
-	printDailyTrend(dateTotals, unit)
+	printPeriodTrend(periodTitle, dateTotals, unit)
 	printGroupRanking(groupTotals, unit, groupBy)
 	printTopGroupShare(groupTotals, unit, groupBy, topN)
 }
 
-func printDailyTrend(dateTotals []dayTotal, unit tokenUnit) {
-	fmt.Fprintln(os.Stdout, "Daily Total Trend")
+func printPeriodTrend(periodTitle string, dateTotals []dayTotal, unit tokenUnit) {
+	fmt.Fprintf(os.Stdout, "%s Total Trend\n", periodTitle)
 	if len(dateTotals) == 0 {
 		fmt.Fprintln(os.Stdout, "No data for selected range.")
 		fmt.Fprintln(os.Stdout)
@@ -471,15 +544,16 @@ func printGroupRanking(groupTotals []groupTotal, unit tokenUnit, groupBy stats.A
 	}
 
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintf(w, "Rank\t%s\tSessions\t%s\n", groupTitle, tokenHeader("Total", unit))
+	fmt.Fprintf(w, "Rank\t%s\tSessions\t%s\tCost\n", groupTitle, tokenHeader("Total", unit))
 	for i, g := range groupTotals {
 		fmt.Fprintf(
 			w,
-			"%d\t%s\t%d\t%s\n",
+			"%d\t%s\t%d\t%s\t%s\n",
 			i+1,
 			g.Name,
 			g.Sessions,
 			formatTokenByUnit(g.TokenUsage.Total(), unit),
+			formatCost(g.Cost),
 		)
 	}
 	w.Flush()
@@ -499,8 +573,10 @@ func printTopGroupShare(groupTotals []groupTotal, unit tokenUnit, groupBy stats.
 	}
 
 	var totalUsage provider.TokenUsage
+	var totalCost provider.CostEstimate
 	for _, g := range groupTotals {
 		mergeTokenUsage(&totalUsage, g.TokenUsage)
+		totalCost.Add(g.Cost)
 	}
 
 	var topUsage provider.TokenUsage
@@ -515,15 +591,17 @@ func printTopGroupShare(groupTotals []groupTotal, unit tokenUnit, groupBy stats.
 		formatTokenByUnit(totalUsage.Total(), unit),
 		formatPercent(topUsage.Total(), totalUsage.Total()),
 	)
+	fmt.Fprintf(os.Stdout, "Estimated cost: %s\n", formatCost(totalCost))
 
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 	fmt.Fprintf(
 		w,
-		"Rank\t%s\tShare\tSessions\t%s\t%s\t%s\t%s\t%s\n",
+		"Rank\t%s\tShare\tSessions\t%s\t%s\t%s\t%s\t%s\t%s\tCost\n",
 		groupTitle,
 		tokenHeader("Total", unit),
 		tokenHeader("Input", unit),
 		tokenHeader("Output", unit),
+		tokenHeader("Reasoning", unit),
 		tokenHeader("Cache Read", unit),
 		tokenHeader("Cache Create", unit),
 	)
@@ -531,7 +609,7 @@ func printTopGroupShare(groupTotals []groupTotal, unit tokenUnit, groupBy stats.
 		g := groupTotals[i]
 		fmt.Fprintf(
 			w,
-			"%d\t%s\t%s\t%d\t%s\t%s\t%s\t%s\t%s\n",
+			"%d\t%s\t%s\t%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
 			i+1,
 			g.Name,
 			formatPercent(g.TokenUsage.Total(), totalUsage.Total()),
@@ -539,10 +617,13 @@ func printTopGroupShare(groupTotals []groupTotal, unit tokenUnit, groupBy stats.
 			formatTokenByUnit(g.TokenUsage.Total(), unit),
 			formatTokenByUnit(g.TokenUsage.InputOther, unit),
 			formatTokenByUnit(g.TokenUsage.Output, unit),
+			formatTokenByUnit(g.TokenUsage.OutputReasoning, unit),
 			formatTokenByUnit(g.TokenUsage.InputCacheRead, unit),
 			formatTokenByUnit(g.TokenUsage.InputCacheCreate, unit),
+			formatCost(g.Cost),
 		)
 	}
 
 	w.Flush()
+	printUnpricedModelsNote(os.Stdout, totalCost)
 }
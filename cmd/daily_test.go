@@ -14,6 +14,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/miss-you/codetok/pricing"
 	"github.com/miss-you/codetok/provider"
 	"github.com/miss-you/codetok/stats"
 )
@@ -184,6 +185,10 @@ func TestResolveGroupBy(t *testing.T) {
 		{input: "MODEL", want: stats.AggregateDimensionModel},
 		{input: "cli", want: stats.AggregateDimensionCLI},
 		{input: "", want: stats.AggregateDimensionCLI},
+		{input: "project", want: stats.AggregateDimensionProject},
+		{input: "account", want: stats.AggregateDimensionAccount},
+		{input: "cli,model", want: "cli,model"},
+		{input: " Project , MODEL ", want: "project,model"},
 	}
 
 	for _, tt := range tests {
@@ -200,12 +205,14 @@ func TestResolveGroupBy(t *testing.T) {
 }
 
 func TestResolveGroupBy_Invalid(t *testing.T) {
-	_, err := resolveGroupBy("provider")
-	if err == nil {
-		t.Fatal("expected error for invalid group-by, got nil")
-	}
-	if !strings.Contains(err.Error(), "invalid --group-by") {
-		t.Fatalf("unexpected error: %v", err)
+	for _, input := range []string{"provider", "cli,provider", "model,model", "cli,"} {
+		_, err := resolveGroupBy(input)
+		if err == nil {
+			t.Fatalf("resolveGroupBy(%q): expected error, got nil", input)
+		}
+		if !strings.Contains(err.Error(), "invalid --group-by") {
+			t.Fatalf("resolveGroupBy(%q): unexpected error: %v", input, err)
+		}
 	}
 }
 
@@ -558,7 +565,7 @@ func TestAggregateDailyUsageEventsFromProvidersInRange_MatchesMaterializedPath(t
 	}
 	sinceDate, untilDate := dailyEventFilterDates(opts.Since, opts.Until, loc)
 
-	got, err := aggregateDailyUsageEventsFromProvidersInRange(newDailyTestCommand(), providers, opts, stats.AggregateDimensionCLI, loc, sinceDate, untilDate)
+	got, err := aggregateDailyUsageEventsFromProvidersInRange(newDailyTestCommand(), providers, opts, stats.AggregateDimensionCLI, loc, sinceDate, untilDate, nil)
 	if err != nil {
 		t.Fatalf("aggregateDailyUsageEventsFromProvidersInRange returned error: %v", err)
 	}
@@ -578,7 +585,7 @@ func TestAggregateDailyUsageEventsFromProvidersInRange_ReturnsProviderErrorsWith
 	}
 	opts := provider.UsageEventCollectOptions{Since: time.Date(2026, 4, 16, 0, 0, 0, 0, time.UTC)}
 
-	_, err := aggregateDailyUsageEventsFromProvidersInRange(newDailyTestCommand(), []provider.Provider{bad}, opts, stats.AggregateDimensionCLI, time.UTC, "2026-04-16", "")
+	_, err := aggregateDailyUsageEventsFromProvidersInRange(newDailyTestCommand(), []provider.Provider{bad}, opts, stats.AggregateDimensionCLI, time.UTC, "2026-04-16", "", nil)
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
@@ -650,7 +657,7 @@ func TestRunDaily_JSONUsesStreamingDailyAggregationAndMatchesMaterializedPath(t
 
 	got := decodeDailyJSON(t, output)
 	filtered := stats.FilterEventsByDateRange(events, "2026-04-16", "2026-04-16", loc)
-	want := stats.AggregateEventsByDayWithDimension(filtered, stats.AggregateDimensionCLI, loc)
+	want := aggregateEventsWithDefaultPricing(filtered, stats.AggregateDimensionCLI, loc)
 	if !reflect.DeepEqual(got, want) {
 		t.Fatalf("daily JSON = %#v, want materialized stats %#v", got, want)
 	}
@@ -718,7 +725,7 @@ func TestRunDaily_DashboardUsesStreamingDailyAggregationAndMatchesMaterializedPa
 	})
 
 	filtered := stats.FilterEventsByDateRange(events, "2026-04-16", "2026-04-16", loc)
-	wantDaily := stats.AggregateEventsByDayWithDimension(filtered, stats.AggregateDimensionCLI, loc)
+	wantDaily := aggregateEventsWithDefaultPricing(filtered, stats.AggregateDimensionCLI, loc)
 	wantOutput := captureStdout(t, func() {
 		printDailyDashboard(wantDaily, tokenUnitRaw, stats.AggregateDimensionCLI, 1)
 	})
@@ -727,6 +734,15 @@ func TestRunDaily_DashboardUsesStreamingDailyAggregationAndMatchesMaterializedPa
 	}
 }
 
+func aggregateEventsWithDefaultPricing(events []provider.UsageEvent, groupBy stats.AggregateDimension, loc *time.Location) []provider.DailyStats {
+	aggregator := stats.NewDailyEventAggregator(groupBy, loc)
+	aggregator.SetPricing(pricing.Default())
+	for _, event := range events {
+		aggregator.Add(event)
+	}
+	return aggregator.Results()
+}
+
 func TestRunDaily_AllUsesFullHistoryCollection(t *testing.T) {
 	eventProvider := &collectTestUsageEventProvider{
 		collectTestProvider: collectTestProvider{name: "codex"},
@@ -1062,6 +1078,52 @@ func TestPrintDailyDashboard_GroupByCLI(t *testing.T) {
 	)
 }
 
+func TestRunDaily_CompositeGroupByRendersTupleLabels(t *testing.T) {
+	ts := time.Date(2026, 4, 16, 9, 0, 0, 0, time.UTC)
+	eventProvider := &collectTestUsageEventProvider{
+		collectTestProvider: collectTestProvider{name: "claude"},
+		events: []provider.UsageEvent{
+			{ProviderName: "claude", ModelName: "claude-opus-4-1", SessionID: "c1", Timestamp: ts, TokenUsage: provider.TokenUsage{InputOther: 300}},
+			{ProviderName: "claude", ModelName: "claude-sonnet-4-5", SessionID: "c2", Timestamp: ts, TokenUsage: provider.TokenUsage{InputOther: 200}},
+			{ProviderName: "codex", ModelName: "gpt-5", SessionID: "x1", Timestamp: ts, TokenUsage: provider.TokenUsage{InputOther: 100}},
+		},
+	}
+
+	for _, jsonOutput := range []string{"false", "true"} {
+		cmd := newDailyTestCommand()
+		for name, value := range map[string]string{"json": jsonOutput, "all": "true", "timezone": "UTC", "group-by": "cli,model", "unit": "raw"} {
+			if err := cmd.Flags().Set(name, value); err != nil {
+				t.Fatalf("setting --%s: %v", name, err)
+			}
+		}
+		output := captureStdout(t, func() {
+			if err := runDailyWithProviders(cmd, nil, []provider.Provider{eventProvider}, ts); err != nil {
+				t.Fatalf("runDailyWithProviders returned error: %v", err)
+			}
+		})
+
+		if jsonOutput == "false" {
+			assertContainsAll(t, output,
+				"CLI / Model Total Ranking",
+				"Top 5 CLI / Model Share",
+				"claude / claude-opus-4-1",
+				"claude / claude-sonnet-4-5",
+				"codex / gpt-5",
+			)
+			continue
+		}
+		rows := decodeDailyJSON(t, output)
+		if len(rows) != 3 {
+			t.Fatalf("got %d rows, want 3: %s", len(rows), output)
+		}
+		for _, row := range rows {
+			if row.GroupBy != "cli,model" || row.Groups["cli"] != row.ProviderName || row.Groups["model"] == "" {
+				t.Fatalf("row = %+v, want cli and model group components", row)
+			}
+		}
+	}
+}
+
 func TestPrintTopGroupShare_RespectsTopN(t *testing.T) {
 	groupTotals := []groupTotal{
 		{
@@ -1099,6 +1161,44 @@ func TestPrintTopGroupShare_RespectsTopN(t *testing.T) {
 	}
 }
 
+func TestPrintTopGroupShare_IncludesReasoningColumn(t *testing.T) {
+	groupTotals := []groupTotal{
+		{
+			Name:     "codex",
+			Sessions: 1,
+			TokenUsage: provider.TokenUsage{
+				InputOther:      200,
+				Output:          120,
+				OutputReasoning: 45,
+			},
+		},
+	}
+
+	output := captureStdout(t, func() {
+		printTopGroupShare(groupTotals, tokenUnitRaw, stats.AggregateDimensionCLI, 5)
+	})
+
+	assertContainsAll(t, output, "Reasoning", "45")
+	var header []string
+	for _, line := range strings.Split(output, "\n") {
+		if strings.HasPrefix(line, "Rank") {
+			header = strings.Fields(line)
+		}
+	}
+	outputIndex, reasoningIndex := -1, -1
+	for i, field := range header {
+		switch field {
+		case "Output":
+			outputIndex = i
+		case "Reasoning":
+			reasoningIndex = i
+		}
+	}
+	if outputIndex < 0 || reasoningIndex != outputIndex+1 {
+		t.Fatalf("Reasoning column should follow Output, header = %v", header)
+	}
+}
+
 type benchmarkDailyUsageEventProvider struct {
 	name   string
 	events []provider.UsageEvent
@@ -1177,7 +1277,7 @@ func BenchmarkDailyAggregationMaterializedVsStreaming(b *testing.B) {
 		b.ResetTimer()
 		for i := 0; i < b.N; i++ {
 			var err error
-			result, err = aggregateDailyUsageEventsFromProvidersInRange(cmd, providers, opts, stats.AggregateDimensionCLI, loc, sinceDate, untilDate)
+			result, err = aggregateDailyUsageEventsFromProvidersInRange(cmd, providers, opts, stats.AggregateDimensionCLI, loc, sinceDate, untilDate, nil)
 			if err != nil {
 				b.Fatal(err)
 			}
@@ -1231,21 +1331,8 @@ func decodeDailyJSON(t *testing.T, output string) []provider.DailyStats {
 
 func newDailyTestCommand() *cobra.Command {
 	cmd := &cobra.Command{}
-	cmd.Flags().Bool("json", false, "")
-	cmd.Flags().String("since", "", "")
-	cmd.Flags().String("until", "", "")
-	cmd.Flags().Int("days", defaultDailyDays, "")
-	cmd.Flags().Bool("all", false, "")
-	cmd.Flags().String("timezone", "", "")
-	cmd.Flags().String("unit", defaultTokenUnit, "")
-	cmd.Flags().String("group-by", defaultGroupBy, "")
-	cmd.Flags().Int("top", defaultTopN, "")
-	cmd.Flags().String("provider", "", "")
-	cmd.Flags().String("base-dir", "", "")
-	cmd.Flags().String("kimi-dir", "", "")
-	cmd.Flags().String("claude-dir", "", "")
-	cmd.Flags().String("codex-dir", "", "")
-	cmd.Flags().String("cursor-dir", "", "")
+	addDailyFlags(cmd)
+	disableTestUsageEventCache(cmd)
 	return cmd
 }
 
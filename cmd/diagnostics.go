@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/miss-you/codetok/provider"
+)
+
+const strictFlagUsage = "Fail when any local file or line could not be parsed"
+const diagnosticsFlagUsage = "Print which local files or lines were skipped and why (to stderr)"
+
+// reportUsageEventDiagnostics prints what collection skipped to stderr: every
+// issue with --diagnostics, otherwise a one-line warning. With --strict any
+// skipped file or malformed line fails the command; dropped duplicate rows are
+// expected and only listed with --diagnostics.
+func reportUsageEventDiagnostics(cmd *cobra.Command, diagnostics *provider.Diagnostics) error {
+	showDetails, _ := cmd.Flags().GetBool("diagnostics")
+	strict, _ := cmd.Flags().GetBool("strict")
+	w := cmd.ErrOrStderr()
+
+	skipped, malformed := diagnostics.Counts()
+	clean := skipped == 0 && malformed == 0
+	summary := "all local files parsed cleanly"
+	if !clean {
+		summary = diagnosticsSummary(skipped, malformed)
+	}
+	if showDetails {
+		if duplicates := diagnostics.DuplicateRows(); duplicates > 0 {
+			fmt.Fprintf(w, "Diagnostics: %s; %s dropped\n", summary, pluralCount(duplicates, "duplicate row"))
+		} else {
+			fmt.Fprintf(w, "Diagnostics: %s\n", summary)
+		}
+		printDiagnosticIssues(w, diagnostics.Issues())
+	}
+	if clean {
+		return nil
+	}
+	if strict {
+		return fmt.Errorf("--strict: local data could not be fully parsed (%s)", summary)
+	}
+	if !showDetails {
+		fmt.Fprintf(w, "Warning: %s; rerun with --diagnostics for details\n", summary)
+	}
+	return nil
+}
+
+func printDiagnosticIssues(w io.Writer, issues []provider.DiagnosticIssue) {
+	for _, issue := range issues {
+		if issue.Err != nil {
+			fmt.Fprintf(w, "  %s: %s: skipped: %v\n", issue.Provider, issue.Path, issue.Err)
+			continue
+		}
+		var details []string
+		if issue.MalformedLines > 0 {
+			details = append(details, pluralCount(issue.MalformedLines, "malformed line")+" ignored")
+		}
+		if issue.DuplicateRows > 0 {
+			details = append(details, pluralCount(issue.DuplicateRows, "duplicate row")+" dropped")
+		}
+		fmt.Fprintf(w, "  %s: %s: %s\n", issue.Provider, issue.Path, strings.Join(details, ", "))
+	}
+}
+
+func diagnosticsSummary(skipped, malformed int) string {
+	switch {
+	case skipped > 0 && malformed > 0:
+		return fmt.Sprintf("%s skipped, %s ignored", pluralCount(skipped, "file"), pluralCount(malformed, "malformed line"))
+	case skipped > 0:
+		return fmt.Sprintf("%s skipped", pluralCount(skipped, "file"))
+	default:
+		return fmt.Sprintf("%s ignored", pluralCount(malformed, "malformed line"))
+	}
+}
+
+func pluralCount(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/miss-you/codetok/provider"
+)
+
+func newDiagnosticsTestCommand(t *testing.T, flags ...string) (*cobra.Command, *bytes.Buffer) {
+	t.Helper()
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool("strict", false, "")
+	cmd.Flags().Bool("diagnostics", false, "")
+	for _, name := range flags {
+		if err := cmd.Flags().Set(name, "true"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var stderr bytes.Buffer
+	cmd.SetErr(&stderr)
+	return cmd, &stderr
+}
+
+func testDiagnostics() *provider.Diagnostics {
+	var d provider.Diagnostics
+	d.AddFileError("gemini", "/logs/chat.json", errors.New("unexpected end of JSON input"))
+	d.AddMalformedLines("claude", "/logs/a.jsonl", 2)
+	d.AddMalformedLines("claude", "/logs/a.jsonl", 1)
+	d.AddDuplicateRows("cursor", "/cursor/imports/dashboard.csv", 2)
+	return &d
+}
+
+func TestReportUsageEventDiagnostics_WarnsByDefault(t *testing.T) {
+	cmd, stderr := newDiagnosticsTestCommand(t)
+	if err := reportUsageEventDiagnostics(cmd, testDiagnostics()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "Warning: 1 file skipped, 3 malformed lines ignored; rerun with --diagnostics for details\n"
+	if stderr.String() != want {
+		t.Fatalf("stderr = %q, want %q", stderr.String(), want)
+	}
+}
+
+func TestReportUsageEventDiagnostics_ListsIssues(t *testing.T) {
+	cmd, stderr := newDiagnosticsTestCommand(t, "diagnostics")
+	if err := reportUsageEventDiagnostics(cmd, testDiagnostics()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertContainsAll(t, stderr.String(),
+		"Diagnostics: 1 file skipped, 3 malformed lines ignored; 2 duplicate rows dropped",
+		"claude: /logs/a.jsonl: 3 malformed lines ignored",
+		"cursor: /cursor/imports/dashboard.csv: 2 duplicate rows dropped",
+		"gemini: /logs/chat.json: skipped: unexpected end of JSON input",
+	)
+	if strings.Contains(stderr.String(), "Warning") {
+		t.Fatalf("stderr = %q, want no summary warning with --diagnostics", stderr.String())
+	}
+
+	clean, stderr := newDiagnosticsTestCommand(t, "diagnostics")
+	if err := reportUsageEventDiagnostics(clean, &provider.Diagnostics{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stderr.String(), "all local files parsed cleanly") {
+		t.Fatalf("stderr = %q, want clean report", stderr.String())
+	}
+}
+
+func TestReportUsageEventDiagnostics_StrictFails(t *testing.T) {
+	cmd, _ := newDiagnosticsTestCommand(t, "strict")
+	err := reportUsageEventDiagnostics(cmd, testDiagnostics())
+	if err == nil || !strings.Contains(err.Error(), "--strict") {
+		t.Fatalf("err = %v, want --strict failure", err)
+	}
+
+	var duplicatesOnly provider.Diagnostics
+	duplicatesOnly.AddDuplicateRows("cursor", "/cursor/imports/dashboard.csv", 2)
+	cmd, stderr := newDiagnosticsTestCommand(t, "strict")
+	if err := reportUsageEventDiagnostics(cmd, &duplicatesOnly); err != nil || stderr.Len() != 0 {
+		t.Fatalf("err = %v, stderr = %q; want dropped duplicates to pass silently", err, stderr.String())
+	}
+}
@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/miss-you/codetok/provider"
+	"github.com/miss-you/codetok/stats"
+)
+
+var heatmapCmd = &cobra.Command{
+	Use:   "heatmap",
+	Short: "Show token usage by weekday and hour of day",
+	Long: `Show token usage by weekday and hour of day.
+
+heatmap buckets usage events by the weekday and hour of their timestamp in the selected timezone and draws a grid with one row per weekday and one cell per hour, shaded by tokens relative to the busiest cell. It shows when agents are busiest and makes overnight runs stand out.
+
+Without --group-by all usage forms one grid; with it, each group gets its own grid, largest first, limited to --top in the terminal output. --json prints the full weekday × hour matrices.
+
+Reporting commands read only local session files and Cursor CSV exports already on disk. They never trigger implicit Cursor login or sync.`,
+	RunE: runHeatmap,
+}
+
+const defaultHeatmapDays = 28
+
+// heatmapShades are the cell glyphs from empty to the busiest quarter.
+var heatmapShades = []string{"··", "░░", "▒▒", "▓▓", "██"}
+
+func init() {
+	addHeatmapFlags(heatmapCmd)
+	rootCmd.AddCommand(heatmapCmd)
+}
+
+func addHeatmapFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("json", false, "Output as JSON")
+	cmd.Flags().String("since", "", "Start date filter (format: 2006-01-02)")
+	cmd.Flags().String("until", "", "End date filter (format: 2006-01-02)")
+	cmd.Flags().Int("days", defaultHeatmapDays, "Lookback window in days when --since/--until are not set")
+	cmd.Flags().Bool("all", false, "Include all historical sessions")
+	cmd.Flags().String("timezone", "", "Timezone for weekdays, hours, and date filters (IANA name, default: local)")
+	cmd.Flags().String("group-by", "", "Draw one grid per group: cli, model, project, account, or a comma-separated list (default: one grid for all usage)")
+	cmd.Flags().Int("top", defaultTopN, "Top N groups to draw when --group-by is set")
+	addUsageSourceFlags(cmd)
+	cmd.Flags().Bool("archive", false, archiveFlagUsage)
+}
+
+type heatmapJSON struct {
+	Timezone string          `json:"timezone"`
+	GroupBy  string          `json:"group_by,omitempty"`
+	Weekdays []string        `json:"weekdays"`
+	Heatmaps []stats.Heatmap `json:"heatmaps"`
+}
+
+func runHeatmap(cmd *cobra.Command, args []string) error {
+	return runHeatmapWithProviders(cmd, provider.Registry(), time.Now())
+}
+
+func runHeatmapWithProviders(cmd *cobra.Command, providers []provider.Provider, now time.Time) error {
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	sinceStr, _ := cmd.Flags().GetString("since")
+	untilStr, _ := cmd.Flags().GetString("until")
+	days, _ := cmd.Flags().GetInt("days")
+	allHistory, _ := cmd.Flags().GetBool("all")
+	timezoneStr, _ := cmd.Flags().GetString("timezone")
+	groupByStr, _ := cmd.Flags().GetString("group-by")
+	topN, _ := cmd.Flags().GetInt("top")
+	var groupBy stats.AggregateDimension
+	if strings.TrimSpace(groupByStr) != "" {
+		var err error
+		if groupBy, err = resolveGroupBy(groupByStr); err != nil {
+			return err
+		}
+	}
+	if !jsonOutput && topN < 1 {
+		return fmt.Errorf("invalid --top: must be >= 1")
+	}
+	loc, err := resolveTimezone(timezoneStr)
+	if err != nil {
+		return err
+	}
+
+	since, until, err := resolveDailyDateRange(
+		sinceStr,
+		untilStr,
+		days,
+		allHistory,
+		cmd.Flags().Changed("days"),
+		now,
+		loc,
+	)
+	if err != nil {
+		return err
+	}
+
+	collectOpts := provider.UsageEventCollectOptions{
+		Since:    since,
+		Until:    until,
+		Location: loc,
+	}
+	sinceDate, untilDate := dailyEventFilterDates(since, until, loc)
+	dateFilter := stats.NewEventDateRangeFilter(sinceDate, untilDate, loc)
+	var events []provider.UsageEvent
+	err = forEachUsageEventFromProvidersInRange(cmd, providers, collectOpts, func(event provider.UsageEvent) error {
+		if dateFilter.Contains(event) {
+			events = append(events, event)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	heatmaps := stats.BuildHeatmaps(events, groupBy, loc)
+	out := cmd.OutOrStdout()
+	if jsonOutput {
+		weekdays := make([]string, 0, len(stats.HeatmapWeekdays))
+		for _, day := range stats.HeatmapWeekdays {
+			weekdays = append(weekdays, day.String())
+		}
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(heatmapJSON{
+			Timezone: loc.String(),
+			GroupBy:  string(groupBy),
+			Weekdays: weekdays,
+			Heatmaps: heatmaps,
+		})
+	}
+
+	if len(heatmaps) == 0 {
+		fmt.Fprintln(out, "No usage found.")
+		return nil
+	}
+	if len(heatmaps) > topN {
+		heatmaps = heatmaps[:topN]
+	}
+	for i, heatmap := range heatmaps {
+		if i > 0 {
+			fmt.Fprintln(out)
+		}
+		printHeatmap(out, heatmap, groupBy != "", loc)
+	}
+	return nil
+}
+
+// printHeatmap draws one weekday × hour grid. Each cell is shaded by its share
+// of the busiest cell, in quarters.
+func printHeatmap(out io.Writer, heatmap stats.Heatmap, titled bool, loc *time.Location) {
+	if titled {
+		fmt.Fprintf(out, "%s (%d tokens)\n", heatmap.Group, heatmap.Total)
+	}
+
+	peak, peakDay, peakHour := 0, 0, 0
+	for day := range heatmap.Tokens {
+		for hour, tokens := range heatmap.Tokens[day] {
+			if tokens > peak {
+				peak, peakDay, peakHour = tokens, day, hour
+			}
+		}
+	}
+
+	var header strings.Builder
+	header.WriteString("     ")
+	for hour := 0; hour < 24; hour += 3 {
+		fmt.Fprintf(&header, "%-6d", hour)
+	}
+	fmt.Fprintln(out, strings.TrimRight(header.String(), " ")+"  Total")
+	for day, weekday := range stats.HeatmapWeekdays {
+		var row strings.Builder
+		rowTotal := 0
+		for _, tokens := range heatmap.Tokens[day] {
+			row.WriteString(heatmapShades[heatmapShade(tokens, peak)])
+			rowTotal += tokens
+		}
+		fmt.Fprintf(out, "%s  %s  %d\n", weekday.String()[:3], row.String(), rowTotal)
+	}
+	fmt.Fprintf(out, "Peak: %d tokens on %s %02d:00 (%s). Shades: %s none, %s ≤25%%, %s ≤50%%, %s ≤75%%, %s >75%% of peak.\n",
+		peak, stats.HeatmapWeekdays[peakDay], peakHour, loc.String(),
+		heatmapShades[0], heatmapShades[1], heatmapShades[2], heatmapShades[3], heatmapShades[4])
+}
+
+// heatmapShade maps tokens to an index into heatmapShades.
+func heatmapShade(tokens, peak int) int {
+	if tokens <= 0 || peak <= 0 {
+		return 0
+	}
+	// Ceiling of tokens/peak in quarters: (0, 25%] is 1, (75%, 100%] is 4.
+	return (tokens*4 + peak - 1) / peak
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/miss-you/codetok/provider"
+)
+
+func heatmapTestProviders() []provider.Provider {
+	return []provider.Provider{
+		&collectTestUsageEventProvider{
+			collectTestProvider: collectTestProvider{name: "codex"},
+			events: []provider.UsageEvent{
+				// Tuesday 2026-04-14 02:15 UTC is Tuesday 10:15 in Asia/Shanghai.
+				{ProviderName: "codex", ModelName: "gpt-5.4", SessionID: "c1", Timestamp: time.Date(2026, 4, 14, 2, 15, 0, 0, time.UTC), TokenUsage: provider.TokenUsage{InputOther: 400}},
+				{ProviderName: "codex", ModelName: "gpt-5.4", SessionID: "c1", Timestamp: time.Date(2026, 4, 14, 2, 45, 0, 0, time.UTC), TokenUsage: provider.TokenUsage{InputOther: 400}},
+			},
+		},
+		&collectTestUsageEventProvider{
+			collectTestProvider: collectTestProvider{name: "claude"},
+			events: []provider.UsageEvent{
+				// Friday 2026-04-10 19:00 UTC is Saturday 03:00 in Asia/Shanghai.
+				{ProviderName: "claude", ModelName: "claude-sonnet-4-5", SessionID: "a1", Timestamp: time.Date(2026, 4, 10, 19, 0, 0, 0, time.UTC), TokenUsage: provider.TokenUsage{Output: 100}},
+			},
+		},
+	}
+}
+
+func TestRunHeatmap_DrawsGridInTimezone(t *testing.T) {
+	now := time.Date(2026, 4, 16, 12, 0, 0, 0, time.UTC)
+	cmd, out := newFlagTestCommand(t, addHeatmapFlags, map[string]string{"timezone": "Asia/Shanghai"})
+	if err := runHeatmapWithProviders(cmd, heatmapTestProviders(), now); err != nil {
+		t.Fatalf("runHeatmapWithProviders: %v", err)
+	}
+
+	lines := strings.Split(out.String(), "\n")
+	if !strings.HasPrefix(lines[0], "     0     3     6") || !strings.HasSuffix(lines[0], "  Total") {
+		t.Fatalf("header = %q", lines[0])
+	}
+	tuesday := "Tue  " + strings.Repeat("··", 10) + "██" + strings.Repeat("··", 13) + "  800"
+	saturday := "Sat  " + strings.Repeat("··", 3) + "░░" + strings.Repeat("··", 20) + "  100"
+	if lines[2] != tuesday || lines[6] != saturday {
+		t.Fatalf("grid =\n%s\nwant Tuesday 10:00 at peak and Saturday 03:00 lightly shaded", out.String())
+	}
+	assertContainsAll(t, out.String(), "Peak: 800 tokens on Tuesday 10:00 (Asia/Shanghai)")
+}
+
+func TestRunHeatmap_GroupByAndJSON(t *testing.T) {
+	now := time.Date(2026, 4, 16, 12, 0, 0, 0, time.UTC)
+	cmd, out := newFlagTestCommand(t, addHeatmapFlags, map[string]string{"timezone": "UTC", "group-by": "cli", "top": "1"})
+	if err := runHeatmapWithProviders(cmd, heatmapTestProviders(), now); err != nil {
+		t.Fatalf("runHeatmapWithProviders: %v", err)
+	}
+	if !strings.HasPrefix(out.String(), "codex (800 tokens)\n") || strings.Contains(out.String(), "claude") {
+		t.Fatalf("grouped output = %q, want only the top group", out.String())
+	}
+
+	cmd, out = newFlagTestCommand(t, addHeatmapFlags, map[string]string{"timezone": "UTC", "group-by": "cli", "json": "true", "provider": "claude"})
+	if err := runHeatmapWithProviders(cmd, heatmapTestProviders(), now); err != nil {
+		t.Fatalf("runHeatmapWithProviders --json: %v", err)
+	}
+	var got struct {
+		Timezone string   `json:"timezone"`
+		GroupBy  string   `json:"group_by"`
+		Weekdays []string `json:"weekdays"`
+		Heatmaps []struct {
+			Group  string  `json:"group"`
+			Tokens [][]int `json:"tokens"`
+			Total  int     `json:"total"`
+		} `json:"heatmaps"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("decoding %q: %v", out.String(), err)
+	}
+	if got.Timezone != "UTC" || got.GroupBy != "cli" || len(got.Weekdays) != 7 || got.Weekdays[0] != "Monday" {
+		t.Fatalf("heatmap JSON header = %+v", got)
+	}
+	if len(got.Heatmaps) != 1 || got.Heatmaps[0].Group != "claude" || len(got.Heatmaps[0].Tokens) != 7 || got.Heatmaps[0].Tokens[4][19] != 100 {
+		t.Fatalf("heatmaps = %+v, want claude's Friday 19:00 cell", got.Heatmaps)
+	}
+
+	cmd, _ = newFlagTestCommand(t, addHeatmapFlags, map[string]string{"group-by": "weekday"})
+	if err := runHeatmapWithProviders(cmd, heatmapTestProviders(), now); err == nil {
+		t.Fatal("expected error for invalid --group-by")
+	}
+}
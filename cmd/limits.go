@@ -0,0 +1,252 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/miss-you/codetok/provider"
+)
+
+var limitsCmd = &cobra.Command{
+	Use:   "limits",
+	Short: "Show rate-limit window utilization logged by Codex",
+	Long: `Show rate-limit window utilization logged by Codex.
+
+Codex records the utilization of its subscription rate limits with every token count: a primary (5-hour) and a secondary (weekly) window, each with the percent used and when it resets. limits shows the latest snapshot per provider and, for each day of the selected range, the peak utilization of both windows.
+
+A reset time in the past means the window has reset since the snapshot was logged.
+
+Reporting commands read only local session files already on disk.`,
+	RunE: runLimits,
+}
+
+func init() {
+	addLimitsFlags(limitsCmd)
+	rootCmd.AddCommand(limitsCmd)
+}
+
+func addLimitsFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("json", false, "Output as JSON")
+	cmd.Flags().String("since", "", "Start date filter (format: 2006-01-02)")
+	cmd.Flags().String("until", "", "End date filter (format: 2006-01-02)")
+	cmd.Flags().Int("days", defaultDailyDays, "Lookback window in days when --since/--until are not set")
+	cmd.Flags().Bool("all", false, "Include all historical sessions")
+	cmd.Flags().String("timezone", "", "Timezone for date filters and displayed times (IANA name, default: local)")
+	cmd.Flags().String("provider", "", "Filter by provider name (codex)")
+	cmd.Flags().String("base-dir", "", "Override default data directory (applies to all providers)")
+	cmd.Flags().String("codex-dir", "", "Override Codex CLI data directory")
+	cmd.Flags().Bool("strict", false, strictFlagUsage)
+	cmd.Flags().Bool("diagnostics", false, diagnosticsFlagUsage)
+}
+
+// rateLimitDay is the peak utilization of each window on one day.
+type rateLimitDay struct {
+	Date                 string   `json:"date"`
+	ProviderName         string   `json:"provider"`
+	Snapshots            int      `json:"snapshots"`
+	PrimaryPeakPercent   *float64 `json:"primary_peak_percent,omitempty"`
+	SecondaryPeakPercent *float64 `json:"secondary_peak_percent,omitempty"`
+}
+
+type rateLimitReport struct {
+	Latest  []provider.RateLimitSnapshot `json:"latest"`
+	History []rateLimitDay               `json:"history"`
+}
+
+func runLimits(cmd *cobra.Command, args []string) error {
+	return runLimitsWithProviders(cmd, provider.Registry(), time.Now())
+}
+
+func runLimitsWithProviders(cmd *cobra.Command, providers []provider.Provider, now time.Time) error {
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	sinceStr, _ := cmd.Flags().GetString("since")
+	untilStr, _ := cmd.Flags().GetString("until")
+	days, _ := cmd.Flags().GetInt("days")
+	allHistory, _ := cmd.Flags().GetBool("all")
+	timezoneStr, _ := cmd.Flags().GetString("timezone")
+	providerFilter, _ := cmd.Flags().GetString("provider")
+	baseDir, _ := cmd.Flags().GetString("base-dir")
+	loc, err := resolveTimezone(timezoneStr)
+	if err != nil {
+		return err
+	}
+	since, until, err := resolveDailyDateRange(
+		sinceStr,
+		untilStr,
+		days,
+		allHistory,
+		cmd.Flags().Changed("days"),
+		now,
+		loc,
+	)
+	if err != nil {
+		return err
+	}
+
+	opts := provider.UsageEventCollectOptions{
+		Since:       since,
+		Until:       until,
+		Location:    loc,
+		Diagnostics: &provider.Diagnostics{},
+	}
+	var snapshots []provider.RateLimitSnapshot
+	for _, p := range provider.FilterProviders(providers, providerFilter) {
+		limitProvider, ok := p.(provider.RateLimitProvider)
+		if !ok {
+			continue
+		}
+		dir := baseDir
+		if providerDir, _ := cmd.Flags().GetString(providerDirFlag(p.Name())); providerDir != "" {
+			dir = providerDir
+		}
+		providerSnapshots, err := limitProvider.CollectRateLimits(dir, opts)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return fmt.Errorf("collecting rate limits from %s: %w", p.Name(), err)
+		}
+		snapshots = append(snapshots, providerSnapshots...)
+	}
+	if err := reportUsageEventDiagnostics(cmd, opts.Diagnostics); err != nil {
+		return err
+	}
+
+	report := buildRateLimitReport(snapshots, loc)
+	if jsonOutput {
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	printRateLimitReport(cmd.OutOrStdout(), report, now, loc)
+	return nil
+}
+
+// buildRateLimitReport keeps the newest snapshot per provider and the daily
+// peaks of each window, both sorted by provider and date.
+func buildRateLimitReport(snapshots []provider.RateLimitSnapshot, loc *time.Location) rateLimitReport {
+	report := rateLimitReport{
+		Latest:  []provider.RateLimitSnapshot{},
+		History: []rateLimitDay{},
+	}
+	latest := make(map[string]provider.RateLimitSnapshot)
+	days := make(map[string]*rateLimitDay)
+	for _, snapshot := range snapshots {
+		if current, ok := latest[snapshot.ProviderName]; !ok || snapshot.Timestamp.After(current.Timestamp) {
+			latest[snapshot.ProviderName] = snapshot
+		}
+
+		date := snapshot.Timestamp.In(loc).Format("2006-01-02")
+		key := snapshot.ProviderName + "\x00" + date
+		day, ok := days[key]
+		if !ok {
+			day = &rateLimitDay{Date: date, ProviderName: snapshot.ProviderName}
+			days[key] = day
+		}
+		day.Snapshots++
+		day.PrimaryPeakPercent = maxPercent(day.PrimaryPeakPercent, snapshot.Primary)
+		day.SecondaryPeakPercent = maxPercent(day.SecondaryPeakPercent, snapshot.Secondary)
+	}
+
+	for _, snapshot := range latest {
+		report.Latest = append(report.Latest, snapshot)
+	}
+	sort.Slice(report.Latest, func(i, j int) bool {
+		return report.Latest[i].ProviderName < report.Latest[j].ProviderName
+	})
+	for _, day := range days {
+		report.History = append(report.History, *day)
+	}
+	sort.Slice(report.History, func(i, j int) bool {
+		if report.History[i].Date != report.History[j].Date {
+			return report.History[i].Date < report.History[j].Date
+		}
+		return report.History[i].ProviderName < report.History[j].ProviderName
+	})
+	return report
+}
+
+func maxPercent(peak *float64, window *provider.RateLimitWindow) *float64 {
+	if window == nil || (peak != nil && *peak >= window.UsedPercent) {
+		return peak
+	}
+	used := window.UsedPercent
+	return &used
+}
+
+func printRateLimitReport(out io.Writer, report rateLimitReport, now time.Time, loc *time.Location) {
+	if len(report.Latest) == 0 {
+		fmt.Fprintln(out, "No rate-limit snapshots found.")
+		return
+	}
+
+	fmt.Fprintln(out, "Latest rate limits")
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Provider\tWindow\tLength\tUsed\tResets\tAs Of")
+	for _, snapshot := range report.Latest {
+		for _, window := range []struct {
+			name  string
+			limit *provider.RateLimitWindow
+		}{{"primary", snapshot.Primary}, {"secondary", snapshot.Secondary}} {
+			if window.limit == nil {
+				continue
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+				snapshot.ProviderName,
+				window.name,
+				formatWindowMinutes(window.limit.WindowMinutes),
+				fmt.Sprintf("%.1f%%", window.limit.UsedPercent),
+				formatReset(window.limit.ResetsAt, now, loc),
+				snapshot.Timestamp.In(loc).Format("2006-01-02 15:04"),
+			)
+		}
+	}
+	_ = w.Flush()
+
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Daily peaks")
+	w = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Date\tProvider\tSnapshots\tPrimary Peak\tSecondary Peak")
+	for _, day := range report.History {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\n",
+			day.Date,
+			day.ProviderName,
+			day.Snapshots,
+			formatOptionalRatio(day.PrimaryPeakPercent, "%.1f%%"),
+			formatOptionalRatio(day.SecondaryPeakPercent, "%.1f%%"),
+		)
+	}
+	_ = w.Flush()
+}
+
+// formatWindowMinutes formats a window length, e.g. 5h or 7d.
+func formatWindowMinutes(minutes int) string {
+	switch {
+	case minutes <= 0:
+		return "-"
+	case minutes%(24*60) == 0:
+		return fmt.Sprintf("%dd", minutes/(24*60))
+	case minutes%60 == 0:
+		return fmt.Sprintf("%dh", minutes/60)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
+
+func formatReset(resetsAt *time.Time, now time.Time, loc *time.Location) string {
+	if resetsAt == nil {
+		return "-"
+	}
+	at := resetsAt.In(loc).Format("2006-01-02 15:04")
+	if !resetsAt.After(now) {
+		return at + " (passed)"
+	}
+	return at + " (in " + formatHoursMinutes(resetsAt.Sub(now)) + ")"
+}
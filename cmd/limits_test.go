@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/miss-you/codetok/provider"
+)
+
+type rateLimitTestProvider struct {
+	collectTestProvider
+	snapshots []provider.RateLimitSnapshot
+	seenDirs  []string
+	seenOpts  []provider.UsageEventCollectOptions
+}
+
+func (p *rateLimitTestProvider) CollectRateLimits(baseDir string, opts provider.UsageEventCollectOptions) ([]provider.RateLimitSnapshot, error) {
+	p.seenDirs = append(p.seenDirs, baseDir)
+	p.seenOpts = append(p.seenOpts, opts)
+	return p.snapshots, nil
+}
+
+func rateLimitTestSnapshot(ts time.Time, primary, secondary float64, primaryReset time.Time) provider.RateLimitSnapshot {
+	return provider.RateLimitSnapshot{
+		ProviderName: "codex",
+		SessionID:    "s1",
+		Timestamp:    ts,
+		Primary:      &provider.RateLimitWindow{UsedPercent: primary, WindowMinutes: 300, ResetsAt: &primaryReset},
+		Secondary:    &provider.RateLimitWindow{UsedPercent: secondary, WindowMinutes: 10080},
+	}
+}
+
+func TestRunLimits_ShowsLatestAndDailyPeaks(t *testing.T) {
+	now := time.Date(2026, 4, 16, 12, 0, 0, 0, time.UTC)
+	codex := &rateLimitTestProvider{
+		collectTestProvider: collectTestProvider{name: "codex"},
+		snapshots: []provider.RateLimitSnapshot{
+			rateLimitTestSnapshot(time.Date(2026, 4, 15, 9, 0, 0, 0, time.UTC), 80, 30, time.Date(2026, 4, 15, 12, 0, 0, 0, time.UTC)),
+			rateLimitTestSnapshot(time.Date(2026, 4, 16, 10, 0, 0, 0, time.UTC), 42.5, 35, time.Date(2026, 4, 16, 13, 30, 0, 0, time.UTC)),
+			rateLimitTestSnapshot(time.Date(2026, 4, 16, 9, 0, 0, 0, time.UTC), 60, 34, time.Date(2026, 4, 16, 11, 0, 0, 0, time.UTC)),
+		},
+	}
+	other := &collectTestProvider{name: "claude"}
+
+	cmd, out := newFlagTestCommand(t, addLimitsFlags, map[string]string{"codex-dir": "/codex", "timezone": "UTC"})
+	if err := runLimitsWithProviders(cmd, []provider.Provider{other, codex}, now); err != nil {
+		t.Fatalf("runLimitsWithProviders: %v", err)
+	}
+	if len(codex.seenDirs) != 1 || codex.seenDirs[0] != "/codex" || !codex.seenOpts[0].Since.Equal(time.Date(2026, 4, 10, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("collect calls = %v %+v, want codex dir and the default 7-day range", codex.seenDirs, codex.seenOpts)
+	}
+	assertContainsAll(t, out.String(),
+		"Latest rate limits",
+		"codex     primary    5h      42.5%  2026-04-16 13:30 (in 1h30m)  2026-04-16 10:00",
+		"codex     secondary  7d      35.0%  -",
+		"Daily peaks",
+		"2026-04-15  codex     1          80.0%         30.0%",
+		"2026-04-16  codex     2          60.0%         35.0%",
+	)
+}
+
+func TestRunLimits_JSON(t *testing.T) {
+	now := time.Date(2026, 4, 16, 12, 0, 0, 0, time.UTC)
+	codex := &rateLimitTestProvider{
+		collectTestProvider: collectTestProvider{name: "codex"},
+		snapshots: []provider.RateLimitSnapshot{
+			rateLimitTestSnapshot(time.Date(2026, 4, 16, 10, 0, 0, 0, time.UTC), 42.5, 35, time.Date(2026, 4, 16, 11, 0, 0, 0, time.UTC)),
+		},
+	}
+
+	cmd, out := newFlagTestCommand(t, addLimitsFlags, map[string]string{"json": "true", "timezone": "UTC"})
+	if err := runLimitsWithProviders(cmd, []provider.Provider{codex}, now); err != nil {
+		t.Fatalf("runLimitsWithProviders: %v", err)
+	}
+	var report struct {
+		Latest []struct {
+			Provider string `json:"provider"`
+			Primary  struct {
+				UsedPercent float64 `json:"used_percent"`
+				ResetsAt    string  `json:"resets_at"`
+			} `json:"primary"`
+			Secondary map[string]any `json:"secondary"`
+		} `json:"latest"`
+		History []rateLimitDay `json:"history"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("decoding %q: %v", out.String(), err)
+	}
+	if len(report.Latest) != 1 || report.Latest[0].Primary.UsedPercent != 42.5 || report.Latest[0].Primary.ResetsAt != "2026-04-16T11:00:00Z" {
+		t.Fatalf("latest = %+v", report.Latest)
+	}
+	if _, ok := report.Latest[0].Secondary["resets_at"]; ok {
+		t.Fatalf("secondary = %v, want no resets_at when unknown", report.Latest[0].Secondary)
+	}
+	if len(report.History) != 1 || *report.History[0].PrimaryPeakPercent != 42.5 {
+		t.Fatalf("history = %+v", report.History)
+	}
+
+	cmd, out = newFlagTestCommand(t, addLimitsFlags, map[string]string{"json": "true", "timezone": "UTC"})
+	if err := runLimitsWithProviders(cmd, nil, now); err != nil {
+		t.Fatalf("runLimitsWithProviders without providers: %v", err)
+	}
+	if got := out.String(); got != "{\n  \"latest\": [],\n  \"history\": []\n}\n" {
+		t.Fatalf("empty report = %q", got)
+	}
+}
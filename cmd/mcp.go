@@ -0,0 +1,375 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/miss-you/codetok/provider"
+)
+
+var mcpCmd = &cobra.Command{
+	Use:   "mcp",
+	Short: "Serve token usage to coding agents over MCP (stdio)",
+	Long: `Serve token usage to coding agents over the Model Context Protocol.
+
+mcp speaks MCP (JSON-RPC 2.0, one message per line) on stdin/stdout, so an agent such as Claude Code or Codex can check usage mid-task. It exposes three tools:
+
+  daily_usage            same rows as 'daily --json'
+  session_usage          same rows as 'session --json'
+  current_session_usage  the most recently active session, optionally for one provider
+
+Every tool call rescans local usage, reusing the usage event cache. Directory, pricing, cache, and archive flags apply to every scan. Like the other reporting commands, mcp reads only local files and never triggers Cursor login or sync.`,
+	RunE: runMCP,
+}
+
+func init() {
+	mcpCmd.Flags().String("timezone", "", "Default timezone for date arguments (IANA name, default: local)")
+	addUsageReportFlags(mcpCmd)
+	rootCmd.AddCommand(mcpCmd)
+}
+
+func runMCP(cmd *cobra.Command, args []string) error {
+	server, err := newUsageServer(cmd, provider.Registry())
+	if err != nil {
+		return err
+	}
+	return serveMCP(server, cmd.InOrStdin(), cmd.OutOrStdout())
+}
+
+// mcpProtocolVersions lists the MCP revisions this server can speak, newest first.
+var mcpProtocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// JSON-RPC error codes used by the MCP server.
+const (
+	jsonRPCParseError     = -32700
+	jsonRPCInvalidRequest = -32600
+	jsonRPCMethodNotFound = -32601
+	jsonRPCInvalidParams  = -32602
+)
+
+type jsonRPCRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type jsonRPCResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *jsonRPCError   `json:"error,omitempty"`
+}
+
+type jsonRPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type mcpTool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"inputSchema"`
+	call        func(s *usageServer, query url.Values) (any, error)
+}
+
+type mcpToolResult struct {
+	Content []mcpTextContent `json:"content"`
+	IsError bool             `json:"isError"`
+}
+
+type mcpTextContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+var mcpDateProperty = map[string]any{"type": "string", "pattern": `^\d{4}-\d{2}-\d{2}$`}
+
+// mcpTools mirrors the CLI reports. Argument names are the flag names with
+// underscores, and are passed to the same resolvers as the HTTP server.
+var mcpTools = []mcpTool{
+	{
+		Name:        "daily_usage",
+		Description: "Daily token usage and estimated cost, grouped by cli, model, project, or account. Same rows as 'codetok daily --json'. Defaults to the last 7 days.",
+		InputSchema: mcpObjectSchema(map[string]any{
+			"since":    withDescription(mcpDateProperty, "First day to include (YYYY-MM-DD)"),
+			"until":    withDescription(mcpDateProperty, "Last day to include (YYYY-MM-DD)"),
+			"days":     map[string]any{"type": "integer", "minimum": 1, "description": "Lookback window in days when since/until are not set"},
+			"all":      map[string]any{"type": "boolean", "description": "Include all history; cannot be combined with days, since, or until"},
+			"timezone": map[string]any{"type": "string", "description": "IANA timezone for days and date arguments"},
+			"group_by": map[string]any{"type": "string", "description": "cli, model, project, account, or a comma-separated combination"},
+			"provider": map[string]any{"type": "string", "description": "Only this provider (kimi, claude, codex, gemini, opencode, cursor)"},
+		}),
+		call: func(s *usageServer, query url.Values) (any, error) {
+			daily, err := s.daily(query)
+			if daily == nil {
+				daily = []provider.DailyStats{}
+			}
+			return daily, err
+		},
+	},
+	{
+		Name:        "session_usage",
+		Description: "Per-session token usage and estimated cost, or per-project totals with group_by=project. Same rows as 'codetok session --json'.",
+		InputSchema: mcpObjectSchema(map[string]any{
+			"since":    withDescription(mcpDateProperty, "Only events on or after this day (YYYY-MM-DD)"),
+			"until":    withDescription(mcpDateProperty, "Only events on or before this day (YYYY-MM-DD)"),
+			"timezone": map[string]any{"type": "string", "description": "IANA timezone for date arguments"},
+			"group_by": map[string]any{"type": "string", "enum": []string{"session", "project"}},
+			"provider": map[string]any{"type": "string", "description": "Only this provider (kimi, claude, codex, gemini, opencode, cursor)"},
+		}),
+		call: func(s *usageServer, query url.Values) (any, error) {
+			return s.session(query)
+		},
+	},
+	{
+		Name:        "current_session_usage",
+		Description: "Token usage and estimated cost of the most recently active session. Pass provider (e.g. claude or codex) to pick your own tool's latest session, or session_id for a specific one.",
+		InputSchema: mcpObjectSchema(map[string]any{
+			"provider":   map[string]any{"type": "string", "description": "Only consider sessions of this provider"},
+			"session_id": map[string]any{"type": "string", "description": "Report this session instead of the latest one"},
+			"timezone":   map[string]any{"type": "string", "description": "IANA timezone for the reported date and last activity"},
+		}),
+		call: func(s *usageServer, query url.Values) (any, error) {
+			return s.currentSession(query)
+		},
+	},
+}
+
+func mcpObjectSchema(properties map[string]any) map[string]any {
+	return map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+}
+
+func withDescription(schema map[string]any, description string) map[string]any {
+	out := make(map[string]any, len(schema)+1)
+	for k, v := range schema {
+		out[k] = v
+	}
+	out["description"] = description
+	return out
+}
+
+// serveMCP answers newline-delimited JSON-RPC messages from in until EOF.
+func serveMCP(server *usageServer, in io.Reader, out io.Writer) error {
+	reader := bufio.NewReader(in)
+	enc := json.NewEncoder(out)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			if resp := handleMCPMessage(server, line); resp != nil {
+				if err := enc.Encode(resp); err != nil {
+					return err
+				}
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// handleMCPMessage returns the response to one message, or nil for notifications.
+func handleMCPMessage(server *usageServer, line []byte) *jsonRPCResponse {
+	var req jsonRPCRequest
+	if err := json.Unmarshal(line, &req); err != nil {
+		return mcpErrorResponse(nil, jsonRPCParseError, "parse error: "+err.Error())
+	}
+	if len(req.ID) == 0 {
+		// Notifications (initialized, cancelled, ...) need no reply.
+		return nil
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return mcpErrorResponse(req.ID, jsonRPCInvalidRequest, "invalid request")
+	}
+
+	switch req.Method {
+	case "initialize":
+		var params struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		_ = json.Unmarshal(req.Params, &params)
+		return mcpResultResponse(req.ID, map[string]any{
+			"protocolVersion": negotiateMCPProtocolVersion(params.ProtocolVersion),
+			"capabilities":    map[string]any{"tools": map[string]any{}},
+			"serverInfo":      map[string]any{"name": "codetok", "version": version},
+		})
+	case "ping":
+		return mcpResultResponse(req.ID, map[string]any{})
+	case "tools/list":
+		return mcpResultResponse(req.ID, map[string]any{"tools": mcpTools})
+	case "tools/call":
+		var params struct {
+			Name      string                     `json:"name"`
+			Arguments map[string]json.RawMessage `json:"arguments"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return mcpErrorResponse(req.ID, jsonRPCInvalidParams, "invalid tools/call params: "+err.Error())
+		}
+		tool, ok := findMCPTool(params.Name)
+		if !ok {
+			return mcpErrorResponse(req.ID, jsonRPCInvalidParams, fmt.Sprintf("unknown tool %q", params.Name))
+		}
+		return mcpResultResponse(req.ID, callMCPTool(server, tool, params.Arguments))
+	default:
+		return mcpErrorResponse(req.ID, jsonRPCMethodNotFound, fmt.Sprintf("method %q not found", req.Method))
+	}
+}
+
+func negotiateMCPProtocolVersion(requested string) string {
+	for _, supported := range mcpProtocolVersions {
+		if requested == supported {
+			return requested
+		}
+	}
+	return mcpProtocolVersions[0]
+}
+
+func findMCPTool(name string) (mcpTool, bool) {
+	for _, tool := range mcpTools {
+		if tool.Name == name {
+			return tool, true
+		}
+	}
+	return mcpTool{}, false
+}
+
+// callMCPTool reports argument and scan failures as tool errors, so the agent
+// sees why the call failed instead of a protocol error.
+func callMCPTool(server *usageServer, tool mcpTool, arguments map[string]json.RawMessage) mcpToolResult {
+	query, err := mcpToolQuery(tool, arguments)
+	if err != nil {
+		return mcpToolError(err)
+	}
+	if err := server.scan(); err != nil {
+		return mcpToolError(fmt.Errorf("scanning local usage: %w", err))
+	}
+	value, err := tool.call(server, query)
+	if err != nil {
+		return mcpToolError(err)
+	}
+	text, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return mcpToolError(err)
+	}
+	return mcpToolResult{Content: []mcpTextContent{{Type: "text", Text: string(text)}}}
+}
+
+// mcpToolQuery converts tool arguments into the query parameters the HTTP
+// handlers accept, rejecting arguments the tool's schema does not declare.
+func mcpToolQuery(tool mcpTool, arguments map[string]json.RawMessage) (url.Values, error) {
+	properties, _ := tool.InputSchema["properties"].(map[string]any)
+	names := make([]string, 0, len(arguments))
+	for name := range arguments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	query := url.Values{}
+	for _, name := range names {
+		if _, ok := properties[name]; !ok {
+			return nil, fmt.Errorf("unknown argument %q for %s", name, tool.Name)
+		}
+		var value any
+		if err := json.Unmarshal(arguments[name], &value); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", name, err)
+		}
+		key := strings.ReplaceAll(name, "_", "-")
+		switch v := value.(type) {
+		case nil:
+			continue
+		case string:
+			query.Set(key, v)
+		case bool:
+			query.Set(key, strconv.FormatBool(v))
+		case float64:
+			query.Set(key, strconv.FormatFloat(v, 'f', -1, 64))
+		default:
+			return nil, fmt.Errorf("invalid %s: expected a string, number, or boolean", name)
+		}
+	}
+	return query, nil
+}
+
+func mcpToolError(err error) mcpToolResult {
+	return mcpToolResult{Content: []mcpTextContent{{Type: "text", Text: err.Error()}}, IsError: true}
+}
+
+func mcpResultResponse(id json.RawMessage, result any) *jsonRPCResponse {
+	return &jsonRPCResponse{JSONRPC: "2.0", ID: id, Result: result}
+}
+
+func mcpErrorResponse(id json.RawMessage, code int, message string) *jsonRPCResponse {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	return &jsonRPCResponse{JSONRPC: "2.0", ID: id, Error: &jsonRPCError{Code: code, Message: message}}
+}
+
+// currentSessionJSON is a session row with the details an agent needs to
+// recognise its own session.
+type currentSessionJSON struct {
+	sessionJSON
+	Model        string `json:"model"`
+	LastActivity string `json:"last_activity"`
+}
+
+// currentSession reports the session of the latest usage event, or the
+// session named by session_id.
+func (s *usageServer) currentSession(query url.Values) (*currentSessionJSON, error) {
+	loc, err := s.location(query)
+	if err != nil {
+		return nil, err
+	}
+	providerFilter := query.Get("provider")
+	sessionID := query.Get("session-id")
+
+	var latest *provider.UsageEvent
+	events := s.snapshot()
+	for i := range events {
+		event := &events[i]
+		if providerFilter != "" && event.ProviderName != providerFilter {
+			continue
+		}
+		if sessionID != "" && sessionEventDisplayID(*event) != sessionID {
+			continue
+		}
+		if latest == nil || event.Timestamp.After(latest.Timestamp) {
+			latest = event
+		}
+	}
+	if latest == nil {
+		return nil, errors.New("no matching session found in local usage data")
+	}
+
+	key := sessionEventGroupKey(*latest)
+	var sessionEvents []provider.UsageEvent
+	for _, event := range events {
+		if sessionEventGroupKey(event) == key {
+			sessionEvents = append(sessionEvents, event)
+		}
+	}
+	session := aggregateSessionEventsWithPricing(sessionEvents, s.prices)[0]
+	return &currentSessionJSON{
+		sessionJSON:  sessionJSONRows([]provider.SessionInfo{session}, loc)[0],
+		Model:        session.ModelName,
+		LastActivity: session.EndTime.In(loc).Format(time.RFC3339),
+	}, nil
+}
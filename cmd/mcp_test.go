@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+type mcpTestResponse struct {
+	ID     json.RawMessage `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *jsonRPCError   `json:"error"`
+}
+
+// runMCPScript feeds one JSON-RPC message per line to the server and returns
+// its responses keyed by request id.
+func runMCPScript(t *testing.T, server *usageServer, messages ...string) map[string]mcpTestResponse {
+	t.Helper()
+	var out bytes.Buffer
+	if err := serveMCP(server, strings.NewReader(strings.Join(messages, "\n")), &out); err != nil {
+		t.Fatalf("serveMCP: %v", err)
+	}
+
+	responses := make(map[string]mcpTestResponse)
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var resp mcpTestResponse
+		if err := json.Unmarshal([]byte(line), &resp); err != nil {
+			t.Fatalf("decoding response %q: %v", line, err)
+		}
+		responses[string(resp.ID)] = resp
+	}
+	return responses
+}
+
+func decodeMCPToolText(t *testing.T, resp mcpTestResponse, wantError bool, into any) string {
+	t.Helper()
+	if resp.Error != nil {
+		t.Fatalf("tools/call protocol error: %+v", resp.Error)
+	}
+	var result mcpToolResult
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		t.Fatalf("decoding tool result %s: %v", resp.Result, err)
+	}
+	if result.IsError != wantError || len(result.Content) != 1 || result.Content[0].Type != "text" {
+		t.Fatalf("tool result = %+v, want isError=%v with one text item", result, wantError)
+	}
+	if into != nil {
+		if err := json.Unmarshal([]byte(result.Content[0].Text), into); err != nil {
+			t.Fatalf("decoding tool text %q: %v", result.Content[0].Text, err)
+		}
+	}
+	return result.Content[0].Text
+}
+
+func TestMCP_InitializeAndListTools(t *testing.T) {
+	server := newServeTestServer(t, serveTestProviders()...)
+
+	responses := runMCPScript(t, server,
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"script","version":"0"}}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"ping"}`,
+		`{"jsonrpc":"2.0","id":4,"method":"resources/list"}`,
+	)
+	if len(responses) != 4 {
+		t.Fatalf("responses = %v, want one per request and none for the notification", responses)
+	}
+
+	var initResult struct {
+		ProtocolVersion string `json:"protocolVersion"`
+		Capabilities    struct {
+			Tools map[string]any `json:"tools"`
+		} `json:"capabilities"`
+		ServerInfo struct {
+			Name string `json:"name"`
+		} `json:"serverInfo"`
+	}
+	if err := json.Unmarshal(responses["1"].Result, &initResult); err != nil {
+		t.Fatalf("decoding initialize: %v", err)
+	}
+	if initResult.ProtocolVersion != "2025-03-26" || initResult.Capabilities.Tools == nil || initResult.ServerInfo.Name != "codetok" {
+		t.Fatalf("initialize = %+v, want negotiated version and tools capability", initResult)
+	}
+
+	var list struct {
+		Tools []struct {
+			Name        string         `json:"name"`
+			InputSchema map[string]any `json:"inputSchema"`
+		} `json:"tools"`
+	}
+	if err := json.Unmarshal(responses["2"].Result, &list); err != nil {
+		t.Fatalf("decoding tools/list: %v", err)
+	}
+	var names []string
+	for _, tool := range list.Tools {
+		names = append(names, tool.Name)
+		if tool.InputSchema["type"] != "object" || tool.InputSchema["properties"] == nil {
+			t.Fatalf("%s input schema = %v, want object schema", tool.Name, tool.InputSchema)
+		}
+	}
+	if strings.Join(names, ",") != "daily_usage,session_usage,current_session_usage" {
+		t.Fatalf("tools = %v", names)
+	}
+
+	if responses["4"].Error == nil || responses["4"].Error.Code != jsonRPCMethodNotFound {
+		t.Fatalf("resources/list = %+v, want method not found", responses["4"])
+	}
+}
+
+func TestMCP_ToolsReturnUsage(t *testing.T) {
+	server := newServeTestServer(t, serveTestProviders()...)
+
+	responses := runMCPScript(t, server,
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"daily_usage","arguments":{"since":"2026-04-15","until":"2026-04-16","provider":"codex","group_by":"model"}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"session_usage","arguments":{"since":"2026-04-15"}}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"current_session_usage","arguments":{"provider":"codex"}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"current_session_usage","arguments":{}}}`,
+	)
+
+	var daily []struct {
+		Date       string `json:"date"`
+		GroupBy    string `json:"group_by"`
+		TokenUsage struct {
+			InputOther int `json:"input_other"`
+		} `json:"token_usage"`
+	}
+	decodeMCPToolText(t, responses["1"], false, &daily)
+	if len(daily) != 2 || daily[0].GroupBy != "model" || daily[1].TokenUsage.InputOther != 50 {
+		t.Fatalf("daily_usage = %+v, want two codex model rows", daily)
+	}
+
+	var sessions []sessionJSON
+	decodeMCPToolText(t, responses["2"], false, &sessions)
+	if len(sessions) != 2 {
+		t.Fatalf("session_usage = %+v, want codex s1 and claude c1", sessions)
+	}
+
+	var current currentSessionJSON
+	decodeMCPToolText(t, responses["3"], false, &current)
+	if current.SessionID != "s1" || current.Turns != 2 || current.TokenUsage.Total() != 180 || current.LastActivity != "2026-04-16T09:00:00Z" {
+		t.Fatalf("current codex session = %+v, want s1 with both of its events", current)
+	}
+	decodeMCPToolText(t, responses["4"], false, &current)
+	if current.ProviderName != "claude" || current.Model != `weird"model` {
+		t.Fatalf("current session = %+v, want the latest claude session", current)
+	}
+}
+
+func TestMCP_ReportsBadArgumentsAsToolErrors(t *testing.T) {
+	server := newServeTestServer(t, serveTestProviders()...)
+
+	responses := runMCPScript(t, server,
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"daily_usage","arguments":{"days":0}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"daily_usage","arguments":{"weeks":2}}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"current_session_usage","arguments":{"session_id":"missing"}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"monthly_usage"}}`,
+		`not json`,
+	)
+
+	if text := decodeMCPToolText(t, responses["1"], true, nil); !strings.Contains(text, "days") {
+		t.Fatalf("days=0 error = %q", text)
+	}
+	if text := decodeMCPToolText(t, responses["2"], true, nil); !strings.Contains(text, `unknown argument "weeks"`) {
+		t.Fatalf("unknown argument error = %q", text)
+	}
+	if text := decodeMCPToolText(t, responses["3"], true, nil); !strings.Contains(text, "no matching session") {
+		t.Fatalf("missing session error = %q", text)
+	}
+	if responses["4"].Error == nil || responses["4"].Error.Code != jsonRPCInvalidParams {
+		t.Fatalf("unknown tool = %+v, want invalid params", responses["4"])
+	}
+	if responses["null"].Error == nil || responses["null"].Error.Code != jsonRPCParseError {
+		t.Fatalf("parse error response = %+v", responses["null"])
+	}
+}
@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/miss-you/codetok/provider"
+	"github.com/miss-you/codetok/stats"
+)
+
+var weeklyCmd = &cobra.Command{
+	Use:   "weekly",
+	Short: "Show weekly token usage breakdown",
+	Long: `Show weekly token usage breakdown.
+
+Weekly buckets local usage events by event week in the selected timezone. Weeks start on Monday and are keyed by ISO week (2006-W01); with --week-start set to another day, weeks are keyed by the date of their first day. --timezone accepts an IANA timezone name; when omitted, codetok uses the local timezone.
+
+Sessions counts distinct sessions per week, so a session active on several days of one week is counted once.
+
+Reporting commands read only local session files and Cursor CSV exports already on disk. They never trigger implicit Cursor login or sync.`,
+	RunE: runWeekly,
+}
+
+var monthlyCmd = &cobra.Command{
+	Use:   "monthly",
+	Short: "Show monthly token usage breakdown",
+	Long: `Show monthly token usage breakdown.
+
+Monthly buckets local usage events by calendar month (2006-01) in the selected timezone. --timezone accepts an IANA timezone name; when omitted, codetok uses the local timezone.
+
+Sessions counts distinct sessions per month, so a session active on several days of one month is counted once.
+
+Reporting commands read only local session files and Cursor CSV exports already on disk. They never trigger implicit Cursor login or sync.`,
+	RunE: runMonthly,
+}
+
+const defaultWeeklyWeeks = 8
+const defaultMonthlyMonths = 6
+const defaultWeekStart = "monday"
+
+func init() {
+	addPeriodReportFlags(weeklyCmd, "weeks", defaultWeeklyWeeks)
+	weeklyCmd.Flags().String("week-start", defaultWeekStart, "First day of the week (e.g. monday, sunday)")
+	rootCmd.AddCommand(weeklyCmd)
+
+	addPeriodReportFlags(monthlyCmd, "months", defaultMonthlyMonths)
+	rootCmd.AddCommand(monthlyCmd)
+}
+
+// addPeriodReportFlags registers the daily report flags, with --days replaced
+// by a lookback count in the command's own period.
+func addPeriodReportFlags(cmd *cobra.Command, countFlag string, defaultCount int) {
+	cmd.Flags().Bool("json", false, "Output as JSON")
+	cmd.Flags().String("since", "", "Start date filter (format: 2006-01-02)")
+	cmd.Flags().String("until", "", "End date filter (format: 2006-01-02)")
+	cmd.Flags().Int(countFlag, defaultCount, fmt.Sprintf("Lookback window in %s, including the current one, when --since/--until are not set", countFlag))
+	cmd.Flags().Bool("all", false, "Include all historical sessions")
+	cmd.Flags().String("timezone", "", "Timezone for date filters (IANA name, default: local)")
+	cmd.Flags().String("unit", defaultTokenUnit, "Token display unit for dashboard output: raw, k, m, g")
+	cmd.Flags().String("group-by", defaultGroupBy, groupByFlagUsage)
+	cmd.Flags().Int("top", defaultTopN, "Top N groups to show in dashboard share section")
+	addUsageReportFlags(cmd)
+}
+
+func runWeekly(cmd *cobra.Command, args []string) error {
+	return runPeriodReportWithProviders(cmd, args, provider.Registry(), time.Now(), stats.PeriodWeek)
+}
+
+func runMonthly(cmd *cobra.Command, args []string) error {
+	return runPeriodReportWithProviders(cmd, args, provider.Registry(), time.Now(), stats.PeriodMonth)
+}
+
+func runPeriodReportWithProviders(cmd *cobra.Command, args []string, providers []provider.Provider, now time.Time, bucket stats.PeriodBucket) error {
+	countFlag, periodTitle := "months", "Monthly"
+	if bucket == stats.PeriodWeek {
+		countFlag, periodTitle = "weeks", "Weekly"
+	}
+
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	sinceStr, _ := cmd.Flags().GetString("since")
+	untilStr, _ := cmd.Flags().GetString("until")
+	count, _ := cmd.Flags().GetInt(countFlag)
+	allHistory, _ := cmd.Flags().GetBool("all")
+	timezoneStr, _ := cmd.Flags().GetString("timezone")
+	unitStr, _ := cmd.Flags().GetString("unit")
+	groupByStr, _ := cmd.Flags().GetString("group-by")
+	topN, _ := cmd.Flags().GetInt("top")
+	groupBy, err := resolveGroupBy(groupByStr)
+	if err != nil {
+		return err
+	}
+	if !jsonOutput && topN < 1 {
+		return fmt.Errorf("invalid --top: must be >= 1")
+	}
+	weekStart := time.Monday
+	if bucket == stats.PeriodWeek {
+		weekStartStr, _ := cmd.Flags().GetString("week-start")
+		weekStart, err = stats.ParseWeekday(weekStartStr)
+		if err != nil {
+			return fmt.Errorf("invalid --week-start: %q", weekStartStr)
+		}
+	}
+	loc, err := resolveTimezone(timezoneStr)
+	if err != nil {
+		return err
+	}
+	prices, err := resolvePricingTable(cmd)
+	if err != nil {
+		return err
+	}
+
+	since, until, err := resolvePeriodDateRange(
+		sinceStr,
+		untilStr,
+		count,
+		countFlag,
+		allHistory,
+		cmd.Flags().Changed(countFlag),
+		bucket,
+		weekStart,
+		now,
+		loc,
+	)
+	if err != nil {
+		return err
+	}
+
+	collectOpts := provider.UsageEventCollectOptions{
+		Since:    since,
+		Until:    until,
+		Location: loc,
+	}
+	sinceDate, untilDate := dailyEventFilterDates(since, until, loc)
+	aggregator := stats.NewDailyEventAggregator(groupBy, loc)
+	aggregator.SetPeriod(bucket, weekStart)
+	aggregator.SetPricing(prices)
+	periods, err := aggregateUsageEventsFromProvidersInRange(cmd, providers, collectOpts, aggregator, loc, sinceDate, untilDate)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		if periods == nil {
+			periods = []provider.DailyStats{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(periods)
+	}
+
+	unit, err := resolveTokenUnit(unitStr)
+	if err != nil {
+		return err
+	}
+
+	printPeriodDashboard(periodTitle, periods, unit, groupBy, topN)
+	return nil
+}
+
+// resolvePeriodDateRange applies the daily --since/--until/--all rules. Without
+// explicit dates, the range starts at the beginning of the period count-1
+// periods before the current one.
+func resolvePeriodDateRange(
+	sinceStr, untilStr string,
+	count int,
+	countFlag string,
+	allHistory, countChanged bool,
+	bucket stats.PeriodBucket,
+	weekStart time.Weekday,
+	now time.Time,
+	loc *time.Location,
+) (time.Time, time.Time, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+	if allHistory {
+		if sinceStr != "" || untilStr != "" || countChanged {
+			return time.Time{}, time.Time{}, fmt.Errorf("--all cannot be used with --%s, --since, or --until", countFlag)
+		}
+		return time.Time{}, time.Time{}, nil
+	}
+	if count < 1 {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid --%s: must be >= 1", countFlag)
+	}
+	if sinceStr != "" || untilStr != "" {
+		if countChanged {
+			return time.Time{}, time.Time{}, fmt.Errorf("--%s cannot be used with --since or --until", countFlag)
+		}
+		return resolveDailyDateRange(sinceStr, untilStr, 1, false, false, now, loc)
+	}
+
+	start := stats.PeriodStart(now.In(loc), bucket, weekStart)
+	if bucket == stats.PeriodWeek {
+		return start.AddDate(0, 0, -7*(count-1)), time.Time{}, nil
+	}
+	return start.AddDate(0, -(count - 1), 0), time.Time{}, nil
+}
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/miss-you/codetok/provider"
+	"github.com/miss-you/codetok/stats"
+)
+
+func newWeeklyTestCommand() *cobra.Command {
+	cmd := &cobra.Command{}
+	addPeriodReportFlags(cmd, "weeks", defaultWeeklyWeeks)
+	cmd.Flags().String("week-start", defaultWeekStart, "")
+	return cmd
+}
+
+func newMonthlyTestCommand() *cobra.Command {
+	cmd := &cobra.Command{}
+	addPeriodReportFlags(cmd, "months", defaultMonthlyMonths)
+	return cmd
+}
+
+func periodTestEvents() []provider.UsageEvent {
+	return []provider.UsageEvent{
+		{ProviderName: "codex", ModelName: "gpt-5", SessionID: "old", Timestamp: time.Date(2026, 2, 27, 9, 0, 0, 0, time.UTC), TokenUsage: provider.TokenUsage{InputOther: 1}},
+		{ProviderName: "codex", ModelName: "gpt-5", SessionID: "a", Timestamp: time.Date(2026, 3, 30, 9, 0, 0, 0, time.UTC), TokenUsage: provider.TokenUsage{InputOther: 10}},
+		{ProviderName: "codex", ModelName: "gpt-5", SessionID: "a", Timestamp: time.Date(2026, 4, 2, 9, 0, 0, 0, time.UTC), TokenUsage: provider.TokenUsage{InputOther: 20}},
+		{ProviderName: "claude", ModelName: "claude-sonnet-4-5", SessionID: "b", Timestamp: time.Date(2026, 4, 12, 9, 0, 0, 0, time.UTC), TokenUsage: provider.TokenUsage{Output: 5}},
+		{ProviderName: "claude", ModelName: "claude-sonnet-4-5", SessionID: "b", Timestamp: time.Date(2026, 4, 16, 9, 0, 0, 0, time.UTC), TokenUsage: provider.TokenUsage{Output: 7}},
+	}
+}
+
+func TestRunWeekly_JSONGroupsByISOWeek(t *testing.T) {
+	eventProvider := &collectTestUsageEventProvider{
+		collectTestProvider: collectTestProvider{name: "codex"},
+		events:              periodTestEvents(),
+	}
+	cmd := newWeeklyTestCommand()
+	for name, value := range map[string]string{"json": "true", "timezone": "UTC", "weeks": "3"} {
+		if err := cmd.Flags().Set(name, value); err != nil {
+			t.Fatalf("setting --%s: %v", name, err)
+		}
+	}
+
+	output := captureStdout(t, func() {
+		err := runPeriodReportWithProviders(cmd, nil, []provider.Provider{eventProvider}, time.Date(2026, 4, 17, 12, 0, 0, 0, time.UTC), stats.PeriodWeek)
+		if err != nil {
+			t.Fatalf("runPeriodReportWithProviders returned error: %v", err)
+		}
+	})
+
+	got := decodeDailyJSON(t, output)
+	want := []struct {
+		date     string
+		group    string
+		sessions int
+		total    int
+	}{
+		{date: "2026-W14", group: "codex", sessions: 1, total: 30},
+		{date: "2026-W15", group: "claude", sessions: 1, total: 5},
+		{date: "2026-W16", group: "claude", sessions: 1, total: 7},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d rows, want %d: %#v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		if got[i].Date != w.date || got[i].Group != w.group || got[i].Sessions != w.sessions || got[i].TokenUsage.Total() != w.total {
+			t.Fatalf("row %d = %+v, want %+v", i, got[i], w)
+		}
+	}
+}
+
+func TestRunWeekly_WeekStartKeysByFirstDay(t *testing.T) {
+	eventProvider := &collectTestUsageEventProvider{
+		collectTestProvider: collectTestProvider{name: "claude"},
+		events:              periodTestEvents()[3:],
+	}
+	cmd := newWeeklyTestCommand()
+	for name, value := range map[string]string{"json": "true", "timezone": "UTC", "week-start": "sunday"} {
+		if err := cmd.Flags().Set(name, value); err != nil {
+			t.Fatalf("setting --%s: %v", name, err)
+		}
+	}
+
+	output := captureStdout(t, func() {
+		err := runPeriodReportWithProviders(cmd, nil, []provider.Provider{eventProvider}, time.Date(2026, 4, 17, 12, 0, 0, 0, time.UTC), stats.PeriodWeek)
+		if err != nil {
+			t.Fatalf("runPeriodReportWithProviders returned error: %v", err)
+		}
+	})
+
+	got := decodeDailyJSON(t, output)
+	if len(got) != 1 || got[0].Date != "2026-04-12" || got[0].Sessions != 1 || got[0].TokenUsage.Total() != 12 {
+		t.Fatalf("rows = %#v, want one 2026-04-12 week with both claude events", got)
+	}
+}
+
+func TestRunMonthly_DashboardGroupsByModel(t *testing.T) {
+	eventProvider := &collectTestUsageEventProvider{
+		collectTestProvider: collectTestProvider{name: "codex"},
+		events:              periodTestEvents(),
+	}
+	cmd := newMonthlyTestCommand()
+	for name, value := range map[string]string{"timezone": "UTC", "months": "2", "group-by": "model", "unit": "raw"} {
+		if err := cmd.Flags().Set(name, value); err != nil {
+			t.Fatalf("setting --%s: %v", name, err)
+		}
+	}
+
+	output := captureStdout(t, func() {
+		err := runPeriodReportWithProviders(cmd, nil, []provider.Provider{eventProvider}, time.Date(2026, 4, 17, 12, 0, 0, 0, time.UTC), stats.PeriodMonth)
+		if err != nil {
+			t.Fatalf("runPeriodReportWithProviders returned error: %v", err)
+		}
+	})
+
+	assertContainsAll(t, output,
+		"Monthly Total Trend",
+		"2026-03",
+		"2026-04",
+		"Model Total Ranking",
+		"gpt-5",
+		"claude-sonnet-4-5",
+	)
+	if strings.Contains(output, "2026-02") {
+		t.Fatalf("output includes month outside the 2-month window:\n%s", output)
+	}
+}
+
+func TestResolvePeriodDateRange(t *testing.T) {
+	loc := time.UTC
+	now := time.Date(2026, 4, 16, 12, 0, 0, 0, loc)
+
+	since, until, err := resolvePeriodDateRange("", "", 2, "weeks", false, false, stats.PeriodWeek, time.Monday, now, loc)
+	if err != nil || !since.Equal(time.Date(2026, 4, 6, 0, 0, 0, 0, loc)) || !until.IsZero() {
+		t.Fatalf("weeks range = %v..%v, %v; want 2026-04-06 open-ended", since, until, err)
+	}
+	since, _, err = resolvePeriodDateRange("", "", 3, "months", false, false, stats.PeriodMonth, time.Monday, now, loc)
+	if err != nil || !since.Equal(time.Date(2026, 2, 1, 0, 0, 0, 0, loc)) {
+		t.Fatalf("months since = %v, %v; want 2026-02-01", since, err)
+	}
+
+	for _, tc := range []struct {
+		name    string
+		since   string
+		count   int
+		all     bool
+		changed bool
+		wantErr string
+	}{
+		{name: "all with count", count: 2, all: true, changed: true, wantErr: "--all cannot be used with --weeks"},
+		{name: "count with since", since: "2026-04-01", count: 2, changed: true, wantErr: "--weeks cannot be used with --since"},
+		{name: "zero count", count: 0, wantErr: "invalid --weeks"},
+	} {
+		_, _, err := resolvePeriodDateRange(tc.since, "", tc.count, "weeks", tc.all, tc.changed, stats.PeriodWeek, time.Monday, now, loc)
+		if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("%s: err = %v, want %q", tc.name, err, tc.wantErr)
+		}
+	}
+}
+
+func TestRunWeekly_RejectsInvalidWeekStart(t *testing.T) {
+	cmd := newWeeklyTestCommand()
+	if err := cmd.Flags().Set("week-start", "someday"); err != nil {
+		t.Fatal(err)
+	}
+	err := runPeriodReportWithProviders(cmd, nil, nil, time.Now(), stats.PeriodWeek)
+	if err == nil || !strings.Contains(err.Error(), "invalid --week-start") {
+		t.Fatalf("err = %v, want invalid --week-start", err)
+	}
+}
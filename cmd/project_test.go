@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/miss-you/codetok/provider"
+)
+
+func projectTestEvents() []provider.UsageEvent {
+	ts := time.Date(2026, 4, 16, 9, 0, 0, 0, time.UTC)
+	return []provider.UsageEvent{
+		{ProviderName: "claude", SessionID: "c1", WorkDirHash: "-home-dev-codetok", ProjectPath: "/home/dev/codetok", Timestamp: ts, TokenUsage: provider.TokenUsage{InputOther: 100}},
+		{ProviderName: "codex", SessionID: "x1", ProjectPath: "/home/dev/codetok", Timestamp: ts.Add(time.Hour), TokenUsage: provider.TokenUsage{InputOther: 200}},
+		{ProviderName: "codex", SessionID: "x1", ProjectPath: "/home/dev/codetok", Timestamp: ts.Add(2 * time.Hour), TokenUsage: provider.TokenUsage{Output: 50}},
+		{ProviderName: "gemini", SessionID: "g1", WorkDirHash: "3f1a9c", Timestamp: ts, TokenUsage: provider.TokenUsage{InputOther: 40}},
+	}
+}
+
+func TestRunDaily_GroupByProject(t *testing.T) {
+	eventProvider := &collectTestUsageEventProvider{
+		collectTestProvider: collectTestProvider{name: "codex"},
+		events:              projectTestEvents(),
+	}
+	cmd := newDailyTestCommand()
+	for name, value := range map[string]string{"json": "true", "all": "true", "timezone": "UTC", "group-by": "project"} {
+		if err := cmd.Flags().Set(name, value); err != nil {
+			t.Fatalf("setting --%s: %v", name, err)
+		}
+	}
+
+	output := captureStdout(t, func() {
+		if err := runDailyWithProviders(cmd, nil, []provider.Provider{eventProvider}, time.Date(2026, 4, 16, 12, 0, 0, 0, time.UTC)); err != nil {
+			t.Fatalf("runDailyWithProviders returned error: %v", err)
+		}
+	})
+
+	rows := decodeDailyJSON(t, output)
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2: %s", len(rows), output)
+	}
+	if rows[0].Group != "/home/dev/codetok" || rows[0].GroupBy != "project" || rows[0].Sessions != 2 || rows[0].TokenUsage.Total() != 350 {
+		t.Fatalf("project row = %+v, want /home/dev/codetok with 2 sessions totaling 350", rows[0])
+	}
+	if rows[1].Group != "3f1a9c" {
+		t.Fatalf("fallback row group = %q, want provider project hash", rows[1].Group)
+	}
+}
+
+func TestRunDaily_GroupByProjectDashboardTitle(t *testing.T) {
+	eventProvider := &collectTestUsageEventProvider{
+		collectTestProvider: collectTestProvider{name: "codex"},
+		events:              projectTestEvents(),
+	}
+	cmd := newDailyTestCommand()
+	for name, value := range map[string]string{"all": "true", "timezone": "UTC", "group-by": "project"} {
+		if err := cmd.Flags().Set(name, value); err != nil {
+			t.Fatalf("setting --%s: %v", name, err)
+		}
+	}
+
+	output := captureStdout(t, func() {
+		if err := runDailyWithProviders(cmd, nil, []provider.Provider{eventProvider}, time.Date(2026, 4, 16, 12, 0, 0, 0, time.UTC)); err != nil {
+			t.Fatalf("runDailyWithProviders returned error: %v", err)
+		}
+	})
+
+	assertContainsAll(t, output, "Project Total Ranking", "Top 5 Project Share", "/home/dev/codetok")
+}
+
+func TestRunSession_GroupByProject(t *testing.T) {
+	eventProvider := &collectTestUsageEventProvider{
+		collectTestProvider: collectTestProvider{name: "codex"},
+		events:              projectTestEvents(),
+	}
+
+	cmd := newSessionTestCommand()
+	for name, value := range map[string]string{"json": "true", "timezone": "UTC", "group-by": "project"} {
+		if err := cmd.Flags().Set(name, value); err != nil {
+			t.Fatalf("setting --%s: %v", name, err)
+		}
+	}
+	output := captureStdout(t, func() {
+		if err := runSessionWithProviders(cmd, nil, []provider.Provider{eventProvider}); err != nil {
+			t.Fatalf("runSessionWithProviders returned error: %v", err)
+		}
+	})
+
+	var projects []projectJSON
+	if err := json.Unmarshal([]byte(output), &projects); err != nil {
+		t.Fatalf("failed to decode JSON: %v\n%s", err, output)
+	}
+	if len(projects) != 2 {
+		t.Fatalf("got %d projects, want 2: %s", len(projects), output)
+	}
+	top := projects[0]
+	if top.Project != "/home/dev/codetok" || top.Sessions != 2 || top.Turns != 3 || top.TokenUsage.Total() != 350 {
+		t.Fatalf("top project = %+v, want /home/dev/codetok with 2 sessions, 3 turns, total 350", top)
+	}
+	if strings.Join(top.Providers, ",") != "claude,codex" {
+		t.Fatalf("Providers = %v, want [claude codex]", top.Providers)
+	}
+
+	tableCmd := newSessionTestCommand()
+	if err := tableCmd.Flags().Set("group-by", "project"); err != nil {
+		t.Fatal(err)
+	}
+	table := captureStdout(t, func() {
+		if err := runSessionWithProviders(tableCmd, nil, []provider.Provider{eventProvider}); err != nil {
+			t.Fatalf("runSessionWithProviders returned error: %v", err)
+		}
+	})
+	assertContainsAll(t, table, "Project", "Providers", "/home/dev/codetok", "claude,codex", "3f1a9c", "TOTAL")
+}
+
+func TestRunSession_JSONIncludesProjectPath(t *testing.T) {
+	eventProvider := &collectTestUsageEventProvider{
+		collectTestProvider: collectTestProvider{name: "codex"},
+		events:              projectTestEvents(),
+	}
+	cmd := newSessionTestCommand()
+	for name, value := range map[string]string{"json": "true", "timezone": "UTC"} {
+		if err := cmd.Flags().Set(name, value); err != nil {
+			t.Fatalf("setting --%s: %v", name, err)
+		}
+	}
+	output := captureStdout(t, func() {
+		if err := runSessionWithProviders(cmd, nil, []provider.Provider{eventProvider}); err != nil {
+			t.Fatalf("runSessionWithProviders returned error: %v", err)
+		}
+	})
+
+	var sessions []sessionJSON
+	if err := json.Unmarshal([]byte(output), &sessions); err != nil {
+		t.Fatalf("failed to decode JSON: %v\n%s", err, output)
+	}
+	projects := make(map[string]string)
+	for _, s := range sessions {
+		projects[s.SessionID] = s.Project
+	}
+	if projects["c1"] != "/home/dev/codetok" || projects["x1"] != "/home/dev/codetok" || projects["g1"] != "" {
+		t.Fatalf("session projects = %v, want resolved paths only", projects)
+	}
+}
+
+func TestRunSession_RejectsInvalidGroupBy(t *testing.T) {
+	cmd := newSessionTestCommand()
+	if err := cmd.Flags().Set("group-by", "model"); err != nil {
+		t.Fatal(err)
+	}
+	err := runSessionWithProviders(cmd, nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "invalid --group-by") {
+		t.Fatalf("err = %v, want invalid --group-by", err)
+	}
+}
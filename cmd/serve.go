@@ -0,0 +1,356 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/miss-you/codetok/pricing"
+	"github.com/miss-you/codetok/provider"
+	"github.com/miss-you/codetok/stats"
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve token usage as a JSON API and Prometheus metrics",
+	Long: `Serve token usage as a JSON API and Prometheus metrics.
+
+serve scans local usage once at startup and again every --interval, then answers from the latest scan:
+
+  GET /daily     same rows as 'daily --json'
+  GET /session   same rows as 'session --json'
+  GET /metrics   Prometheus text format
+
+/daily accepts the query parameters since, until, days, all, timezone, group-by, and provider with the same meaning as the daily flags; /session accepts since, until, timezone, group-by, and provider. /metrics exposes all-time token counters labeled by provider, model, and token type.
+
+Directory, pricing, cache, and archive flags apply to every scan. Like the other reporting commands, serve reads only local files and never triggers Cursor login or sync.`,
+	RunE: runServe,
+}
+
+const defaultServeAddr = "127.0.0.1:8080"
+const defaultServeInterval = 5 * time.Minute
+
+func init() {
+	serveCmd.Flags().String("addr", defaultServeAddr, "Address to listen on")
+	serveCmd.Flags().Duration("interval", defaultServeInterval, "How often to rescan local usage data")
+	serveCmd.Flags().String("timezone", "", "Default timezone for date parameters (IANA name, default: local)")
+	addUsageReportFlags(serveCmd)
+	rootCmd.AddCommand(serveCmd)
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	addr, _ := cmd.Flags().GetString("addr")
+	interval, _ := cmd.Flags().GetDuration("interval")
+	if interval <= 0 {
+		return fmt.Errorf("invalid --interval: must be > 0")
+	}
+
+	server, err := newUsageServer(cmd, provider.Registry())
+	if err != nil {
+		return err
+	}
+	if err := server.scan(); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go server.rescanEvery(ctx, interval)
+
+	httpServer := &http.Server{
+		Addr:              addr,
+		Handler:           server.handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = httpServer.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Fprintf(cmd.ErrOrStderr(), "Serving codetok usage on http://%s (rescanning every %s)\n", addr, interval)
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// usageServer answers HTTP queries from the events of its latest scan. Scans
+// reuse the command's directory, cache, and archive flags; query parameters
+// only filter and group what was scanned.
+type usageServer struct {
+	cmd       *cobra.Command
+	providers []provider.Provider
+	prices    *pricing.Table
+	loc       *time.Location
+	now       func() time.Time
+
+	mu         sync.RWMutex
+	events     []provider.UsageEvent
+	scannedAt  time.Time
+	scanErrors int
+}
+
+func newUsageServer(cmd *cobra.Command, providers []provider.Provider) (*usageServer, error) {
+	timezoneStr, _ := cmd.Flags().GetString("timezone")
+	loc, err := resolveTimezone(timezoneStr)
+	if err != nil {
+		return nil, err
+	}
+	prices, err := resolvePricingTable(cmd)
+	if err != nil {
+		return nil, err
+	}
+	return &usageServer{
+		cmd:       cmd,
+		providers: providers,
+		prices:    prices,
+		loc:       loc,
+		now:       time.Now,
+	}, nil
+}
+
+// scan collects all local usage events and replaces the served snapshot. On
+// failure the previous snapshot stays in place.
+func (s *usageServer) scan() error {
+	var events []provider.UsageEvent
+	err := forEachUsageEventFromProvidersInRange(s.cmd, s.providers, provider.UsageEventCollectOptions{}, func(event provider.UsageEvent) error {
+		events = append(events, event)
+		return nil
+	})
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		s.scanErrors++
+		return err
+	}
+	s.events = events
+	s.scannedAt = s.now()
+	return nil
+}
+
+func (s *usageServer) rescanEvery(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.scan(); err != nil {
+				fmt.Fprintf(s.cmd.ErrOrStderr(), "Warning: rescan failed, serving previous data: %v\n", err)
+			}
+		}
+	}
+}
+
+func (s *usageServer) snapshot() []provider.UsageEvent {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.events
+}
+
+func (s *usageServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/daily", s.handleDaily)
+	mux.HandleFunc("/session", s.handleSession)
+	mux.HandleFunc("/metrics", s.handleMetrics)
+	return mux
+}
+
+func (s *usageServer) handleDaily(w http.ResponseWriter, r *http.Request) {
+	daily, err := s.daily(r.URL.Query())
+	if err != nil {
+		writeServeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if daily == nil {
+		daily = []provider.DailyStats{}
+	}
+	writeServeJSON(w, daily)
+}
+
+func (s *usageServer) daily(query url.Values) ([]provider.DailyStats, error) {
+	days := defaultDailyDays
+	if value := query.Get("days"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid days: %q", value)
+		}
+		days = parsed
+	}
+	allHistory := false
+	if value := query.Get("all"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid all: %q", value)
+		}
+		allHistory = parsed
+	}
+	groupBy, err := resolveGroupBy(valueOrDefault(query.Get("group-by"), defaultGroupBy))
+	if err != nil {
+		return nil, err
+	}
+	loc, err := s.location(query)
+	if err != nil {
+		return nil, err
+	}
+	since, until, err := resolveDailyDateRange(query.Get("since"), query.Get("until"), days, allHistory, query.Has("days"), s.now(), loc)
+	if err != nil {
+		return nil, err
+	}
+
+	sinceDate, untilDate := dailyEventFilterDates(since, until, loc)
+	dateFilter := stats.NewEventDateRangeFilter(sinceDate, untilDate, loc)
+	providerFilter := query.Get("provider")
+	aggregator := stats.NewDailyEventAggregator(groupBy, loc)
+	aggregator.SetPricing(s.prices)
+	for _, event := range s.snapshot() {
+		if providerFilter != "" && event.ProviderName != providerFilter {
+			continue
+		}
+		if dateFilter.Contains(event) {
+			aggregator.Add(event)
+		}
+	}
+	return aggregator.Results(), nil
+}
+
+func (s *usageServer) handleSession(w http.ResponseWriter, r *http.Request) {
+	out, err := s.session(r.URL.Query())
+	if err != nil {
+		writeServeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeServeJSON(w, out)
+}
+
+func (s *usageServer) session(query url.Values) (any, error) {
+	byProject, err := resolveSessionGroupBy(valueOrDefault(query.Get("group-by"), defaultSessionGroupBy))
+	if err != nil {
+		return nil, err
+	}
+	loc, err := s.location(query)
+	if err != nil {
+		return nil, err
+	}
+	sinceDate, untilDate, err := resolveSessionEventFilterDates(query.Get("since"), query.Get("until"), loc)
+	if err != nil {
+		return nil, err
+	}
+
+	providerFilter := query.Get("provider")
+	var events []provider.UsageEvent
+	for _, event := range s.snapshot() {
+		if providerFilter == "" || event.ProviderName == providerFilter {
+			events = append(events, event)
+		}
+	}
+	events = stats.FilterEventsByDateRange(events, sinceDate, untilDate, loc)
+	sessions := aggregateSessionEventsWithPricing(events, s.prices)
+	if byProject {
+		return projectJSONRows(aggregateSessionsByProject(sessions)), nil
+	}
+	return sessionJSONRows(sessions, loc), nil
+}
+
+func (s *usageServer) location(query url.Values) (*time.Location, error) {
+	if value := query.Get("timezone"); value != "" {
+		return resolveTimezone(value)
+	}
+	return s.loc, nil
+}
+
+// metricTokenTypes lists the token_type label values, in the order they are
+// exported. output_reasoning is already counted in output.
+var metricTokenTypes = []struct {
+	name  string
+	value func(provider.TokenUsage) int
+}{
+	{"input_other", func(t provider.TokenUsage) int { return t.InputOther }},
+	{"input_cache_read", func(t provider.TokenUsage) int { return t.InputCacheRead }},
+	{"input_cache_creation", func(t provider.TokenUsage) int { return t.InputCacheCreate }},
+	{"output", func(t provider.TokenUsage) int { return t.Output }},
+	{"output_reasoning", func(t provider.TokenUsage) int { return t.OutputReasoning }},
+}
+
+func (s *usageServer) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	events, scannedAt, scanErrors := s.events, s.scannedAt, s.scanErrors
+	s.mu.RUnlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	writePrometheusMetrics(w, aggregateProviderModelTotals(events, s.prices), len(events), scannedAt, scanErrors)
+}
+
+func writePrometheusMetrics(w io.Writer, series []providerModelTotal, events int, scannedAt time.Time, scanErrors int) {
+	fmt.Fprintln(w, "# HELP codetok_tokens_total Tokens recorded in local usage data. output_reasoning is already included in output.")
+	fmt.Fprintln(w, "# TYPE codetok_tokens_total counter")
+	for _, s := range series {
+		for _, tokenType := range metricTokenTypes {
+			fmt.Fprintf(w, "codetok_tokens_total{provider=%s,model=%s,token_type=%s} %d\n",
+				prometheusLabel(s.Provider), prometheusLabel(s.Model), prometheusLabel(tokenType.name), tokenType.value(s.TokenUsage))
+		}
+	}
+
+	fmt.Fprintln(w, "# HELP codetok_cost_usd_total Estimated cost in USD of priced local usage.")
+	fmt.Fprintln(w, "# TYPE codetok_cost_usd_total counter")
+	for _, s := range series {
+		if s.Cost.Status == provider.CostStatusUnknown || s.Cost.Status == "" {
+			continue
+		}
+		fmt.Fprintf(w, "codetok_cost_usd_total{provider=%s,model=%s} %g\n",
+			prometheusLabel(s.Provider), prometheusLabel(s.Model), s.Cost.USD)
+	}
+
+	fmt.Fprintln(w, "# HELP codetok_usage_events Usage events in the latest scan.")
+	fmt.Fprintln(w, "# TYPE codetok_usage_events gauge")
+	fmt.Fprintf(w, "codetok_usage_events %d\n", events)
+	fmt.Fprintln(w, "# HELP codetok_last_scan_timestamp_seconds Unix time of the latest successful scan.")
+	fmt.Fprintln(w, "# TYPE codetok_last_scan_timestamp_seconds gauge")
+	fmt.Fprintf(w, "codetok_last_scan_timestamp_seconds %d\n", scannedAt.Unix())
+	fmt.Fprintln(w, "# HELP codetok_scan_errors_total Scans that failed since the server started.")
+	fmt.Fprintln(w, "# TYPE codetok_scan_errors_total counter")
+	fmt.Fprintf(w, "codetok_scan_errors_total %d\n", scanErrors)
+}
+
+var prometheusLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// prometheusLabel quotes a label value using the text format's escapes.
+func prometheusLabel(value string) string {
+	return `"` + prometheusLabelEscaper.Replace(strings.ToValidUTF8(value, "�")) + `"`
+}
+
+func valueOrDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+func writeServeJSON(w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(value)
+}
+
+func writeServeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
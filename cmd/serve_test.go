@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/miss-you/codetok/provider"
+)
+
+func newServeTestServer(t *testing.T, providers ...provider.Provider) *usageServer {
+	t.Helper()
+	cmd := &cobra.Command{}
+	cmd.Flags().String("timezone", "UTC", "")
+	cmd.Flags().String("pricing-file", "", "")
+	cmd.Flags().Bool("no-cache", true, "")
+	cmd.SetErr(io.Discard)
+
+	server, err := newUsageServer(cmd, providers)
+	if err != nil {
+		t.Fatalf("newUsageServer: %v", err)
+	}
+	server.now = func() time.Time { return time.Date(2026, 4, 16, 12, 0, 0, 0, time.UTC) }
+	if err := server.scan(); err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	return server
+}
+
+func serveTestProviders() []provider.Provider {
+	return []provider.Provider{
+		&collectTestUsageEventProvider{
+			collectTestProvider: collectTestProvider{name: "codex"},
+			events: []provider.UsageEvent{
+				{ProviderName: "codex", ModelName: "gpt-5.4", SessionID: "s1", Timestamp: time.Date(2026, 4, 15, 9, 0, 0, 0, time.UTC), TokenUsage: provider.TokenUsage{InputOther: 100, Output: 10, OutputReasoning: 4}},
+				{ProviderName: "codex", ModelName: "gpt-5.4", SessionID: "s1", Timestamp: time.Date(2026, 4, 16, 9, 0, 0, 0, time.UTC), TokenUsage: provider.TokenUsage{InputOther: 50, InputCacheRead: 20}},
+				{ProviderName: "codex", ModelName: "gpt-5.4", SessionID: "old", Timestamp: time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC), TokenUsage: provider.TokenUsage{InputOther: 1000}},
+			},
+		},
+		&collectTestUsageEventProvider{
+			collectTestProvider: collectTestProvider{name: "claude"},
+			events: []provider.UsageEvent{
+				{ProviderName: "claude", ModelName: `weird"model`, SessionID: "c1", Timestamp: time.Date(2026, 4, 16, 10, 0, 0, 0, time.UTC), TokenUsage: provider.TokenUsage{InputOther: 7, Output: 3}},
+			},
+		},
+	}
+}
+
+func serveTestGet(t *testing.T, server *usageServer, target string) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	server.handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	return rec
+}
+
+func TestServeDaily_AppliesCLIQueryParameters(t *testing.T) {
+	server := newServeTestServer(t, serveTestProviders()...)
+
+	rec := serveTestGet(t, server, "/daily?since=2026-04-15&until=2026-04-16&provider=codex")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	var daily []provider.DailyStats
+	if err := json.Unmarshal(rec.Body.Bytes(), &daily); err != nil {
+		t.Fatalf("decoding /daily: %v", err)
+	}
+	if len(daily) != 2 || daily[0].Date != "2026-04-15" || daily[1].TokenUsage.Total() != 70 {
+		t.Fatalf("daily = %+v, want two codex days inside the range", daily)
+	}
+
+	rec = serveTestGet(t, server, "/daily?all=true&group-by=model")
+	if err := json.Unmarshal(rec.Body.Bytes(), &daily); err != nil {
+		t.Fatalf("decoding /daily?all: %v", err)
+	}
+	total := 0
+	for _, row := range daily {
+		if row.GroupBy != "model" {
+			t.Fatalf("row = %+v, want model grouping", row)
+		}
+		total += row.TokenUsage.Total()
+	}
+	if total != 1190 {
+		t.Fatalf("all-history total = %d, want 1190", total)
+	}
+}
+
+func TestServeDaily_RejectsInvalidParameters(t *testing.T) {
+	server := newServeTestServer(t, serveTestProviders()...)
+
+	for _, target := range []string{
+		"/daily?days=abc",
+		"/daily?all=true&days=3",
+		"/daily?group-by=weather",
+		"/daily?timezone=Mars/Base",
+		"/session?since=yesterday",
+	} {
+		rec := serveTestGet(t, server, target)
+		if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), `"error"`) {
+			t.Fatalf("%s: status = %d, body = %s, want 400 with error", target, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestServeSession_GroupsEventsBySession(t *testing.T) {
+	server := newServeTestServer(t, serveTestProviders()...)
+
+	rec := serveTestGet(t, server, "/session?since=2026-04-15&provider=codex")
+	var sessions []sessionJSON
+	if err := json.Unmarshal(rec.Body.Bytes(), &sessions); err != nil {
+		t.Fatalf("decoding /session: %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].SessionID != "s1" || sessions[0].TokenUsage.Total() != 180 {
+		t.Fatalf("sessions = %+v, want codex session s1 with both in-range events", sessions)
+	}
+
+	rec = serveTestGet(t, server, "/session?group-by=project")
+	var projects []projectJSON
+	if err := json.Unmarshal(rec.Body.Bytes(), &projects); err != nil || len(projects) == 0 {
+		t.Fatalf("/session?group-by=project = %s (%v), want project rows", rec.Body.String(), err)
+	}
+}
+
+func TestServeMetrics_ExportsTokenCountersByProviderModelAndType(t *testing.T) {
+	server := newServeTestServer(t, serveTestProviders()...)
+
+	rec := serveTestGet(t, server, "/metrics")
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Fatalf("content type = %q, want Prometheus text format", ct)
+	}
+	assertContainsAll(t, rec.Body.String(),
+		"# TYPE codetok_tokens_total counter",
+		`codetok_tokens_total{provider="codex",model="gpt-5.4",token_type="input_other"} 1150`,
+		`codetok_tokens_total{provider="codex",model="gpt-5.4",token_type="input_cache_read"} 20`,
+		`codetok_tokens_total{provider="codex",model="gpt-5.4",token_type="output_reasoning"} 4`,
+		`codetok_tokens_total{provider="claude",model="weird\"model",token_type="output"} 3`,
+		"codetok_usage_events 4",
+		"codetok_scan_errors_total 0",
+	)
+}
+
+func TestServeScan_KeepsPreviousSnapshotOnFailure(t *testing.T) {
+	failing := &collectTestUsageEventProvider{
+		collectTestProvider: collectTestProvider{name: "codex"},
+		events: []provider.UsageEvent{
+			{ProviderName: "codex", SessionID: "s1", Timestamp: time.Date(2026, 4, 16, 9, 0, 0, 0, time.UTC), TokenUsage: provider.TokenUsage{InputOther: 5}},
+		},
+	}
+	server := newServeTestServer(t, failing)
+
+	failing.eventErr = errors.New("disk went away")
+	if err := server.scan(); err == nil {
+		t.Fatal("scan error = nil, want provider failure")
+	}
+
+	body := serveTestGet(t, server, "/metrics").Body.String()
+	assertContainsAll(t, body, "codetok_usage_events 1", "codetok_scan_errors_total 1")
+}
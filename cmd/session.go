@@ -11,11 +11,14 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/miss-you/codetok/pricing"
 	"github.com/miss-you/codetok/provider"
 	_ "github.com/miss-you/codetok/provider/claude"
 	_ "github.com/miss-you/codetok/provider/codex"
 	_ "github.com/miss-you/codetok/provider/cursor"
+	_ "github.com/miss-you/codetok/provider/gemini"
 	_ "github.com/miss-you/codetok/provider/kimi"
+	_ "github.com/miss-you/codetok/provider/opencode"
 	"github.com/miss-you/codetok/stats"
 )
 
@@ -30,32 +33,50 @@ Reporting commands read only local session files and Cursor CSV exports already
 
 Codex reads $CODEX_HOME/sessions when CODEX_HOME is set, otherwise ~/.codex/sessions.
 
+OpenCode reads $XDG_DATA_HOME/opencode/storage when XDG_DATA_HOME is set, otherwise ~/.local/share/opencode/storage.
+
+Gemini CLI reads the chat recordings in ~/.gemini/tmp/<project-hash>/chats/. Checkpoints (/chat save and --checkpointing) store conversation history without token counts, so they are not read.
+
 By default Cursor reporting scans legacy CSV files in ~/.codetok/cursor/ plus imports/ and synced/ subdirectories. Use --cursor-dir to scan only a custom local directory.`,
 	RunE: runSession,
 }
 
 func init() {
-	sessionCmd.Flags().Bool("json", false, "Output as JSON")
-	sessionCmd.Flags().String("since", "", "Start date filter (format: 2006-01-02)")
-	sessionCmd.Flags().String("until", "", "End date filter (format: 2006-01-02)")
-	sessionCmd.Flags().String("timezone", "", "Timezone for date filters (IANA name, default: local)")
-	sessionCmd.Flags().String("provider", "", "Filter by provider name (e.g. kimi, claude, codex, cursor)")
-	sessionCmd.Flags().String("base-dir", "", "Override default data directory (applies to all providers)")
-	sessionCmd.Flags().String("kimi-dir", "", "Override Kimi data directory")
-	sessionCmd.Flags().String("claude-dir", "", "Override Claude Code data directory")
-	sessionCmd.Flags().String("codex-dir", "", "Override Codex CLI data directory")
-	sessionCmd.Flags().String("cursor-dir", "", "Override Cursor CSV directory; scans only this local path and skips default Cursor imports/synced roots")
+	addSessionFlags(sessionCmd)
 	rootCmd.AddCommand(sessionCmd)
 }
 
+func addSessionFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("json", false, "Output as JSON")
+	cmd.Flags().String("since", "", "Start date filter (format: 2006-01-02)")
+	cmd.Flags().String("until", "", "End date filter (format: 2006-01-02)")
+	cmd.Flags().String("timezone", "", "Timezone for date filters (IANA name, default: local)")
+	cmd.Flags().String("group-by", defaultSessionGroupBy, "Group rows by: session, project")
+	addUsageReportFlags(cmd)
+}
+
+const defaultSessionGroupBy = "session"
+
 // sessionJSON is the JSON output representation of a session.
 type sessionJSON struct {
-	SessionID    string              `json:"session_id"`
-	ProviderName string              `json:"provider"`
-	Title        string              `json:"title"`
-	Date         string              `json:"date"`
-	Turns        int                 `json:"turns"`
-	TokenUsage   provider.TokenUsage `json:"token_usage"`
+	SessionID    string                `json:"session_id"`
+	ProviderName string                `json:"provider"`
+	Title        string                `json:"title"`
+	Project      string                `json:"project,omitempty"`
+	Date         string                `json:"date"`
+	Turns        int                   `json:"turns"`
+	TokenUsage   provider.TokenUsage   `json:"token_usage"`
+	Cost         provider.CostEstimate `json:"cost"`
+}
+
+// projectJSON is the JSON output representation of sessions grouped by project.
+type projectJSON struct {
+	Project    string                `json:"project"`
+	Providers  []string              `json:"providers"`
+	Sessions   int                   `json:"sessions"`
+	Turns      int                   `json:"turns"`
+	TokenUsage provider.TokenUsage   `json:"token_usage"`
+	Cost       provider.CostEstimate `json:"cost"`
 }
 
 func runSession(cmd *cobra.Command, args []string) error {
@@ -67,11 +88,20 @@ func runSessionWithProviders(cmd *cobra.Command, args []string, providers []prov
 	sinceStr, _ := cmd.Flags().GetString("since")
 	untilStr, _ := cmd.Flags().GetString("until")
 	timezoneStr, _ := cmd.Flags().GetString("timezone")
+	groupByStr, _ := cmd.Flags().GetString("group-by")
 
+	byProject, err := resolveSessionGroupBy(groupByStr)
+	if err != nil {
+		return err
+	}
 	loc, err := resolveTimezone(timezoneStr)
 	if err != nil {
 		return err
 	}
+	prices, err := resolvePricingTable(cmd)
+	if err != nil {
+		return err
+	}
 
 	sinceDate, untilDate, since, until, err := resolveSessionEventFilterRange(sinceStr, untilStr, loc)
 	if err != nil {
@@ -87,29 +117,73 @@ func runSessionWithProviders(cmd *cobra.Command, args []string, providers []prov
 		return err
 	}
 	events = stats.FilterEventsByDateRange(events, sinceDate, untilDate, loc)
-	allSessions := aggregateSessionEvents(events)
+	allSessions := aggregateSessionEventsWithPricing(events, prices)
+
+	if byProject {
+		projects := aggregateSessionsByProject(allSessions)
+		if jsonOutput {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(projectJSONRows(projects))
+		}
+		printProjectTable(projects)
+		return nil
+	}
 
 	if jsonOutput {
-		out := make([]sessionJSON, len(allSessions))
-		for i, s := range allSessions {
-			out[i] = sessionJSON{
-				SessionID:    s.SessionID,
-				ProviderName: s.ProviderName,
-				Title:        s.Title,
-				Date:         sessionOutputDate(s.StartTime, loc),
-				Turns:        s.Turns,
-				TokenUsage:   s.TokenUsage,
-			}
-		}
 		enc := json.NewEncoder(os.Stdout)
 		enc.SetIndent("", "  ")
-		return enc.Encode(out)
+		return enc.Encode(sessionJSONRows(allSessions, loc))
 	}
 
 	printSessionTableWithLocation(allSessions, loc)
 	return nil
 }
 
+func sessionJSONRows(sessions []provider.SessionInfo, loc *time.Location) []sessionJSON {
+	out := make([]sessionJSON, len(sessions))
+	for i, s := range sessions {
+		out[i] = sessionJSON{
+			SessionID:    s.SessionID,
+			ProviderName: s.ProviderName,
+			Title:        s.Title,
+			Project:      s.ProjectPath,
+			Date:         sessionOutputDate(s.StartTime, loc),
+			Turns:        s.Turns,
+			TokenUsage:   s.TokenUsage,
+			Cost:         s.Cost,
+		}
+	}
+	return out
+}
+
+func projectJSONRows(projects []projectTotal) []projectJSON {
+	out := make([]projectJSON, len(projects))
+	for i, p := range projects {
+		out[i] = projectJSON{
+			Project:    p.Name,
+			Providers:  p.Providers,
+			Sessions:   p.Sessions,
+			Turns:      p.Turns,
+			TokenUsage: p.TokenUsage,
+			Cost:       p.Cost,
+		}
+	}
+	return out
+}
+
+// resolveSessionGroupBy reports whether session rows should be rolled up by project.
+func resolveSessionGroupBy(groupBy string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(groupBy)) {
+	case "", "session":
+		return false, nil
+	case "project":
+		return true, nil
+	default:
+		return false, fmt.Errorf("invalid --group-by: %q (allowed: session, project)", groupBy)
+	}
+}
+
 func resolveSessionEventFilterDates(sinceStr, untilStr string, loc *time.Location) (string, string, error) {
 	sinceDate, untilDate, _, _, err := resolveSessionEventFilterRange(sinceStr, untilStr, loc)
 	return sinceDate, untilDate, err
@@ -142,6 +216,12 @@ func resolveSessionEventFilterRange(sinceStr, untilStr string, loc *time.Locatio
 }
 
 func aggregateSessionEvents(events []provider.UsageEvent) []provider.SessionInfo {
+	return aggregateSessionEventsWithPricing(events, nil)
+}
+
+// aggregateSessionEventsWithPricing groups events into sessions and, when prices
+// is non-nil, estimates each session's cost from its events' models.
+func aggregateSessionEventsWithPricing(events []provider.UsageEvent, prices *pricing.Table) []provider.SessionInfo {
 	if len(events) == 0 {
 		return nil
 	}
@@ -192,6 +272,9 @@ func aggregateSessionEvents(events []provider.UsageEvent) []provider.SessionInfo
 		if session.WorkDirHash == "" {
 			session.WorkDirHash = strings.TrimSpace(event.WorkDirHash)
 		}
+		if session.ProjectPath == "" {
+			session.ProjectPath = strings.TrimSpace(event.ProjectPath)
+		}
 		if session.StartTime.IsZero() || (!event.Timestamp.IsZero() && event.Timestamp.Before(session.StartTime)) {
 			session.StartTime = event.Timestamp
 		}
@@ -200,6 +283,9 @@ func aggregateSessionEvents(events []provider.UsageEvent) []provider.SessionInfo
 		}
 		session.Turns++
 		mergeTokenUsage(&session.TokenUsage, event.TokenUsage)
+		if prices != nil {
+			session.Cost.Add(stats.EstimateEventCost(prices, event))
+		}
 	}
 
 	rows := make([]provider.SessionInfo, 0, len(sessionMap))
@@ -271,31 +357,125 @@ func truncate(s string, max int) string {
 
 func printSessionTableWithLocation(sessions []provider.SessionInfo, loc *time.Location) {
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "Date\tProvider\tSession\tTitle\tInput\tOutput\tTotal")
+	fmt.Fprintln(w, "Date\tProvider\tSession\tTitle\tInput\tOutput\tReasoning\tTotal\tCost")
 
 	var totalUsage provider.TokenUsage
+	var totalCost provider.CostEstimate
 
 	for _, s := range sessions {
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%d\t%d\n",
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%d\t%d\t%d\t%s\n",
 			sessionOutputDate(s.StartTime, loc),
 			s.ProviderName,
 			s.SessionID,
 			truncate(s.Title, 40),
 			s.TokenUsage.TotalInput(),
 			s.TokenUsage.Output,
+			s.TokenUsage.OutputReasoning,
 			s.TokenUsage.Total(),
+			formatCost(s.Cost),
 		)
 		totalUsage.InputOther += s.TokenUsage.InputOther
 		totalUsage.Output += s.TokenUsage.Output
+		totalUsage.OutputReasoning += s.TokenUsage.OutputReasoning
 		totalUsage.InputCacheRead += s.TokenUsage.InputCacheRead
 		totalUsage.InputCacheCreate += s.TokenUsage.InputCacheCreate
+		totalCost.Add(s.Cost)
+	}
+
+	fmt.Fprintf(w, "TOTAL\t\t\t\t%d\t%d\t%d\t%d\t%s\n",
+		totalUsage.TotalInput(),
+		totalUsage.Output,
+		totalUsage.OutputReasoning,
+		totalUsage.Total(),
+		formatCost(totalCost),
+	)
+
+	w.Flush()
+	printUnpricedModelsNote(os.Stdout, totalCost)
+}
+
+type projectTotal struct {
+	Name       string
+	Providers  []string
+	Sessions   int
+	Turns      int
+	TokenUsage provider.TokenUsage
+	Cost       provider.CostEstimate
+}
+
+// aggregateSessionsByProject rolls sessions up by project, largest total first.
+func aggregateSessionsByProject(sessions []provider.SessionInfo) []projectTotal {
+	projectMap := make(map[string]*projectTotal)
+	providerSets := make(map[string]map[string]struct{})
+	for _, s := range sessions {
+		name := stats.ProjectName(s.ProjectPath, s.WorkDirHash, s.ProviderName)
+		t, ok := projectMap[name]
+		if !ok {
+			t = &projectTotal{Name: name}
+			projectMap[name] = t
+			providerSets[name] = make(map[string]struct{})
+		}
+		if s.ProviderName != "" {
+			providerSets[name][s.ProviderName] = struct{}{}
+		}
+		t.Sessions++
+		t.Turns += s.Turns
+		mergeTokenUsage(&t.TokenUsage, s.TokenUsage)
+		t.Cost.Add(s.Cost)
+	}
+
+	result := make([]projectTotal, 0, len(projectMap))
+	for name, t := range projectMap {
+		for providerName := range providerSets[name] {
+			t.Providers = append(t.Providers, providerName)
+		}
+		sort.Strings(t.Providers)
+		result = append(result, *t)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		left := result[i].TokenUsage.Total()
+		right := result[j].TokenUsage.Total()
+		if left != right {
+			return left > right
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+func printProjectTable(projects []projectTotal) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Project\tProviders\tSessions\tInput\tOutput\tReasoning\tTotal\tCost")
+
+	var totalUsage provider.TokenUsage
+	var totalCost provider.CostEstimate
+	var totalSessions int
+
+	for _, p := range projects {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%d\t%d\t%s\n",
+			p.Name,
+			strings.Join(p.Providers, ","),
+			p.Sessions,
+			p.TokenUsage.TotalInput(),
+			p.TokenUsage.Output,
+			p.TokenUsage.OutputReasoning,
+			p.TokenUsage.Total(),
+			formatCost(p.Cost),
+		)
+		mergeTokenUsage(&totalUsage, p.TokenUsage)
+		totalCost.Add(p.Cost)
+		totalSessions += p.Sessions
 	}
 
-	fmt.Fprintf(w, "TOTAL\t\t\t\t%d\t%d\t%d\n",
+	fmt.Fprintf(w, "TOTAL\t\t%d\t%d\t%d\t%d\t%d\t%s\n",
+		totalSessions,
 		totalUsage.TotalInput(),
 		totalUsage.Output,
+		totalUsage.OutputReasoning,
 		totalUsage.Total(),
+		formatCost(totalCost),
 	)
 
 	w.Flush()
+	printUnpricedModelsNote(os.Stdout, totalCost)
 }
@@ -0,0 +1,273 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/miss-you/codetok/pricing"
+	"github.com/miss-you/codetok/provider"
+	"github.com/miss-you/codetok/stats"
+)
+
+var sessionShowCmd = &cobra.Command{
+	Use:   "show <session-id>",
+	Short: "Show every usage event of one session",
+	Long: `Show every usage event of one session.
+
+show prints the session's usage events in order with their input, cache read, cache write, and output tokens and the running cumulative total, followed by a sparkline of context growth. Context is the prompt size of each turn: input plus cache read and cache write tokens.
+
+The session is looked up across all providers by the ID shown in "codetok session"; events without a session ID are matched by their source path or event ID. Use --provider when the same ID exists in more than one provider, and --since/--until to narrow the scan of large histories.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSessionShow,
+}
+
+// sessionSparkWidth caps the context sparkline; longer sessions are
+// downsampled to their per-bucket peak.
+const sessionSparkWidth = 60
+
+var sparkLevels = []rune("▁▂▃▄▅▆▇█")
+
+func init() {
+	addSessionShowFlags(sessionShowCmd)
+	sessionCmd.AddCommand(sessionShowCmd)
+}
+
+func addSessionShowFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("json", false, "Output as JSON")
+	cmd.Flags().String("since", "", "Start date filter (format: 2006-01-02)")
+	cmd.Flags().String("until", "", "End date filter (format: 2006-01-02)")
+	cmd.Flags().String("timezone", "", "Timezone for timestamps and date filters (IANA name, default: local)")
+	addUsageReportFlags(cmd)
+}
+
+// sessionTurnJSON is the JSON output representation of one usage event.
+type sessionTurnJSON struct {
+	Timestamp       string                `json:"timestamp"`
+	Model           string                `json:"model"`
+	TokenUsage      provider.TokenUsage   `json:"token_usage"`
+	ContextTokens   int                   `json:"context_tokens"`
+	CumulativeTotal int                   `json:"cumulative_total"`
+	Cost            provider.CostEstimate `json:"cost"`
+}
+
+// sessionDetailJSON is the JSON output of "session show".
+type sessionDetailJSON struct {
+	sessionJSON
+	Models []string          `json:"models"`
+	Start  string            `json:"start"`
+	End    string            `json:"end"`
+	Events []sessionTurnJSON `json:"events"`
+}
+
+func runSessionShow(cmd *cobra.Command, args []string) error {
+	return runSessionShowWithProviders(cmd, args, provider.Registry())
+}
+
+func runSessionShowWithProviders(cmd *cobra.Command, args []string, providers []provider.Provider) error {
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	sinceStr, _ := cmd.Flags().GetString("since")
+	untilStr, _ := cmd.Flags().GetString("until")
+	timezoneStr, _ := cmd.Flags().GetString("timezone")
+	sessionID := strings.TrimSpace(args[0])
+	if sessionID == "" {
+		return fmt.Errorf("session ID must not be empty")
+	}
+
+	loc, err := resolveTimezone(timezoneStr)
+	if err != nil {
+		return err
+	}
+	prices, err := resolvePricingTable(cmd)
+	if err != nil {
+		return err
+	}
+	sinceDate, untilDate, since, until, err := resolveSessionEventFilterRange(sinceStr, untilStr, loc)
+	if err != nil {
+		return err
+	}
+
+	dateFilter := stats.NewEventDateRangeFilter(sinceDate, untilDate, loc)
+	byKey := make(map[string][]provider.UsageEvent)
+	err = forEachUsageEventFromProvidersInRange(cmd, providers, provider.UsageEventCollectOptions{
+		Since:    since,
+		Until:    until,
+		Location: loc,
+	}, func(event provider.UsageEvent) error {
+		if sessionEventDisplayID(event) == sessionID && dateFilter.Contains(event) {
+			key := sessionEventGroupKey(event)
+			byKey[key] = append(byKey[key], event)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(byKey) == 0 {
+		return fmt.Errorf("session %q not found in local usage data", sessionID)
+	}
+	var events []provider.UsageEvent
+	var names []string
+	for _, keyEvents := range byKey {
+		events = keyEvents
+		names = append(names, strings.TrimSpace(keyEvents[0].ProviderName))
+	}
+	if len(byKey) > 1 {
+		sort.Strings(names)
+		return fmt.Errorf("session %q found in more than one provider (%s); use --provider", sessionID, strings.Join(names, ", "))
+	}
+	sortSessionTurns(events)
+
+	session := aggregateSessionEventsWithPricing(events, prices)[0]
+	detail := buildSessionDetail(session, events, prices, loc)
+	out := cmd.OutOrStdout()
+	if jsonOutput {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(detail)
+	}
+	printSessionDetail(out, detail)
+	return nil
+}
+
+// sortSessionTurns orders events by timestamp, keeping events without one last
+// in their collected order.
+func sortSessionTurns(events []provider.UsageEvent) {
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].Timestamp.IsZero() || events[j].Timestamp.IsZero() {
+			return !events[i].Timestamp.IsZero() && events[j].Timestamp.IsZero()
+		}
+		return events[i].Timestamp.Before(events[j].Timestamp)
+	})
+}
+
+func buildSessionDetail(session provider.SessionInfo, events []provider.UsageEvent, prices *pricing.Table, loc *time.Location) sessionDetailJSON {
+	detail := sessionDetailJSON{
+		sessionJSON: sessionJSONRows([]provider.SessionInfo{session}, loc)[0],
+		Models:      []string{},
+		Start:       sessionOutputTimestamp(session.StartTime, loc),
+		End:         sessionOutputTimestamp(session.EndTime, loc),
+		Events:      make([]sessionTurnJSON, 0, len(events)),
+	}
+	seenModels := make(map[string]bool)
+	cumulative := 0
+	for _, event := range events {
+		model := strings.TrimSpace(event.ModelName)
+		if model != "" && !seenModels[model] {
+			seenModels[model] = true
+			detail.Models = append(detail.Models, model)
+		}
+		cumulative += event.TokenUsage.Total()
+		turn := sessionTurnJSON{
+			Timestamp:       sessionOutputTimestamp(event.Timestamp, loc),
+			Model:           model,
+			TokenUsage:      event.TokenUsage,
+			ContextTokens:   event.TokenUsage.TotalInput(),
+			CumulativeTotal: cumulative,
+		}
+		if prices != nil {
+			turn.Cost = stats.EstimateEventCost(prices, event)
+		}
+		detail.Events = append(detail.Events, turn)
+	}
+	return detail
+}
+
+func sessionOutputTimestamp(ts time.Time, loc *time.Location) string {
+	if ts.IsZero() {
+		return ""
+	}
+	return ts.In(loc).Format(time.RFC3339)
+}
+
+func printSessionDetail(out io.Writer, detail sessionDetailJSON) {
+	fmt.Fprintf(out, "Session:  %s (%s)\n", detail.SessionID, detail.ProviderName)
+	if detail.Title != "" {
+		fmt.Fprintf(out, "Title:    %s\n", detail.Title)
+	}
+	if detail.Project != "" {
+		fmt.Fprintf(out, "Project:  %s\n", detail.Project)
+	}
+	if len(detail.Models) > 0 {
+		fmt.Fprintf(out, "Models:   %s\n", strings.Join(detail.Models, ", "))
+	}
+	if detail.Start != "" {
+		fmt.Fprintf(out, "Time:     %s → %s\n", detail.Start, detail.End)
+	}
+	fmt.Fprintf(out, "Turns:    %d  Total: %d  Cost: %s\n\n", detail.Turns, detail.TokenUsage.Total(), formatCost(detail.Cost))
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "#\tTime\tModel\tInput\tCache Read\tCache Write\tOutput\tReasoning\tCumulative\tCost")
+	contexts := make([]int, 0, len(detail.Events))
+	for i, turn := range detail.Events {
+		timestamp := "-"
+		if turn.Timestamp != "" {
+			parsed, _ := time.Parse(time.RFC3339, turn.Timestamp)
+			timestamp = parsed.Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%d\t%d\t%d\t%d\t%d\t%d\t%s\n",
+			i+1,
+			timestamp,
+			turn.Model,
+			turn.TokenUsage.InputOther,
+			turn.TokenUsage.InputCacheRead,
+			turn.TokenUsage.InputCacheCreate,
+			turn.TokenUsage.Output,
+			turn.TokenUsage.OutputReasoning,
+			turn.CumulativeTotal,
+			formatCost(turn.Cost),
+		)
+		contexts = append(contexts, turn.ContextTokens)
+	}
+	w.Flush()
+
+	peak, peakTurn := 0, 0
+	for i, tokens := range contexts {
+		if tokens > peak {
+			peak, peakTurn = tokens, i+1
+		}
+	}
+	fmt.Fprintf(out, "\nContext: %s", sparkline(contexts, sessionSparkWidth))
+	if peak > 0 {
+		fmt.Fprintf(out, "  peak %d tokens at turn %d", peak, peakTurn)
+	}
+	fmt.Fprintln(out)
+	printUnpricedModelsNote(out, detail.Cost)
+}
+
+// sparkline draws values as block characters scaled to the largest value.
+// More than width values are downsampled to the peak of each bucket.
+func sparkline(values []int, width int) string {
+	if len(values) > width {
+		buckets := make([]int, width)
+		for i, v := range values {
+			b := i * width / len(values)
+			if v > buckets[b] {
+				buckets[b] = v
+			}
+		}
+		values = buckets
+	}
+	peak := 0
+	for _, v := range values {
+		if v > peak {
+			peak = v
+		}
+	}
+	var b strings.Builder
+	for _, v := range values {
+		level := 0
+		if peak > 0 && v > 0 {
+			level = (v*len(sparkLevels) - 1) / peak
+		}
+		b.WriteRune(sparkLevels[level])
+	}
+	return b.String()
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/miss-you/codetok/provider"
+)
+
+func sessionShowTestProviders() []provider.Provider {
+	day := time.Date(2026, 4, 15, 0, 0, 0, 0, time.UTC)
+	return []provider.Provider{
+		&collectTestUsageEventProvider{
+			collectTestProvider: collectTestProvider{name: "claude"},
+			events: []provider.UsageEvent{
+				{ProviderName: "claude", ModelName: "claude-sonnet-4-5", SessionID: "s1", Title: "Refactor", Timestamp: day.Add(10*time.Hour + 5*time.Minute), TokenUsage: provider.TokenUsage{InputOther: 100, InputCacheRead: 4000, InputCacheCreate: 900, Output: 200}},
+				{ProviderName: "claude", ModelName: "claude-sonnet-4-5", SessionID: "s1", Timestamp: day.Add(10 * time.Hour), TokenUsage: provider.TokenUsage{InputOther: 50, InputCacheCreate: 1000, Output: 100}},
+				{ProviderName: "claude", ModelName: "claude-sonnet-4-5", SessionID: "other", Timestamp: day.Add(11 * time.Hour), TokenUsage: provider.TokenUsage{InputOther: 7}},
+				{ProviderName: "claude", ModelName: "claude-sonnet-4-5", SourcePath: "/logs/no-id.jsonl", Timestamp: day.Add(12 * time.Hour), TokenUsage: provider.TokenUsage{Output: 9}},
+			},
+		},
+		&collectTestUsageEventProvider{
+			collectTestProvider: collectTestProvider{name: "codex"},
+			events: []provider.UsageEvent{
+				{ProviderName: "codex", ModelName: "gpt-5.4", SessionID: "shared", Timestamp: day, TokenUsage: provider.TokenUsage{InputOther: 1}},
+			},
+		},
+		&collectTestUsageEventProvider{
+			collectTestProvider: collectTestProvider{name: "kimi"},
+			events: []provider.UsageEvent{
+				{ProviderName: "kimi", ModelName: "kimi-k2", SessionID: "shared", Timestamp: day, TokenUsage: provider.TokenUsage{InputOther: 2}},
+			},
+		},
+	}
+}
+
+func TestRunSessionShow_PrintsTurnsInOrderWithCumulativeTotals(t *testing.T) {
+	cmd, out := newFlagTestCommand(t, addSessionShowFlags, map[string]string{"timezone": "UTC"})
+	if err := runSessionShowWithProviders(cmd, []string{"s1"}, sessionShowTestProviders()); err != nil {
+		t.Fatalf("runSessionShowWithProviders: %v", err)
+	}
+
+	assertContainsAll(t, out.String(),
+		"Session:  s1 (claude)",
+		"Title:    Refactor",
+		"Turns:    2  Total: 6350",
+		"1  2026-04-15 10:00:00  claude-sonnet-4-5  50     0           1000         100     0          1150",
+		"2  2026-04-15 10:05:00  claude-sonnet-4-5  100    4000        900          200     0          6350",
+		"Context: ▂█  peak 5000 tokens at turn 2",
+	)
+	if strings.Contains(out.String(), "other") {
+		t.Fatalf("output includes another session:\n%s", out.String())
+	}
+
+	cmd, out = newFlagTestCommand(t, addSessionShowFlags, map[string]string{"timezone": "UTC"})
+	if err := runSessionShowWithProviders(cmd, []string{"/logs/no-id.jsonl"}, sessionShowTestProviders()); err != nil {
+		t.Fatalf("runSessionShowWithProviders by source path: %v", err)
+	}
+	assertContainsAll(t, out.String(), "Turns:    1  Total: 9")
+}
+
+func TestRunSessionShow_JSONAndLookupErrors(t *testing.T) {
+	cmd, out := newFlagTestCommand(t, addSessionShowFlags, map[string]string{"json": "true", "timezone": "UTC"})
+	if err := runSessionShowWithProviders(cmd, []string{"s1"}, sessionShowTestProviders()); err != nil {
+		t.Fatalf("runSessionShowWithProviders --json: %v", err)
+	}
+	var got sessionDetailJSON
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("decoding %q: %v", out.String(), err)
+	}
+	if got.SessionID != "s1" || got.Turns != 2 || got.Start != "2026-04-15T10:00:00Z" || len(got.Models) != 1 {
+		t.Fatalf("session detail = %+v", got)
+	}
+	if len(got.Events) != 2 || got.Events[0].ContextTokens != 1050 || got.Events[1].CumulativeTotal != 6350 {
+		t.Fatalf("events = %+v", got.Events)
+	}
+
+	cmd, _ = newFlagTestCommand(t, addSessionShowFlags, map[string]string{"timezone": "UTC"})
+	err := runSessionShowWithProviders(cmd, []string{"shared"}, sessionShowTestProviders())
+	if err == nil || !strings.Contains(err.Error(), "codex, kimi") {
+		t.Fatalf("err = %v, want ambiguous session error naming both providers", err)
+	}
+	cmd, out = newFlagTestCommand(t, addSessionShowFlags, map[string]string{"provider": "kimi", "timezone": "UTC"})
+	if err := runSessionShowWithProviders(cmd, []string{"shared"}, sessionShowTestProviders()); err != nil {
+		t.Fatalf("runSessionShowWithProviders --provider: %v", err)
+	}
+	assertContainsAll(t, out.String(), "Session:  shared (kimi)")
+
+	cmd, _ = newFlagTestCommand(t, addSessionShowFlags, map[string]string{"timezone": "UTC"})
+	if err := runSessionShowWithProviders(cmd, []string{"missing"}, sessionShowTestProviders()); err == nil {
+		t.Fatal("expected error for unknown session")
+	}
+}
+
+func TestSparklineDownsamplesToPeaks(t *testing.T) {
+	if got := sparkline([]int{0, 1, 4, 8}, 10); got != "▁▁▄█" {
+		t.Fatalf("sparkline = %q", got)
+	}
+	if got := sparkline([]int{1, 8, 1, 1, 4, 1}, 3); got != "█▁▄" {
+		t.Fatalf("downsampled sparkline = %q", got)
+	}
+}
@@ -322,6 +322,63 @@ func TestRunSession_TableUsesEventAggregation(t *testing.T) {
 	}
 }
 
+func TestRunSession_ReportsReasoningOutput(t *testing.T) {
+	eventProvider := &collectTestUsageEventProvider{
+		collectTestProvider: collectTestProvider{name: "codex"},
+		events: []provider.UsageEvent{
+			{
+				ProviderName: "codex",
+				SessionID:    "reasoning-session",
+				Timestamp:    time.Date(2026, 4, 16, 9, 0, 0, 0, time.UTC),
+				TokenUsage:   provider.TokenUsage{InputOther: 100, Output: 40, OutputReasoning: 25},
+			},
+			{
+				ProviderName: "codex",
+				SessionID:    "reasoning-session",
+				Timestamp:    time.Date(2026, 4, 16, 10, 0, 0, 0, time.UTC),
+				TokenUsage:   provider.TokenUsage{InputOther: 50, Output: 30, OutputReasoning: 12},
+			},
+		},
+	}
+
+	cmd := newSessionTestCommand()
+	if err := cmd.Flags().Set("timezone", "UTC"); err != nil {
+		t.Fatalf("setting --timezone: %v", err)
+	}
+	if err := cmd.Flags().Set("json", "true"); err != nil {
+		t.Fatalf("setting --json: %v", err)
+	}
+	output := captureStdout(t, func() {
+		if err := runSessionWithProviders(cmd, nil, []provider.Provider{eventProvider}); err != nil {
+			t.Fatalf("runSessionWithProviders returned error: %v", err)
+		}
+	})
+	var sessions []sessionJSON
+	if err := json.Unmarshal([]byte(output), &sessions); err != nil {
+		t.Fatalf("failed to decode JSON: %v\n%s", err, output)
+	}
+	if len(sessions) != 1 {
+		t.Fatalf("got %d sessions, want 1", len(sessions))
+	}
+	if got := sessions[0].TokenUsage; got.OutputReasoning != 37 || got.Output != 70 || got.Total() != 220 {
+		t.Fatalf("token usage = %+v, want reasoning 37 within output 70 and total 220", got)
+	}
+	if !strings.Contains(output, `"output_reasoning": 37`) {
+		t.Fatalf("JSON output missing output_reasoning:\n%s", output)
+	}
+
+	tableCmd := newSessionTestCommand()
+	if err := tableCmd.Flags().Set("timezone", "UTC"); err != nil {
+		t.Fatalf("setting --timezone: %v", err)
+	}
+	table := captureStdout(t, func() {
+		if err := runSessionWithProviders(tableCmd, nil, []provider.Provider{eventProvider}); err != nil {
+			t.Fatalf("runSessionWithProviders returned error: %v", err)
+		}
+	})
+	assertContainsAll(t, table, "Reasoning", "reasoning-session", "37")
+}
+
 func TestRunSession_InvalidTimezone(t *testing.T) {
 	cmd := newSessionTestCommand()
 	if err := cmd.Flags().Set("timezone", "not/a-zone"); err != nil {
@@ -498,15 +555,7 @@ func decodeSessionJSON(t *testing.T, output string) []sessionJSON {
 
 func newSessionTestCommand() *cobra.Command {
 	cmd := &cobra.Command{}
-	cmd.Flags().Bool("json", false, "")
-	cmd.Flags().String("since", "", "")
-	cmd.Flags().String("until", "", "")
-	cmd.Flags().String("timezone", "", "")
-	cmd.Flags().String("provider", "", "")
-	cmd.Flags().String("base-dir", "", "")
-	cmd.Flags().String("kimi-dir", "", "")
-	cmd.Flags().String("claude-dir", "", "")
-	cmd.Flags().String("codex-dir", "", "")
-	cmd.Flags().String("cursor-dir", "", "")
+	addSessionFlags(cmd)
+	disableTestUsageEventCache(cmd)
 	return cmd
 }
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/miss-you/codetok/provider"
+	"github.com/miss-you/codetok/provider/claude"
+	"github.com/miss-you/codetok/stats"
+)
+
+var statuslineCmd = &cobra.Command{
+	Use:   "statusline",
+	Short: "Print a one-line usage summary for the Claude Code statusline",
+	Long: `Print a one-line usage summary for the Claude Code statusline.
+
+Claude Code runs the statusline command with a JSON payload on stdin that names the current session's transcript. statusline parses only that transcript for the session total, and only today's files of every provider for the day total, so it stays fast enough to run after every message. Add it to ~/.claude/settings.json:
+
+  "statusLine": {"type": "command", "command": "codetok statusline"}`,
+	RunE: runStatusline,
+}
+
+func init() {
+	statuslineCmd.Flags().Bool("cost", false, "Append estimated session and day cost")
+	statuslineCmd.Flags().String("timezone", "", "Timezone that decides today (IANA name, default: local)")
+	addUsageReportFlags(statuslineCmd)
+	rootCmd.AddCommand(statuslineCmd)
+}
+
+// statuslinePayload is the part of Claude Code's statusline input codetok uses.
+type statuslinePayload struct {
+	SessionID      string `json:"session_id"`
+	TranscriptPath string `json:"transcript_path"`
+	Model          struct {
+		ID          string `json:"id"`
+		DisplayName string `json:"display_name"`
+	} `json:"model"`
+}
+
+func runStatusline(cmd *cobra.Command, args []string) error {
+	return runStatuslineWithProviders(cmd, provider.Registry(), time.Now())
+}
+
+func runStatuslineWithProviders(cmd *cobra.Command, providers []provider.Provider, now time.Time) error {
+	showCost, _ := cmd.Flags().GetBool("cost")
+	timezone, _ := cmd.Flags().GetString("timezone")
+	loc, err := resolveTimezone(timezone)
+	if err != nil {
+		return err
+	}
+	prices, err := resolvePricingTable(cmd)
+	if err != nil {
+		return err
+	}
+
+	var payload statuslinePayload
+	if err := json.NewDecoder(cmd.InOrStdin()).Decode(&payload); err != nil && err != io.EOF {
+		return fmt.Errorf("invalid statusline payload: %w", err)
+	}
+
+	cache := openUsageEventCache(cmd)
+	// The cache only speeds up later runs, so failing to save it is not an error.
+	defer cache.Save()
+	opts := provider.UsageEventCollectOptions{Location: loc, Cache: cache}
+
+	var sessionEvents []provider.UsageEvent
+	if path := strings.TrimSpace(payload.TranscriptPath); path != "" {
+		sessionEvents, err = (&claude.Provider{}).CollectTranscriptUsageEvents(path, opts)
+		// The transcript appears with the first message, so a missing file is an empty session.
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("reading transcript: %w", err)
+		}
+	}
+
+	opts.Since, opts.Until, err = resolveDailyDateRange("", "", 1, false, false, now, loc)
+	if err != nil {
+		return err
+	}
+	sinceDate, untilDate := dailyEventFilterDates(opts.Since, opts.Until, loc)
+	today := stats.NewEventDateRangeFilter(sinceDate, untilDate, loc)
+	var todayUsage provider.TokenUsage
+	var todayCost provider.CostEstimate
+	err = forEachUsageEventFromProvidersInRange(cmd, providers, opts, func(event provider.UsageEvent) error {
+		if today.Contains(event) {
+			mergeTokenUsage(&todayUsage, event.TokenUsage)
+			todayCost.Add(stats.EstimateEventCost(prices, event))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var sessionUsage provider.TokenUsage
+	var sessionCost provider.CostEstimate
+	model := payload.Model.DisplayName
+	if model == "" {
+		model = payload.Model.ID
+	}
+	for _, event := range sessionEvents {
+		mergeTokenUsage(&sessionUsage, event.TokenUsage)
+		sessionCost.Add(stats.EstimateEventCost(prices, event))
+		if payload.Model.DisplayName == "" && payload.Model.ID == "" && event.ModelName != "" {
+			model = event.ModelName
+		}
+	}
+
+	parts := make([]string, 0, 3)
+	if model != "" {
+		parts = append(parts, model)
+	}
+	session := "session " + formatCompactTokens(sessionUsage.Total()) + " tok"
+	day := "today " + formatCompactTokens(todayUsage.Total()) + " tok"
+	if showCost {
+		session += " " + formatCost(sessionCost)
+		day += " " + formatCost(todayCost)
+	}
+	parts = append(parts, session, day)
+	fmt.Fprintln(cmd.OutOrStdout(), strings.Join(parts, " | "))
+	return nil
+}
+
+// formatCompactTokens abbreviates token counts for narrow displays, e.g. 45.2k.
+func formatCompactTokens(n int) string {
+	switch {
+	case n >= 1_000_000_000:
+		return fmt.Sprintf("%.1fB", float64(n)/1_000_000_000)
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1_000_000)
+	case n >= 1_000:
+		return fmt.Sprintf("%.1fk", float64(n)/1_000)
+	default:
+		return fmt.Sprintf("%d", n)
+	}
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/miss-you/codetok/provider"
+)
+
+func newStatuslineTestCommand(t *testing.T, payload string, cost bool) (*cobra.Command, *bytes.Buffer) {
+	t.Helper()
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool("cost", cost, "")
+	cmd.Flags().String("timezone", "UTC", "")
+	cmd.Flags().String("pricing-file", "", "")
+	cmd.Flags().Bool("no-cache", true, "")
+	cmd.SetIn(strings.NewReader(payload))
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	return cmd, &out
+}
+
+func TestStatusline_SummarizesTranscriptAndToday(t *testing.T) {
+	now := time.Date(2026, 4, 16, 12, 0, 0, 0, time.UTC)
+	transcript := filepath.Join(t.TempDir(), "-work-repo", "s1.jsonl")
+	if err := os.MkdirAll(filepath.Dir(transcript), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	var lines string
+	for i, input := range []int{40000, 5000} {
+		lines += fmt.Sprintf(`{"type":"assistant","requestId":"req-%d","sessionId":"s1","timestamp":"2026-04-16T11:0%d:00Z","message":{"id":"msg-%d","model":"claude-sonnet-4-5","role":"assistant","content":[],"usage":{"input_tokens":%d,"output_tokens":200}}}`+"\n", i, i, i, input)
+	}
+	if err := os.WriteFile(transcript, []byte(lines), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	codex := &collectTestUsageEventProvider{
+		collectTestProvider: collectTestProvider{name: "codex"},
+		events: []provider.UsageEvent{
+			{ProviderName: "codex", ModelName: "gpt-5.4", SessionID: "x", Timestamp: now.Add(-time.Hour), TokenUsage: provider.TokenUsage{InputOther: 1_200_000}},
+			{ProviderName: "codex", ModelName: "gpt-5.4", SessionID: "y", Timestamp: now.AddDate(0, 0, -1), TokenUsage: provider.TokenUsage{InputOther: 9_000_000}},
+		},
+	}
+	payload := fmt.Sprintf(`{"session_id":"s1","transcript_path":%q,"model":{"id":"claude-opus-4-1","display_name":"Opus"}}`, transcript)
+	cmd, out := newStatuslineTestCommand(t, payload, false)
+	if err := runStatuslineWithProviders(cmd, []provider.Provider{codex}, now); err != nil {
+		t.Fatalf("runStatuslineWithProviders: %v", err)
+	}
+	if got, want := out.String(), "Opus | session 45.4k tok | today 1.2M tok\n"; got != want {
+		t.Fatalf("statusline = %q, want %q", got, want)
+	}
+	if len(codex.seenRangeOpts) != 1 || !codex.seenRangeOpts[0].Since.Equal(time.Date(2026, 4, 16, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("range opts = %+v, want collection limited to today", codex.seenRangeOpts)
+	}
+
+	cmd, out = newStatuslineTestCommand(t, `{"transcript_path":"`+transcript+`"}`, true)
+	if err := runStatuslineWithProviders(cmd, nil, now); err != nil {
+		t.Fatalf("runStatuslineWithProviders with cost: %v", err)
+	}
+	assertContainsAll(t, out.String(), "claude-sonnet-4-5 | session 45.4k tok $", "| today 0 tok $0.00")
+}
+
+func TestStatusline_ToleratesMissingTranscript(t *testing.T) {
+	payload := `{"session_id":"new","transcript_path":"` + filepath.Join(t.TempDir(), "missing.jsonl") + `","model":{"display_name":"Sonnet"}}`
+	cmd, out := newStatuslineTestCommand(t, payload, false)
+	if err := runStatuslineWithProviders(cmd, nil, time.Now()); err != nil {
+		t.Fatalf("runStatuslineWithProviders: %v", err)
+	}
+	if got := out.String(); got != "Sonnet | session 0 tok | today 0 tok\n" {
+		t.Fatalf("statusline = %q", got)
+	}
+
+	cmd, _ = newStatuslineTestCommand(t, "not json", false)
+	if err := runStatuslineWithProviders(cmd, nil, time.Now()); err == nil || !strings.Contains(err.Error(), "invalid statusline payload") {
+		t.Fatalf("error = %v, want invalid payload", err)
+	}
+}
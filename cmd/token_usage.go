@@ -1,10 +1,63 @@
 package cmd
 
-import "github.com/miss-you/codetok/provider"
+import (
+	"sort"
+	"time"
+
+	"github.com/miss-you/codetok/pricing"
+	"github.com/miss-you/codetok/provider"
+	"github.com/miss-you/codetok/stats"
+)
 
 func mergeTokenUsage(dst *provider.TokenUsage, src provider.TokenUsage) {
 	dst.InputOther += src.InputOther
 	dst.Output += src.Output
+	dst.OutputReasoning += src.OutputReasoning
 	dst.InputCacheRead += src.InputCacheRead
 	dst.InputCacheCreate += src.InputCacheCreate
 }
+
+// providerModelTotal is the all-time usage of one model within one provider.
+type providerModelTotal struct {
+	Provider   string                `json:"provider"`
+	Model      string                `json:"model"`
+	TokenUsage provider.TokenUsage   `json:"token_usage"`
+	Cost       provider.CostEstimate `json:"cost"`
+}
+
+// aggregateProviderModelTotals sums events per provider and model, naming
+// models the way 'daily --group-by cli,model' does. Rows are sorted by provider
+// and model.
+func aggregateProviderModelTotals(events []provider.UsageEvent, prices *pricing.Table) []providerModelTotal {
+	aggregator := stats.NewDailyEventAggregator(stats.CompositeDimension(stats.AggregateDimensionCLI, stats.AggregateDimensionModel), time.UTC)
+	aggregator.SetPricing(prices)
+	for _, event := range events {
+		aggregator.Add(event)
+	}
+
+	byKey := make(map[string]*providerModelTotal)
+	for _, day := range aggregator.Results() {
+		providerName := day.Groups[string(stats.AggregateDimensionCLI)]
+		model := day.Groups[string(stats.AggregateDimensionModel)]
+		key := providerName + "\x00" + model
+		total, ok := byKey[key]
+		if !ok {
+			total = &providerModelTotal{Provider: providerName, Model: model}
+			byKey[key] = total
+		}
+		mergeTokenUsage(&total.TokenUsage, day.TokenUsage)
+		total.Cost.Add(day.Cost)
+	}
+
+	totals := make([]providerModelTotal, 0, len(byKey))
+	for _, total := range byKey {
+		totals = append(totals, *total)
+	}
+	sort.Slice(totals, func(i, j int) bool {
+		if totals[i].Provider != totals[j].Provider {
+			return totals[i].Provider < totals[j].Provider
+		}
+		return totals[i].Model < totals[j].Model
+	})
+	return totals
+}
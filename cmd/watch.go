@@ -0,0 +1,375 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/miss-you/codetok/pricing"
+	"github.com/miss-you/codetok/provider"
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Show live token totals of active sessions",
+	Long: `Show live token totals of active sessions.
+
+watch follows the append-only session logs of Claude Code, Codex CLI, and Kimi CLI that changed within --active, plus any log that starts changing while it runs, and parses only newly appended lines with the same parsers as the reports. Followed logs are read every --interval; new logs are discovered every 30 seconds. The screen shows per-session and per-model totals, refreshed every --interval, with tokens/minute over the last --rate-window.
+
+With --json, watch prints one JSON object per line (NDJSON) whenever the totals change. Stop it with Ctrl-C.`,
+	RunE: runWatch,
+}
+
+const defaultWatchInterval = 2 * time.Second
+const defaultWatchActive = 30 * time.Minute
+const defaultWatchRateWindow = 5 * time.Minute
+
+// watchRescanInterval is how often the log directories are listed again to
+// find new sessions; followed logs are read on every --interval.
+const watchRescanInterval = 30 * time.Second
+
+func init() {
+	watchCmd.Flags().Bool("json", false, "Print an NDJSON snapshot whenever totals change")
+	watchCmd.Flags().Duration("interval", defaultWatchInterval, "How often to check the logs for new lines")
+	watchCmd.Flags().Duration("active", defaultWatchActive, "Follow logs modified within this window")
+	watchCmd.Flags().Duration("rate-window", defaultWatchRateWindow, "Window for the tokens/minute rate")
+	watchCmd.Flags().String("provider", "", "Filter by provider name (claude, codex, kimi)")
+	watchCmd.Flags().String("base-dir", "", "Override default data directory (applies to all providers)")
+	watchCmd.Flags().String("kimi-dir", "", "Override Kimi data directory")
+	watchCmd.Flags().String("claude-dir", "", "Override Claude Code data directory")
+	watchCmd.Flags().String("codex-dir", "", "Override Codex CLI data directory")
+	watchCmd.Flags().String("pricing-file", "", pricingFileFlagUsage)
+	rootCmd.AddCommand(watchCmd)
+}
+
+func runWatch(cmd *cobra.Command, args []string) error {
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	interval, _ := cmd.Flags().GetDuration("interval")
+	active, _ := cmd.Flags().GetDuration("active")
+	rateWindow, _ := cmd.Flags().GetDuration("rate-window")
+	providerFilter, _ := cmd.Flags().GetString("provider")
+	baseDir, _ := cmd.Flags().GetString("base-dir")
+	if interval <= 0 {
+		return fmt.Errorf("invalid --interval: must be > 0")
+	}
+	if active <= 0 {
+		return fmt.Errorf("invalid --active: must be > 0")
+	}
+	if rateWindow <= 0 {
+		return fmt.Errorf("invalid --rate-window: must be > 0")
+	}
+	prices, err := resolvePricingTable(cmd)
+	if err != nil {
+		return err
+	}
+
+	var sources []provider.JSONLSourceProvider
+	dirs := make(map[string]string)
+	for _, p := range provider.FilterProviders(provider.Registry(), providerFilter) {
+		sourceProvider, ok := p.(provider.JSONLSourceProvider)
+		if !ok {
+			continue
+		}
+		sources = append(sources, sourceProvider)
+		dirs[p.Name()] = baseDir
+		if providerDir, _ := cmd.Flags().GetString(providerDirFlag(p.Name())); providerDir != "" {
+			dirs[p.Name()] = providerDir
+		}
+	}
+	if len(sources) == 0 {
+		return fmt.Errorf("watch supports claude, codex, and kimi; got --provider %q", providerFilter)
+	}
+
+	watcher := newUsageWatcher(sources, dirs, active, cmd.ErrOrStderr())
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	out := cmd.OutOrStdout()
+	clearScreen := !jsonOutput && isTerminal(os.Stdout)
+	return watcher.run(ctx, interval, time.Now, func(now time.Time, changed bool) error {
+		if jsonOutput {
+			if !changed {
+				return nil
+			}
+			return json.NewEncoder(out).Encode(watcher.snapshot(now, rateWindow, prices))
+		}
+		if clearScreen {
+			fmt.Fprint(out, "\033[H\033[2J")
+		} else if !changed {
+			return nil
+		}
+		printWatchSnapshot(out, watcher.snapshot(now, rateWindow, prices), rateWindow)
+		return nil
+	})
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// usageWatcher follows the JSONL logs of recently active sessions.
+type usageWatcher struct {
+	listers []watchSourceLister
+	active  time.Duration
+	rescan  time.Duration
+	errOut  io.Writer
+
+	lastScan time.Time
+	tails    map[string]*provider.JSONLTail
+	// broken remembers logs that failed to parse, so they are reported once.
+	broken map[string]struct{}
+}
+
+type watchSourceLister struct {
+	name string
+	list func() ([]provider.JSONLSource, error)
+}
+
+func newUsageWatcher(providers []provider.JSONLSourceProvider, dirs map[string]string, active time.Duration, errOut io.Writer) *usageWatcher {
+	listers := make([]watchSourceLister, 0, len(providers))
+	for _, p := range providers {
+		listers = append(listers, watchSourceLister{name: p.Name(), list: p.JSONLSourceLister(dirs[p.Name()])})
+	}
+	return &usageWatcher{
+		listers: listers,
+		active:  active,
+		rescan:  watchRescanInterval,
+		errOut:  errOut,
+		tails:   make(map[string]*provider.JSONLTail),
+		broken:  make(map[string]struct{}),
+	}
+}
+
+// run polls until ctx is done, calling render after every poll with whether
+// any log grew. The first render happens right away.
+func (w *usageWatcher) run(ctx context.Context, interval time.Duration, now func() time.Time, render func(now time.Time, changed bool) error) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	first := true
+	for {
+		changed, err := w.poll(now())
+		if err != nil {
+			return err
+		}
+		if err := render(now(), changed || first); err != nil {
+			return err
+		}
+		first = false
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll reads new lines from every followed log and reports whether any log
+// grew. Every rescan interval it first starts following logs that were
+// modified within the active window.
+func (w *usageWatcher) poll(now time.Time) (bool, error) {
+	if w.lastScan.IsZero() || now.Sub(w.lastScan) >= w.rescan {
+		if err := w.scan(now); err != nil {
+			return false, err
+		}
+		w.lastScan = now
+	}
+
+	changed := false
+	for path, tail := range w.tails {
+		skipped := tail.SkippedLines()
+		read, err := tail.Poll()
+		if n := tail.SkippedLines() - skipped; n > 0 {
+			fmt.Fprintf(w.errOut, "Warning: skipped %s of 1 MiB or more in %s\n", pluralCount(n, "line"), path)
+		}
+		if err != nil {
+			delete(w.tails, path)
+			changed = true
+			if !os.IsNotExist(err) {
+				w.broken[path] = struct{}{}
+				fmt.Fprintf(w.errOut, "Warning: stopped following %s: %v\n", path, err)
+			}
+			continue
+		}
+		changed = changed || read
+	}
+	return changed, nil
+}
+
+// scan starts following logs that were modified within the active window.
+func (w *usageWatcher) scan(now time.Time) error {
+	for _, lister := range w.listers {
+		sources, err := lister.list()
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return fmt.Errorf("listing %s session logs: %w", lister.name, err)
+		}
+		for _, source := range sources {
+			if _, ok := w.tails[source.Path]; ok {
+				continue
+			}
+			if _, ok := w.broken[source.Path]; ok {
+				continue
+			}
+			info, err := os.Stat(source.Path)
+			if err != nil || now.Sub(info.ModTime()) > w.active {
+				continue
+			}
+			w.tails[source.Path] = provider.NewJSONLTail(source)
+		}
+	}
+	return nil
+}
+
+func (w *usageWatcher) events() []provider.UsageEvent {
+	var events []provider.UsageEvent
+	for _, tail := range w.tails {
+		events = append(events, tail.Events()...)
+	}
+	return events
+}
+
+type watchSessionJSON struct {
+	Provider        string                `json:"provider"`
+	SessionID       string                `json:"session_id"`
+	Title           string                `json:"title"`
+	Model           string                `json:"model"`
+	Turns           int                   `json:"turns"`
+	TokenUsage      provider.TokenUsage   `json:"token_usage"`
+	Cost            provider.CostEstimate `json:"cost"`
+	TokensPerMinute float64               `json:"tokens_per_minute"`
+	LastActivity    time.Time             `json:"last_activity"`
+}
+
+type watchModelJSON struct {
+	providerModelTotal
+	TokensPerMinute float64 `json:"tokens_per_minute"`
+}
+
+type watchTotalJSON struct {
+	TokenUsage      provider.TokenUsage   `json:"token_usage"`
+	Cost            provider.CostEstimate `json:"cost"`
+	TokensPerMinute float64               `json:"tokens_per_minute"`
+}
+
+type watchSnapshotJSON struct {
+	Time     time.Time          `json:"time"`
+	Sessions []watchSessionJSON `json:"sessions"`
+	Models   []watchModelJSON   `json:"models"`
+	Total    watchTotalJSON     `json:"total"`
+}
+
+// snapshot totals the followed sessions. Rates count tokens of events in the
+// rateWindow before now, per minute.
+func (w *usageWatcher) snapshot(now time.Time, rateWindow time.Duration, prices *pricing.Table) watchSnapshotJSON {
+	events := w.events()
+	var recent []provider.UsageEvent
+	for _, event := range events {
+		if event.Timestamp.After(now.Add(-rateWindow)) && !event.Timestamp.After(now) {
+			recent = append(recent, event)
+		}
+	}
+	perMinute := func(tokens int) float64 {
+		return float64(tokens) / rateWindow.Minutes()
+	}
+
+	recentSessions := make(map[string]int)
+	for _, session := range aggregateSessionEvents(recent) {
+		recentSessions[session.ProviderName+"\x00"+session.SessionID] += session.TokenUsage.Total()
+	}
+	snapshot := watchSnapshotJSON{Time: now, Sessions: []watchSessionJSON{}, Models: []watchModelJSON{}}
+	for _, session := range aggregateSessionEventsWithPricing(events, prices) {
+		snapshot.Sessions = append(snapshot.Sessions, watchSessionJSON{
+			Provider:        session.ProviderName,
+			SessionID:       session.SessionID,
+			Title:           session.Title,
+			Model:           session.ModelName,
+			Turns:           session.Turns,
+			TokenUsage:      session.TokenUsage,
+			Cost:            session.Cost,
+			TokensPerMinute: perMinute(recentSessions[session.ProviderName+"\x00"+session.SessionID]),
+			LastActivity:    session.EndTime,
+		})
+		mergeTokenUsage(&snapshot.Total.TokenUsage, session.TokenUsage)
+		snapshot.Total.Cost.Add(session.Cost)
+	}
+	sort.SliceStable(snapshot.Sessions, func(i, j int) bool {
+		return snapshot.Sessions[i].LastActivity.After(snapshot.Sessions[j].LastActivity)
+	})
+
+	recentModels := make(map[string]int)
+	recentTotal := 0
+	for _, model := range aggregateProviderModelTotals(recent, nil) {
+		recentModels[model.Provider+"\x00"+model.Model] = model.TokenUsage.Total()
+		recentTotal += model.TokenUsage.Total()
+	}
+	for _, model := range aggregateProviderModelTotals(events, prices) {
+		snapshot.Models = append(snapshot.Models, watchModelJSON{
+			providerModelTotal: model,
+			TokensPerMinute:    perMinute(recentModels[model.Provider+"\x00"+model.Model]),
+		})
+	}
+	snapshot.Total.TokensPerMinute = perMinute(recentTotal)
+	return snapshot
+}
+
+func printWatchSnapshot(out io.Writer, snapshot watchSnapshotJSON, rateWindow time.Duration) {
+	fmt.Fprintf(out, "codetok watch  %s  (%s followed, tokens/min over the last %s)\n\n",
+		snapshot.Time.Format("15:04:05"), pluralCount(len(snapshot.Sessions), "session"), rateWindow)
+	if len(snapshot.Sessions) == 0 {
+		fmt.Fprintln(out, "Waiting for session activity...")
+		return
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Provider\tSession\tTitle\tModel\tTurns\tTotal\tTokens/min\tCost\tLast Active")
+	for _, s := range snapshot.Sessions {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%d\t%.0f\t%s\t%s\n",
+			s.Provider,
+			truncate(s.SessionID, 12),
+			truncate(s.Title, 30),
+			s.Model,
+			s.Turns,
+			s.TokenUsage.Total(),
+			s.TokensPerMinute,
+			formatCost(s.Cost),
+			s.LastActivity.Local().Format("15:04:05"),
+		)
+	}
+	_ = w.Flush()
+	fmt.Fprintln(out)
+
+	w = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Provider\tModel\tInput\tOutput\tTotal\tTokens/min\tCost")
+	for _, m := range snapshot.Models {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%.0f\t%s\n",
+			m.Provider,
+			m.Model,
+			m.TokenUsage.TotalInput(),
+			m.TokenUsage.Output,
+			m.TokenUsage.Total(),
+			m.TokensPerMinute,
+			formatCost(m.Cost),
+		)
+	}
+	fmt.Fprintf(w, "TOTAL\t\t%d\t%d\t%d\t%.0f\t%s\n",
+		snapshot.Total.TokenUsage.TotalInput(),
+		snapshot.Total.TokenUsage.Output,
+		snapshot.Total.TokenUsage.Total(),
+		snapshot.Total.TokensPerMinute,
+		formatCost(snapshot.Total.Cost),
+	)
+	_ = w.Flush()
+	printUnpricedModelsNote(out, snapshot.Total.Cost)
+}
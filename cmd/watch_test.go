@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/miss-you/codetok/provider"
+)
+
+func watchTestClaudeLine(id, sessionID string, ts time.Time, input, output int) string {
+	return fmt.Sprintf(`{"type":"assistant","requestId":"req-%s","sessionId":"%s","timestamp":"%s","message":{"id":"msg-%s","model":"claude-sonnet-4-5","role":"assistant","content":[{"type":"text","text":"ok"}],"usage":{"input_tokens":%d,"cache_creation_input_tokens":0,"cache_read_input_tokens":0,"output_tokens":%d}}}`+"\n",
+		id, sessionID, ts.Format(time.RFC3339), id, input, output)
+}
+
+func writeWatchTestLog(t *testing.T, path, content string, modTime time.Time) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newWatchTestWatcher(t *testing.T, dir string, errOut *bytes.Buffer) *usageWatcher {
+	t.Helper()
+	var sources []provider.JSONLSourceProvider
+	for _, p := range provider.FilterProviders(provider.Registry(), "claude") {
+		sources = append(sources, p.(provider.JSONLSourceProvider))
+	}
+	if len(sources) != 1 {
+		t.Fatalf("claude JSONL sources = %d, want 1", len(sources))
+	}
+	return newUsageWatcher(sources, map[string]string{"claude": dir}, 30*time.Minute, errOut)
+}
+
+func TestUsageWatcher_FollowsActiveLogsIncrementally(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now().Truncate(time.Second)
+	activeLog := filepath.Join(dir, "proj", "s1.jsonl")
+	staleLog := filepath.Join(dir, "proj", "old.jsonl")
+	writeWatchTestLog(t, activeLog, watchTestClaudeLine("a", "s1", now.Add(-20*time.Minute), 100, 10), now.Add(-time.Minute))
+	writeWatchTestLog(t, staleLog, watchTestClaudeLine("z", "old", now.Add(-2*time.Hour), 999, 9), now.Add(-2*time.Hour))
+
+	var errOut bytes.Buffer
+	watcher := newWatchTestWatcher(t, dir, &errOut)
+	if changed, err := watcher.poll(now); err != nil || !changed {
+		t.Fatalf("first poll = %v, %v; want the active log read", changed, err)
+	}
+	if changed, err := watcher.poll(now); err != nil || changed {
+		t.Fatalf("idle poll = %v, %v; want nothing new", changed, err)
+	}
+
+	writeWatchTestLog(t, activeLog, watchTestClaudeLine("b", "s1", now.Add(-2*time.Minute), 400, 100), now)
+	newLog := filepath.Join(dir, "proj", "s2.jsonl")
+	writeWatchTestLog(t, newLog, watchTestClaudeLine("c", "s2", now.Add(-time.Minute), 40, 10), now)
+	if changed, err := watcher.poll(now); err != nil || !changed {
+		t.Fatalf("poll after append = %v, %v; want new lines read", changed, err)
+	}
+	if got := len(watcher.snapshot(now, 5*time.Minute, nil).Sessions); got != 1 {
+		t.Fatalf("sessions before rescan = %d, want new logs found only on the next rescan", got)
+	}
+	later := now.Add(watchRescanInterval)
+	if changed, err := watcher.poll(later); err != nil || !changed {
+		t.Fatalf("poll after rescan = %v, %v; want the new log read", changed, err)
+	}
+
+	snapshot := watcher.snapshot(now, 5*time.Minute, nil)
+	if len(snapshot.Sessions) != 2 {
+		t.Fatalf("sessions = %+v, want s1 and the newly active s2 but not the stale log", snapshot.Sessions)
+	}
+	s2, s1 := snapshot.Sessions[0], snapshot.Sessions[1]
+	if s2.SessionID != "s2" || s2.TokenUsage.Total() != 50 || s2.TokensPerMinute != 10 {
+		t.Fatalf("s2 = %+v, want 50 tokens at 10/min", s2)
+	}
+	if s1.SessionID != "s1" || s1.Turns != 2 || s1.TokenUsage.Total() != 610 || s1.TokensPerMinute != 100 {
+		t.Fatalf("s1 = %+v, want both turns and only the recent one in the rate", s1)
+	}
+	if len(snapshot.Models) != 1 || snapshot.Models[0].Model != "claude-sonnet-4-5" || snapshot.Models[0].TokenUsage.Total() != 660 {
+		t.Fatalf("models = %+v", snapshot.Models)
+	}
+	if snapshot.Total.TokenUsage.Total() != 660 || snapshot.Total.TokensPerMinute != 110 {
+		t.Fatalf("total = %+v, want 660 tokens at 110/min", snapshot.Total)
+	}
+
+	if err := os.Remove(newLog); err != nil {
+		t.Fatal(err)
+	}
+	if changed, err := watcher.poll(later); err != nil || !changed {
+		t.Fatalf("poll after removal = %v, %v; want the log dropped", changed, err)
+	}
+	if got := len(watcher.snapshot(now, 5*time.Minute, nil).Sessions); got != 1 {
+		t.Fatalf("sessions after removal = %d, want 1", got)
+	}
+	if errOut.Len() != 0 {
+		t.Fatalf("warnings = %q, want none for a removed log", errOut.String())
+	}
+
+	oversized := `{"type":"user","message":{"content":"` + strings.Repeat("x", 1024*1024) + `"}}` + "\n"
+	writeWatchTestLog(t, activeLog, oversized+watchTestClaudeLine("d", "s1", now, 1, 1), later)
+	if changed, err := watcher.poll(later); err != nil || !changed {
+		t.Fatalf("poll after oversized line = %v, %v; want the following line read", changed, err)
+	}
+	if !strings.Contains(errOut.String(), "skipped 1 line of 1 MiB or more in "+activeLog) {
+		t.Fatalf("warnings = %q, want the oversized line reported", errOut.String())
+	}
+	if got := watcher.snapshot(later, 5*time.Minute, nil).Sessions[0]; got.SessionID != "s1" || got.Turns != 3 {
+		t.Fatalf("s1 = %+v, want the log still followed past the oversized line", got)
+	}
+}
+
+func TestUsageWatcher_RendersJSONAndTable(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 4, 16, 12, 0, 0, 0, time.UTC)
+	writeWatchTestLog(t, filepath.Join(dir, "proj", "s1.jsonl"), watchTestClaudeLine("a", "s1", now.Add(-time.Minute), 100, 50), now)
+
+	watcher := newWatchTestWatcher(t, dir, &bytes.Buffer{})
+	if _, err := watcher.poll(now); err != nil {
+		t.Fatalf("poll: %v", err)
+	}
+	snapshot := watcher.snapshot(now, 5*time.Minute, nil)
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	sessions := decoded["sessions"].([]any)
+	models := decoded["models"].([]any)
+	session := sessions[0].(map[string]any)
+	model := models[0].(map[string]any)
+	if session["session_id"] != "s1" || session["tokens_per_minute"] != float64(30) || session["last_activity"] != "2026-04-16T11:59:00Z" {
+		t.Fatalf("session JSON = %v", session)
+	}
+	if model["provider"] != "claude" || model["model"] != "claude-sonnet-4-5" || model["tokens_per_minute"] != float64(30) {
+		t.Fatalf("model JSON = %v", model)
+	}
+
+	var out bytes.Buffer
+	printWatchSnapshot(&out, snapshot, 5*time.Minute)
+	assertContainsAll(t, out.String(),
+		"1 session followed",
+		"Provider  Session  Title",
+		"Tokens/min",
+		"claude-sonnet-4-5",
+		"TOTAL",
+	)
+	if !strings.Contains(out.String(), "150") {
+		t.Fatalf("table = %q, want the session total", out.String())
+	}
+}
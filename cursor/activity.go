@@ -6,23 +6,50 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	_ "modernc.org/sqlite"
 )
 
 const activityDriverName = "sqlite"
 
-const activityTotalsQuery = `
+const activityCommitsQuery = `
 SELECT
-	COUNT(*),
-	COALESCE(SUM(composerLinesAdded), 0),
-	COALESCE(SUM(composerLinesDeleted), 0),
-	COALESCE(SUM(tabLinesAdded), 0),
-	COALESCE(SUM(tabLinesDeleted), 0)
+	commitHash,
+	COALESCE(branchName, ''),
+	COALESCE(scoredAt, 0),
+	COALESCE(commitDate, ''),
+	COALESCE(commitMessage, ''),
+	COALESCE(composerLinesAdded, 0),
+	COALESCE(composerLinesDeleted, 0),
+	COALESCE(tabLinesAdded, 0),
+	COALESCE(tabLinesDeleted, 0)
 FROM scored_commits
 `
 
+// activityScoredSinceClause skips commits scored before the range. Cursor
+// scores a commit after it is made, so such a commit cannot fall in the range
+// by its commit date either. There is no matching upper bound: a commit made
+// before Until may be scored after it.
+const activityScoredSinceClause = `WHERE COALESCE(scoredAt, 0) = 0 OR scoredAt >= ?`
+
+// Activity breakdowns. The tracking database does not record which
+// repository a scored commit belongs to, so branch is the closest grouping.
+const (
+	ActivityByDay    = "day"
+	ActivityByBranch = "branch"
+	ActivityByCommit = "commit"
+)
+
+// commitDateLayouts are the formats Cursor has used for scored_commits.commitDate.
+var commitDateLayouts = []string{
+	time.RFC3339Nano,
+	"Mon Jan 2 15:04:05 2006 -0700",
+	"2006-01-02 15:04:05 -0700",
+}
+
 type openActivityDBFunc func(driverName, dataSourceName string) (*sql.DB, error)
 
 // ActivityMetric holds line-based Cursor attribution data for one activity source.
@@ -33,13 +60,45 @@ type ActivityMetric struct {
 
 // ActivityResult keeps Cursor line attribution separate from token accounting.
 type ActivityResult struct {
-	DBPath        string         `json:"db_path"`
-	HasData       bool           `json:"has_data"`
+	DBPath        string          `json:"db_path"`
+	HasData       bool            `json:"has_data"`
+	ScoredCommits int             `json:"scored_commits"`
+	Composer      ActivityMetric  `json:"composer"`
+	Tab           ActivityMetric  `json:"tab"`
+	By            string          `json:"by,omitempty"`
+	Groups        []ActivityGroup `json:"groups,omitempty"`
+}
+
+// ActivityGroup is the attribution of one day, branch, or commit.
+type ActivityGroup struct {
+	Key           string         `json:"key"`
+	Branch        string         `json:"branch,omitempty"`
+	Message       string         `json:"message,omitempty"`
 	ScoredCommits int            `json:"scored_commits"`
 	Composer      ActivityMetric `json:"composer"`
 	Tab           ActivityMetric `json:"tab"`
 }
 
+// ActivityQuery filters and groups scored commits. Commits are dated by their
+// commit date, or by when Cursor scored them when that is missing; Since and
+// Until are inclusive and zero bounds are open. Location sets the day
+// boundaries for ActivityByDay (default: local time).
+type ActivityQuery struct {
+	Since    time.Time
+	Until    time.Time
+	Location *time.Location
+	By       string
+}
+
+type scoredCommit struct {
+	hash     string
+	branch   string
+	date     time.Time
+	message  string
+	composer ActivityMetric
+	tab      ActivityMetric
+}
+
 // ActivityReader reads Cursor activity attribution from the local tracking database.
 type ActivityReader struct {
 	openDB openActivityDBFunc
@@ -60,7 +119,13 @@ func DefaultActivityDBPath() (string, error) {
 }
 
 // Read aggregates composer and tab activity without reusing token fields.
-func (r *ActivityReader) Read(dbPath string) (ActivityResult, error) {
+func (r *ActivityReader) Read(dbPath string, query ActivityQuery) (ActivityResult, error) {
+	switch query.By {
+	case "", ActivityByDay, ActivityByBranch, ActivityByCommit:
+	default:
+		return ActivityResult{}, fmt.Errorf("unsupported cursor activity breakdown %q", query.By)
+	}
+
 	resolvedPath, err := resolveActivityDBPath(dbPath)
 	if err != nil {
 		return ActivityResult{}, err
@@ -85,13 +150,7 @@ func (r *ActivityReader) Read(dbPath string) (ActivityResult, error) {
 	}
 	defer db.Close()
 
-	err = db.QueryRow(activityTotalsQuery).Scan(
-		&result.ScoredCommits,
-		&result.Composer.LinesAdded,
-		&result.Composer.LinesDeleted,
-		&result.Tab.LinesAdded,
-		&result.Tab.LinesDeleted,
-	)
+	commits, err := readScoredCommits(db, query.Since)
 	if err != nil {
 		if strings.Contains(err.Error(), "no such table") {
 			return result, nil
@@ -99,10 +158,168 @@ func (r *ActivityReader) Read(dbPath string) (ActivityResult, error) {
 		return result, fmt.Errorf("query cursor activity database %q: %w", resolvedPath, err)
 	}
 
+	loc := query.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	groups := make(map[string]*ActivityGroup)
+	for _, commit := range commits {
+		if !query.contains(commit.date) {
+			continue
+		}
+		result.ScoredCommits++
+		result.Composer.add(commit.composer)
+		result.Tab.add(commit.tab)
+
+		if query.By == "" {
+			continue
+		}
+		key := commit.groupKey(query.By, loc)
+		group, ok := groups[key]
+		if !ok {
+			group = &ActivityGroup{Key: key}
+			if query.By == ActivityByCommit {
+				group.Branch = commit.branch
+				group.Message = firstLine(commit.message)
+			}
+			groups[key] = group
+		}
+		group.ScoredCommits++
+		group.Composer.add(commit.composer)
+		group.Tab.add(commit.tab)
+	}
+
 	result.HasData = result.ScoredCommits > 0
+	if query.By != "" {
+		result.By = query.By
+		result.Groups = sortedActivityGroups(groups, query.By)
+	}
 	return result, nil
 }
 
+// readScoredCommits loads the scored commits that may fall on or after since;
+// the caller still checks each commit's date against the query.
+func readScoredCommits(db *sql.DB, since time.Time) ([]scoredCommit, error) {
+	var (
+		rows *sql.Rows
+		err  error
+	)
+	if since.IsZero() {
+		rows, err = db.Query(activityCommitsQuery)
+	} else {
+		rows, err = db.Query(activityCommitsQuery+activityScoredSinceClause, since.UnixMilli())
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var commits []scoredCommit
+	for rows.Next() {
+		var (
+			commit     scoredCommit
+			scoredAt   int64
+			commitDate string
+		)
+		if err := rows.Scan(
+			&commit.hash,
+			&commit.branch,
+			&scoredAt,
+			&commitDate,
+			&commit.message,
+			&commit.composer.LinesAdded,
+			&commit.composer.LinesDeleted,
+			&commit.tab.LinesAdded,
+			&commit.tab.LinesDeleted,
+		); err != nil {
+			return nil, err
+		}
+		commit.date = scoredCommitTime(commitDate, scoredAt)
+		commits = append(commits, commit)
+	}
+	return commits, rows.Err()
+}
+
+// scoredCommitTime prefers the commit's own date and falls back to scoredAt,
+// which Cursor stores in Unix milliseconds.
+func scoredCommitTime(commitDate string, scoredAt int64) time.Time {
+	commitDate = strings.TrimSpace(commitDate)
+	for _, layout := range commitDateLayouts {
+		if ts, err := time.Parse(layout, commitDate); err == nil {
+			return ts
+		}
+	}
+	if scoredAt > 0 {
+		return time.UnixMilli(scoredAt)
+	}
+	return time.Time{}
+}
+
+func (q ActivityQuery) contains(ts time.Time) bool {
+	if q.Since.IsZero() && q.Until.IsZero() {
+		return true
+	}
+	if ts.IsZero() {
+		return false
+	}
+	if !q.Since.IsZero() && ts.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && ts.After(q.Until) {
+		return false
+	}
+	return true
+}
+
+func (c scoredCommit) groupKey(by string, loc *time.Location) string {
+	switch by {
+	case ActivityByDay:
+		if c.date.IsZero() {
+			return "unknown"
+		}
+		return c.date.In(loc).Format("2006-01-02")
+	case ActivityByBranch:
+		if c.branch == "" {
+			return "unknown"
+		}
+		return c.branch
+	default:
+		return c.hash
+	}
+}
+
+// sortedActivityGroups orders days chronologically and branches or commits by
+// AI-authored lines added, largest first.
+func sortedActivityGroups(groups map[string]*ActivityGroup, by string) []ActivityGroup {
+	sorted := make([]ActivityGroup, 0, len(groups))
+	for _, group := range groups {
+		sorted = append(sorted, *group)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if by != ActivityByDay {
+			ai := sorted[i].Composer.LinesAdded + sorted[i].Tab.LinesAdded
+			aj := sorted[j].Composer.LinesAdded + sorted[j].Tab.LinesAdded
+			if ai != aj {
+				return ai > aj
+			}
+		}
+		return sorted[i].Key < sorted[j].Key
+	})
+	return sorted
+}
+
+func (m *ActivityMetric) add(other ActivityMetric) {
+	m.LinesAdded += other.LinesAdded
+	m.LinesDeleted += other.LinesDeleted
+}
+
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[:i]
+	}
+	return strings.TrimSpace(s)
+}
+
 func resolveActivityDBPath(dbPath string) (string, error) {
 	if strings.TrimSpace(dbPath) != "" {
 		return dbPath, nil
@@ -111,6 +328,6 @@ func resolveActivityDBPath(dbPath string) (string, error) {
 }
 
 // Activity returns local Cursor line-attribution data without entering token paths.
-func (s *Service) Activity(_ context.Context, dbPath string) (ActivityResult, error) {
-	return NewActivityReader().Read(dbPath)
+func (s *Service) Activity(_ context.Context, dbPath string, query ActivityQuery) (ActivityResult, error) {
+	return NewActivityReader().Read(dbPath, query)
 }
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/miss-you/codetok/internal/testutil"
 )
@@ -19,7 +20,7 @@ func TestReadActivity_AggregatesComposerAndTabMetrics(t *testing.T) {
 	})
 
 	reader := NewActivityReader()
-	result, err := reader.Read(dbPath)
+	result, err := reader.Read(dbPath, ActivityQuery{})
 	if err != nil {
 		t.Fatalf("Read returned error: %v", err)
 	}
@@ -51,7 +52,7 @@ func TestReadActivity_MissingDatabaseReturnsNoData(t *testing.T) {
 	reader := NewActivityReader()
 	dbPath := filepath.Join(t.TempDir(), "missing.db")
 
-	result, err := reader.Read(dbPath)
+	result, err := reader.Read(dbPath, ActivityQuery{})
 	if err != nil {
 		t.Fatalf("Read returned error: %v", err)
 	}
@@ -79,7 +80,7 @@ func TestReadActivity_ComposerOnlyKeepsTabZero(t *testing.T) {
 	})
 
 	reader := NewActivityReader()
-	result, err := reader.Read(dbPath)
+	result, err := reader.Read(dbPath, ActivityQuery{})
 	if err != nil {
 		t.Fatalf("Read returned error: %v", err)
 	}
@@ -101,7 +102,7 @@ func TestReadActivity_TabOnlyKeepsComposerZero(t *testing.T) {
 	})
 
 	reader := NewActivityReader()
-	result, err := reader.Read(dbPath)
+	result, err := reader.Read(dbPath, ActivityQuery{})
 	if err != nil {
 		t.Fatalf("Read returned error: %v", err)
 	}
@@ -124,7 +125,7 @@ func TestReadActivity_UnexpectedStatErrorReturnsError(t *testing.T) {
 	}
 
 	reader := NewActivityReader()
-	_, err := reader.Read(filepath.Join(parentFile, "ai-code-tracking.db"))
+	_, err := reader.Read(filepath.Join(parentFile, "ai-code-tracking.db"), ActivityQuery{})
 	if err == nil {
 		t.Fatal("expected unexpected stat failure to return error")
 	}
@@ -148,7 +149,7 @@ func TestReadActivity_OpenDatabaseErrorReturnsError(t *testing.T) {
 		},
 	}
 
-	_, err := reader.Read(dbPath)
+	_, err := reader.Read(dbPath, ActivityQuery{})
 	if !errors.Is(err, wantErr) {
 		t.Fatalf("Read error = %v, want %v", err, wantErr)
 	}
@@ -172,7 +173,7 @@ func TestReadActivity_UnexpectedQueryErrorReturnsError(t *testing.T) {
 		},
 	}
 
-	_, err := reader.Read(dbPath)
+	_, err := reader.Read(dbPath, ActivityQuery{})
 	if err == nil {
 		t.Fatal("expected unexpected query failure to return error")
 	}
@@ -184,3 +185,77 @@ func TestReadActivity_UnexpectedQueryErrorReturnsError(t *testing.T) {
 func writeActivityFixtureDB(t *testing.T, rows []scoredCommitFixture) string {
 	return testutil.WriteCursorActivityDB(t, rows)
 }
+
+func TestReadActivity_FiltersByDateAndBreaksDownByDay(t *testing.T) {
+	dbPath := writeActivityFixtureDB(t, []scoredCommitFixture{
+		// 23:30 UTC on Apr 14 is already Apr 15 in Shanghai.
+		{CommitHash: "a1", CommitDate: "2026-04-14T23:30:00Z", ScoredAt: time.Date(2026, 4, 14, 23, 31, 0, 0, time.UTC).UnixMilli(), ComposerAdded: 10, TabAdded: 1},
+		{CommitHash: "b2", CommitDate: "Wed Apr 15 09:00:00 2026 +0800", ScoredAt: time.Date(2026, 4, 17, 3, 0, 0, 0, time.UTC).UnixMilli(), ComposerAdded: 5, TabAdded: 2},
+		// No commit date: dated by scoredAt (Unix milliseconds).
+		{CommitHash: "c3", ScoredAt: time.Date(2026, 4, 16, 2, 0, 0, 0, time.UTC).UnixMilli(), ComposerAdded: 7},
+		{CommitHash: "d4", CommitDate: "2026-04-20T08:00:00Z", ScoredAt: time.Date(2026, 4, 20, 8, 1, 0, 0, time.UTC).UnixMilli(), ComposerAdded: 100},
+		// Made before the range but scored inside it: dated by its commit date.
+		{CommitHash: "e5", CommitDate: "2026-04-10T08:00:00Z", ScoredAt: time.Date(2026, 4, 15, 8, 0, 0, 0, time.UTC).UnixMilli(), ComposerAdded: 1000},
+		// Scored before the range: skipped by the query itself, before its
+		// (here inconsistent) commit date is looked at.
+		{CommitHash: "f6", CommitDate: "2026-04-15T08:00:00Z", ScoredAt: time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC).UnixMilli(), ComposerAdded: 10000},
+	})
+	loc, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+
+	result, err := NewActivityReader().Read(dbPath, ActivityQuery{
+		Since:    time.Date(2026, 4, 15, 0, 0, 0, 0, loc),
+		Until:    time.Date(2026, 4, 17, 0, 0, 0, 0, loc).Add(-time.Nanosecond),
+		Location: loc,
+		By:       ActivityByDay,
+	})
+	if err != nil {
+		t.Fatalf("Read returned error: %v", err)
+	}
+
+	if result.ScoredCommits != 3 || result.Composer.LinesAdded != 22 || result.Tab.LinesAdded != 3 {
+		t.Fatalf("totals = %+v, want the three commits inside the range", result)
+	}
+	if result.By != ActivityByDay || len(result.Groups) != 2 {
+		t.Fatalf("groups = %+v, want two days", result.Groups)
+	}
+	if got := result.Groups[0]; got.Key != "2026-04-15" || got.ScoredCommits != 2 || got.Composer.LinesAdded != 15 {
+		t.Fatalf("first day = %+v, want 2026-04-15 with two commits", got)
+	}
+	if got := result.Groups[1]; got.Key != "2026-04-16" || got.Composer.LinesAdded != 7 {
+		t.Fatalf("second day = %+v, want 2026-04-16 from scoredAt", got)
+	}
+}
+
+func TestReadActivity_BreaksDownByBranchAndCommit(t *testing.T) {
+	dbPath := writeActivityFixtureDB(t, []scoredCommitFixture{
+		{CommitHash: "a1", Branch: "main", CommitMessage: "Fix parser\n\nDetails", ComposerAdded: 3},
+		{CommitHash: "b2", Branch: "feature", ComposerAdded: 8, TabAdded: 4},
+		{CommitHash: "c3", Branch: "main", ComposerAdded: 2},
+	})
+
+	byBranch, err := NewActivityReader().Read(dbPath, ActivityQuery{By: ActivityByBranch})
+	if err != nil {
+		t.Fatalf("Read returned error: %v", err)
+	}
+	if len(byBranch.Groups) != 2 || byBranch.Groups[0].Key != "feature" || byBranch.Groups[1].Key != "main" || byBranch.Groups[1].ScoredCommits != 2 {
+		t.Fatalf("branch groups = %+v, want feature then main", byBranch.Groups)
+	}
+
+	byCommit, err := NewActivityReader().Read(dbPath, ActivityQuery{By: ActivityByCommit})
+	if err != nil {
+		t.Fatalf("Read returned error: %v", err)
+	}
+	if len(byCommit.Groups) != 3 || byCommit.Groups[0].Key != "b2" {
+		t.Fatalf("commit groups = %+v, want largest commit first", byCommit.Groups)
+	}
+	if got := byCommit.Groups[1]; got.Key != "a1" || got.Branch != "main" || got.Message != "Fix parser" {
+		t.Fatalf("commit a1 = %+v, want branch and first message line", got)
+	}
+
+	if _, err := NewActivityReader().Read(dbPath, ActivityQuery{By: "repo"}); err == nil {
+		t.Fatal("expected an unsupported breakdown to fail")
+	}
+}
@@ -4,12 +4,14 @@ import (
 	"context"
 	"errors"
 	"strings"
+	"time"
 )
 
 // APIClient abstracts the explicit remote calls used by Cursor auth and sync.
 type APIClient interface {
 	ValidateSession(ctx context.Context, token string) (ValidationResult, error)
-	FetchUsageCSV(ctx context.Context, token string) ([]byte, error)
+	FetchUsageCSV(ctx context.Context, token string, r UsageRange) ([]byte, error)
+	FetchUsageSummary(ctx context.Context, token string) (UsageSummary, error)
 }
 
 // StatusResult reports whether local credentials exist and whether they validate remotely.
@@ -24,6 +26,7 @@ type StatusResult struct {
 type Service struct {
 	Store  Store
 	Client APIClient
+	now    func() time.Time
 }
 
 // NewService returns a Cursor service using the provided store and client.
@@ -37,8 +40,13 @@ func NewService(store Store, client APIClient) *Service {
 	}
 }
 
-// Login validates the supplied token before saving it as the active credential.
-func (s *Service) Login(ctx context.Context, token string) (ValidationResult, error) {
+// Login validates the supplied token before saving it as the credential of
+// profile. An empty profile selects DefaultProfile, as for every Service method.
+func (s *Service) Login(ctx context.Context, profile, token string) (ValidationResult, error) {
+	store, err := s.Store.WithProfile(profile)
+	if err != nil {
+		return ValidationResult{}, err
+	}
 	token = strings.TrimSpace(token)
 	if token == "" {
 		return ValidationResult{}, errors.New("provide a Cursor session token")
@@ -55,15 +63,19 @@ func (s *Service) Login(ctx context.Context, token string) (ValidationResult, er
 		return result, errors.New(result.Message)
 	}
 
-	if err := s.Store.SaveCredentials(Credentials{SessionToken: token}); err != nil {
+	if err := store.SaveCredentials(Credentials{SessionToken: token}); err != nil {
 		return ValidationResult{}, err
 	}
 	return result, nil
 }
 
-// Status reports whether codetok has a local credential and whether it validates remotely.
-func (s *Service) Status(ctx context.Context) (StatusResult, error) {
-	creds, err := s.Store.LoadCredentials()
+// Status reports whether profile has a local credential and whether it validates remotely.
+func (s *Service) Status(ctx context.Context, profile string) (StatusResult, error) {
+	store, err := s.Store.WithProfile(profile)
+	if err != nil {
+		return StatusResult{}, err
+	}
+	creds, err := store.LoadCredentials()
 	if err != nil {
 		if errors.Is(err, ErrNoCredentials) {
 			return StatusResult{}, nil
@@ -84,7 +96,11 @@ func (s *Service) Status(ctx context.Context) (StatusResult, error) {
 	}, nil
 }
 
-// Logout removes the active local credential.
-func (s *Service) Logout() error {
-	return s.Store.DeleteCredentials()
+// Logout removes the local credential of profile.
+func (s *Service) Logout(profile string) error {
+	store, err := s.Store.WithProfile(profile)
+	if err != nil {
+		return err
+	}
+	return store.DeleteCredentials()
 }
@@ -11,6 +11,9 @@ type stubAPIClient struct {
 	validateErr    error
 	fetchCSV       []byte
 	fetchErr       error
+	fetchRange     UsageRange
+	summary        UsageSummary
+	summaryErr     error
 }
 
 func (s *stubAPIClient) ValidateSession(_ context.Context, token string) (ValidationResult, error) {
@@ -20,20 +23,28 @@ func (s *stubAPIClient) ValidateSession(_ context.Context, token string) (Valida
 	return s.validateResult, s.validateErr
 }
 
-func (s *stubAPIClient) FetchUsageCSV(context.Context, string) ([]byte, error) {
+func (s *stubAPIClient) FetchUsageCSV(_ context.Context, _ string, r UsageRange) ([]byte, error) {
+	s.fetchRange = r
 	if s.fetchErr != nil {
 		return nil, s.fetchErr
 	}
 	return append([]byte(nil), s.fetchCSV...), nil
 }
 
+func (s *stubAPIClient) FetchUsageSummary(_ context.Context, token string) (UsageSummary, error) {
+	if token == "" {
+		return UsageSummary{}, errors.New("missing token")
+	}
+	return s.summary, s.summaryErr
+}
+
 func TestServiceLoginSavesValidatedCredentials(t *testing.T) {
 	store := NewStore(t.TempDir())
 	svc := NewService(store, &stubAPIClient{
 		validateResult: ValidationResult{Valid: true, MembershipType: "pro"},
 	})
 
-	result, err := svc.Login(context.Background(), "token-123")
+	result, err := svc.Login(context.Background(), "", "token-123")
 	if err != nil {
 		t.Fatalf("Login returned error: %v", err)
 	}
@@ -56,7 +67,7 @@ func TestServiceLoginRejectsInvalidCredentials(t *testing.T) {
 		validateResult: ValidationResult{Valid: false, Message: "Session token expired or invalid"},
 	})
 
-	_, err := svc.Login(context.Background(), "bad-token")
+	_, err := svc.Login(context.Background(), "", "bad-token")
 	if err == nil {
 		t.Fatal("expected login to reject invalid credentials")
 	}
@@ -69,7 +80,7 @@ func TestServiceLoginRejectsInvalidCredentials(t *testing.T) {
 func TestServiceStatusReturnsLoggedOutWithoutCredentials(t *testing.T) {
 	svc := NewService(NewStore(t.TempDir()), &stubAPIClient{})
 
-	status, err := svc.Status(context.Background())
+	status, err := svc.Status(context.Background(), "")
 	if err != nil {
 		t.Fatalf("Status returned error: %v", err)
 	}
@@ -91,7 +102,7 @@ func TestServiceStatusSeparatesSavedCredentialsFromRemoteValidity(t *testing.T)
 		validateResult: ValidationResult{Valid: false, Message: "Session token expired or invalid"},
 	})
 
-	status, err := svc.Status(context.Background())
+	status, err := svc.Status(context.Background(), "")
 	if err != nil {
 		t.Fatalf("Status returned error: %v", err)
 	}
@@ -110,7 +121,7 @@ func TestServiceLogoutRemovesSavedCredentials(t *testing.T) {
 	}
 
 	svc := NewService(store, &stubAPIClient{})
-	if err := svc.Logout(); err != nil {
+	if err := svc.Logout(""); err != nil {
 		t.Fatalf("Logout returned error: %v", err)
 	}
 
@@ -118,3 +129,41 @@ func TestServiceLogoutRemovesSavedCredentials(t *testing.T) {
 		t.Fatalf("LoadCredentials error = %v, want ErrNoCredentials", err)
 	}
 }
+
+func TestServiceProfilesKeepSeparateCredentials(t *testing.T) {
+	store := NewStore(t.TempDir())
+	svc := NewService(store, &stubAPIClient{
+		validateResult: ValidationResult{Valid: true},
+	})
+
+	if _, err := svc.Login(context.Background(), "work", "work-token"); err != nil {
+		t.Fatalf("Login returned error: %v", err)
+	}
+
+	status, err := svc.Status(context.Background(), "")
+	if err != nil {
+		t.Fatalf("Status returned error: %v", err)
+	}
+	if status.HasCredentials {
+		t.Fatal("default profile should not see the work credential")
+	}
+
+	work, err := store.WithProfile("work")
+	if err != nil {
+		t.Fatalf("WithProfile returned error: %v", err)
+	}
+	creds, err := work.LoadCredentials()
+	if err != nil {
+		t.Fatalf("LoadCredentials returned error: %v", err)
+	}
+	if creds.SessionToken != "work-token" {
+		t.Fatalf("SessionToken = %q, want work-token", creds.SessionToken)
+	}
+
+	if err := svc.Logout("work"); err != nil {
+		t.Fatalf("Logout returned error: %v", err)
+	}
+	if _, err := work.LoadCredentials(); !errors.Is(err, ErrNoCredentials) {
+		t.Fatalf("LoadCredentials after logout error = %v, want ErrNoCredentials", err)
+	}
+}
@@ -4,15 +4,49 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 )
 
 const defaultBaseURL = "https://cursor.com"
 
+var errSessionInvalid = errors.New("cursor session token expired or invalid")
+
+// maxUsageExportSpan is the longest range requested from the usage export in
+// one call; longer ranges are fetched in consecutive chunks.
+const maxUsageExportSpan = 30 * 24 * time.Hour
+
+// UsageRange bounds a usage export. Since is inclusive and Until is exclusive;
+// a zero bound leaves that side to the dashboard's default export window.
+type UsageRange struct {
+	Since time.Time
+	Until time.Time
+}
+
+// chunks splits a bounded range into consecutive spans of at most
+// maxUsageExportSpan. Unbounded ranges are requested as-is.
+func (r UsageRange) chunks() []UsageRange {
+	if r.Since.IsZero() || r.Until.IsZero() || !r.Until.After(r.Since) {
+		return []UsageRange{r}
+	}
+	var chunks []UsageRange
+	for start := r.Since; start.Before(r.Until); start = start.Add(maxUsageExportSpan) {
+		end := start.Add(maxUsageExportSpan)
+		if end.After(r.Until) {
+			end = r.Until
+		}
+		chunks = append(chunks, UsageRange{Since: start, Until: end})
+	}
+	return chunks
+}
+
 // ValidationResult reports the result of validating a Cursor session token.
 type ValidationResult struct {
 	Valid          bool
@@ -44,46 +78,153 @@ type usageSummaryResponse struct {
 	BillingCycleStart string `json:"billingCycleStart"`
 	BillingCycleEnd   string `json:"billingCycleEnd"`
 	MembershipType    string `json:"membershipType"`
+	LimitType         string `json:"limitType"`
+	IndividualUsage   struct {
+		Plan     usageAllowanceResponse `json:"plan"`
+		OnDemand usageAllowanceResponse `json:"onDemand"`
+	} `json:"individualUsage"`
+}
+
+type usageAllowanceResponse struct {
+	Enabled   *bool    `json:"enabled"`
+	Used      float64  `json:"used"`
+	Limit     *float64 `json:"limit"`
+	Remaining *float64 `json:"remaining"`
 }
 
 // ValidateSession checks whether the supplied Cursor session token is accepted.
 func (c *Client) ValidateSession(ctx context.Context, token string) (ValidationResult, error) {
-	req, err := c.newRequest(ctx, http.MethodGet, "/api/usage-summary", token, nil)
+	body, err := c.getUsageSummary(ctx, token)
+	if errors.Is(err, errSessionInvalid) {
+		return ValidationResult{Message: "Cursor session token expired or invalid"}, nil
+	}
 	if err != nil {
 		return ValidationResult{}, err
 	}
+	if body.BillingCycleStart == "" || body.BillingCycleEnd == "" {
+		return ValidationResult{Message: "invalid response format from Cursor usage summary"}, nil
+	}
+
+	return ValidationResult{
+		Valid:          true,
+		MembershipType: body.MembershipType,
+	}, nil
+}
+
+// FetchUsageSummary returns the current billing cycle and plan allowance of
+// the account behind token.
+func (c *Client) FetchUsageSummary(ctx context.Context, token string) (UsageSummary, error) {
+	body, err := c.getUsageSummary(ctx, token)
+	if err != nil {
+		return UsageSummary{}, err
+	}
+	start, err := time.Parse(time.RFC3339Nano, body.BillingCycleStart)
+	if err != nil {
+		return UsageSummary{}, fmt.Errorf("invalid response format from Cursor usage summary: billing cycle start: %w", err)
+	}
+	end, err := time.Parse(time.RFC3339Nano, body.BillingCycleEnd)
+	if err != nil {
+		return UsageSummary{}, fmt.Errorf("invalid response format from Cursor usage summary: billing cycle end: %w", err)
+	}
+
+	return UsageSummary{
+		MembershipType:    body.MembershipType,
+		LimitType:         body.LimitType,
+		BillingCycleStart: start,
+		BillingCycleEnd:   end,
+		Included:          body.IndividualUsage.Plan.allowance(true),
+		OnDemand:          body.IndividualUsage.OnDemand.allowance(false),
+	}, nil
+}
+
+// allowance converts a usage-summary allowance, deriving Remaining from Limit
+// when Cursor omits it. enabledByDefault applies when "enabled" is absent.
+func (r usageAllowanceResponse) allowance(enabledByDefault bool) UsageAllowance {
+	a := UsageAllowance{
+		Enabled:   enabledByDefault,
+		Used:      r.Used,
+		Limit:     r.Limit,
+		Remaining: r.Remaining,
+	}
+	if r.Enabled != nil {
+		a.Enabled = *r.Enabled
+	}
+	if a.Limit != nil {
+		if a.Remaining == nil {
+			remaining := math.Max(*a.Limit-a.Used, 0)
+			a.Remaining = &remaining
+		}
+		if *a.Limit > 0 {
+			percent := a.Used / *a.Limit * 100
+			a.UsedPercent = &percent
+		}
+	}
+	return a
+}
+
+func (c *Client) getUsageSummary(ctx context.Context, token string) (usageSummaryResponse, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/usage-summary", token, nil)
+	if err != nil {
+		return usageSummaryResponse{}, err
+	}
 
 	resp, err := c.httpClient().Do(req)
 	if err != nil {
-		return ValidationResult{}, err
+		return usageSummaryResponse{}, err
 	}
 	defer resp.Body.Close()
 
 	switch resp.StatusCode {
 	case http.StatusUnauthorized, http.StatusForbidden:
-		return ValidationResult{Message: "Cursor session token expired or invalid"}, nil
+		return usageSummaryResponse{}, errSessionInvalid
 	}
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return ValidationResult{}, fmt.Errorf("cursor API returned status %s", resp.Status)
+		return usageSummaryResponse{}, fmt.Errorf("cursor API returned status %s", resp.Status)
 	}
 
 	var body usageSummaryResponse
 	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
-		return ValidationResult{}, fmt.Errorf("decode usage summary: %w", err)
+		return usageSummaryResponse{}, fmt.Errorf("decode usage summary: %w", err)
 	}
-	if body.BillingCycleStart == "" || body.BillingCycleEnd == "" {
-		return ValidationResult{Message: "invalid response format from Cursor usage summary"}, nil
+	return body, nil
+}
+
+// FetchUsageCSV downloads the Cursor usage CSV export for the active account.
+// Ranges longer than the export returns in one call are fetched in chunks and
+// combined into a single CSV; chunks without usage add no rows.
+func (c *Client) FetchUsageCSV(ctx context.Context, token string, r UsageRange) ([]byte, error) {
+	chunks := r.chunks()
+	if len(chunks) == 1 {
+		return c.fetchUsageCSVChunk(ctx, token, chunks[0])
 	}
 
-	return ValidationResult{
-		Valid:          true,
-		MembershipType: body.MembershipType,
-	}, nil
+	var combined []byte
+	for _, chunk := range chunks {
+		data, err := c.fetchUsageCSVChunk(ctx, token, chunk)
+		if err != nil {
+			return nil, fmt.Errorf("fetch usage from %s: %w", chunk.Since.Format("2006-01-02"), err)
+		}
+		if len(data) == 0 {
+			continue
+		}
+		combined, _, err = mergeUsageCSV(combined, data)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return combined, nil
 }
 
-// FetchUsageCSV downloads the Cursor usage CSV export for the active account.
-func (c *Client) FetchUsageCSV(ctx context.Context, token string) ([]byte, error) {
-	req, err := c.newRequest(ctx, http.MethodGet, "/api/dashboard/export-usage-events-csv?strategy=tokens", token, nil)
+func (c *Client) fetchUsageCSVChunk(ctx context.Context, token string, r UsageRange) ([]byte, error) {
+	query := url.Values{"strategy": {"tokens"}}
+	if !r.Since.IsZero() {
+		query.Set("startDate", strconv.FormatInt(r.Since.UnixMilli(), 10))
+	}
+	if !r.Until.IsZero() {
+		// The export treats endDate as inclusive.
+		query.Set("endDate", strconv.FormatInt(r.Until.UnixMilli()-1, 10))
+	}
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/dashboard/export-usage-events-csv?"+query.Encode(), token, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -96,7 +237,7 @@ func (c *Client) FetchUsageCSV(ctx context.Context, token string) ([]byte, error
 
 	switch resp.StatusCode {
 	case http.StatusUnauthorized, http.StatusForbidden:
-		return nil, fmt.Errorf("cursor session token expired or invalid")
+		return nil, errSessionInvalid
 	}
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		return nil, fmt.Errorf("cursor API returned status %s", resp.Status)
@@ -108,6 +249,10 @@ func (c *Client) FetchUsageCSV(ctx context.Context, token string) ([]byte, error
 	}
 	trimmed := bytes.TrimSpace(data)
 	trimmed = bytes.TrimPrefix(trimmed, []byte("\xef\xbb\xbf"))
+	if len(trimmed) == 0 {
+		// The export may answer a range without usage with an empty body.
+		return nil, nil
+	}
 	if !bytes.HasPrefix(trimmed, []byte("Date,")) {
 		return nil, fmt.Errorf("invalid response from Cursor API: expected CSV data")
 	}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -61,6 +62,62 @@ func TestClientValidateSession_InvalidToken(t *testing.T) {
 	}
 }
 
+func TestClientFetchUsageSummary_DecodesAllowances(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/usage-summary" {
+			t.Fatalf("path = %q, want /api/usage-summary", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"billingCycleStart":"2026-04-01T00:00:00.000Z",
+			"billingCycleEnd":"2026-05-01T00:00:00.000Z",
+			"membershipType":"pro",
+			"limitType":"user",
+			"individualUsage":{
+				"plan":{"enabled":true,"used":150,"limit":500},
+				"onDemand":{"enabled":false,"used":0,"limit":null,"remaining":null}
+			}
+		}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, server.Client())
+	summary, err := client.FetchUsageSummary(context.Background(), "token-123")
+	if err != nil {
+		t.Fatalf("FetchUsageSummary returned error: %v", err)
+	}
+	if summary.MembershipType != "pro" || summary.LimitType != "user" {
+		t.Fatalf("summary = %+v, want membership and limit type", summary)
+	}
+	if !summary.BillingCycleStart.Equal(time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)) ||
+		!summary.BillingCycleEnd.Equal(time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("billing cycle = %s to %s, want April 2026", summary.BillingCycleStart, summary.BillingCycleEnd)
+	}
+	included := summary.Included
+	if !included.Enabled || included.Used != 150 || included.Limit == nil || *included.Limit != 500 {
+		t.Fatalf("included = %+v, want 150 of 500", included)
+	}
+	if included.Remaining == nil || *included.Remaining != 350 || included.UsedPercent == nil || *included.UsedPercent != 30 {
+		t.Fatalf("included = %+v, want 350 remaining derived from the limit", included)
+	}
+	if summary.OnDemand.Enabled || summary.OnDemand.Limit != nil || summary.OnDemand.Remaining != nil {
+		t.Fatalf("on-demand = %+v, want disabled without limit", summary.OnDemand)
+	}
+}
+
+func TestClientFetchUsageSummary_InvalidToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, server.Client())
+	_, err := client.FetchUsageSummary(context.Background(), "bad-token")
+	if err == nil || !strings.Contains(err.Error(), "expired or invalid") {
+		t.Fatalf("FetchUsageSummary error = %v, want invalid-token error", err)
+	}
+}
+
 func TestClientFetchUsageCSV_Success(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/api/dashboard/export-usage-events-csv" {
@@ -69,13 +126,16 @@ func TestClientFetchUsageCSV_Success(t *testing.T) {
 		if got := r.URL.Query().Get("strategy"); got != "tokens" {
 			t.Fatalf("strategy = %q, want tokens", got)
 		}
+		if r.URL.Query().Has("startDate") || r.URL.Query().Has("endDate") {
+			t.Fatalf("query = %q, want no date bounds without a range", r.URL.RawQuery)
+		}
 		w.Header().Set("Content-Type", "text/csv")
 		_, _ = w.Write([]byte("Date,Model,Input (w/ Cache Write),Input (w/o Cache Write),Cache Read,Output Tokens\n"))
 	}))
 	defer server.Close()
 
 	client := NewClient(server.URL, server.Client())
-	csv, err := client.FetchUsageCSV(context.Background(), "token-123")
+	csv, err := client.FetchUsageCSV(context.Background(), "token-123", UsageRange{})
 	if err != nil {
 		t.Fatalf("FetchUsageCSV returned error: %v", err)
 	}
@@ -84,6 +144,112 @@ func TestClientFetchUsageCSV_Success(t *testing.T) {
 	}
 }
 
+func TestClientFetchUsageCSV_FetchesLongRangesInChunks(t *testing.T) {
+	var requested [][2]int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start, err := strconv.ParseInt(r.URL.Query().Get("startDate"), 10, 64)
+		if err != nil {
+			t.Fatalf("startDate = %q: %v", r.URL.Query().Get("startDate"), err)
+		}
+		end, err := strconv.ParseInt(r.URL.Query().Get("endDate"), 10, 64)
+		if err != nil {
+			t.Fatalf("endDate = %q: %v", r.URL.Query().Get("endDate"), err)
+		}
+		requested = append(requested, [2]int64{start, end})
+
+		// One request per chunk, dated at the start of the chunk.
+		day := time.UnixMilli(start).UTC().Format("2006-01-02T15:04:05.000Z")
+		w.Header().Set("Content-Type", "text/csv")
+		_, _ = w.Write(ledgerTestCSV(`"` + day + `","Included","gpt-5","0","100","0","10"`))
+	}))
+	defer server.Close()
+
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	client := NewClient(server.URL, server.Client())
+	data, err := client.FetchUsageCSV(context.Background(), "token-123", UsageRange{Since: since, Until: until})
+	if err != nil {
+		t.Fatalf("FetchUsageCSV returned error: %v", err)
+	}
+
+	boundary := since.Add(maxUsageExportSpan)
+	want := [][2]int64{
+		{since.UnixMilli(), boundary.UnixMilli() - 1},
+		{boundary.UnixMilli(), until.UnixMilli() - 1},
+	}
+	if len(requested) != len(want) {
+		t.Fatalf("got %d requests %v, want %v", len(requested), requested, want)
+	}
+	for i := range want {
+		if requested[i] != want[i] {
+			t.Fatalf("request %d bounds = %v, want %v", i, requested[i], want[i])
+		}
+	}
+
+	parsed, err := readUsageCSV(data)
+	if err != nil {
+		t.Fatalf("readUsageCSV returned error: %v", err)
+	}
+	if len(parsed.rows) != 2 {
+		t.Fatalf("combined CSV = %q, want one row per chunk", data)
+	}
+	for _, day := range []string{"2026-01-01T00:00:00.000Z", "2026-01-31T00:00:00.000Z"} {
+		if !strings.Contains(string(data), day) {
+			t.Fatalf("combined CSV = %q, want row from %s", data, day)
+		}
+	}
+}
+
+func TestClientFetchUsageCSV_SkipsChunksWithoutUsage(t *testing.T) {
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	quiet := since.Add(maxUsageExportSpan)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start, err := strconv.ParseInt(r.URL.Query().Get("startDate"), 10, 64)
+		if err != nil {
+			t.Fatalf("startDate = %q: %v", r.URL.Query().Get("startDate"), err)
+		}
+		w.Header().Set("Content-Type", "text/csv")
+		if start == quiet.UnixMilli() {
+			// A month without usage comes back as an empty body.
+			return
+		}
+		day := time.UnixMilli(start).UTC().Format("2006-01-02T15:04:05.000Z")
+		_, _ = w.Write(ledgerTestCSV(`"` + day + `","Included","gpt-5","0","100","0","10"`))
+	}))
+	defer server.Close()
+
+	until := quiet.Add(2 * maxUsageExportSpan)
+	client := NewClient(server.URL, server.Client())
+	data, err := client.FetchUsageCSV(context.Background(), "token-123", UsageRange{Since: since, Until: until})
+	if err != nil {
+		t.Fatalf("FetchUsageCSV returned error: %v", err)
+	}
+	parsed, err := readUsageCSV(data)
+	if err != nil {
+		t.Fatalf("readUsageCSV returned error: %v", err)
+	}
+	if len(parsed.rows) != 2 {
+		t.Fatalf("combined CSV = %q, want rows from the chunks around the quiet one", data)
+	}
+}
+
+func TestClientFetchUsageCSV_QuietSingleChunkRange(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/csv")
+	}))
+	defer server.Close()
+
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	client := NewClient(server.URL, server.Client())
+	data, err := client.FetchUsageCSV(context.Background(), "token-123", UsageRange{Since: since, Until: since.AddDate(0, 0, 7)})
+	if err != nil {
+		t.Fatalf("FetchUsageCSV returned error: %v", err)
+	}
+	if len(data) != 0 {
+		t.Fatalf("data = %q, want no rows for a quiet range", data)
+	}
+}
+
 func TestClientFetchUsageCSV_AcceptsUTF8BOM(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "text/csv")
@@ -92,7 +258,7 @@ func TestClientFetchUsageCSV_AcceptsUTF8BOM(t *testing.T) {
 	defer server.Close()
 
 	client := NewClient(server.URL, server.Client())
-	csv, err := client.FetchUsageCSV(context.Background(), "token-123")
+	csv, err := client.FetchUsageCSV(context.Background(), "token-123", UsageRange{})
 	if err != nil {
 		t.Fatalf("FetchUsageCSV returned error: %v", err)
 	}
@@ -109,7 +275,7 @@ func TestClientFetchUsageCSV_RejectsInvalidResponse(t *testing.T) {
 	defer server.Close()
 
 	client := NewClient(server.URL, server.Client())
-	_, err := client.FetchUsageCSV(context.Background(), "token-123")
+	_, err := client.FetchUsageCSV(context.Background(), "token-123", UsageRange{})
 	if err == nil {
 		t.Fatal("expected invalid response error")
 	}
@@ -127,7 +293,7 @@ func TestClientFetchUsageCSV_PropagatesNetworkError(t *testing.T) {
 	}
 
 	client := NewClient("https://cursor.example.test", httpClient)
-	_, err := client.FetchUsageCSV(context.Background(), "token-123")
+	_, err := client.FetchUsageCSV(context.Background(), "token-123", UsageRange{})
 	if err == nil {
 		t.Fatal("expected network error")
 	}
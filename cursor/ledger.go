@@ -0,0 +1,195 @@
+package cursor
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ledgerKeyColumns identify one usage row across dashboard exports, together
+// with ledgerTokenColumns.
+var ledgerKeyColumns = []string{"Date", "Kind", "Model"}
+
+var ledgerTokenColumns = []string{
+	"Input (w/ Cache Write)",
+	"Input (w/o Cache Write)",
+	"Cache Read",
+	"Output Tokens",
+}
+
+// LedgerMerge counts the rows of a merge into the cumulative synced CSV.
+type LedgerMerge struct {
+	NewRows   int
+	KnownRows int
+	TotalRows int
+}
+
+type usageCSV struct {
+	header []string
+	rows   [][]string
+}
+
+// mergeUsageCSV merges a freshly downloaded usage export into the existing
+// ledger. Rows are matched on date, kind, model, and token columns; identical
+// rows within one export are separate requests, so each key is kept as many
+// times as the most either side contains it. Rows are ordered newest first,
+// like the dashboard export.
+func mergeUsageCSV(ledger, download []byte) ([]byte, LedgerMerge, error) {
+	fetched, err := readUsageCSV(download)
+	if err != nil {
+		return nil, LedgerMerge{}, err
+	}
+	if fetched == nil || columnIndex(fetched.header, "Date") < 0 {
+		return nil, LedgerMerge{}, errors.New("cursor sync returned a CSV without a Date column")
+	}
+	known, err := readUsageCSV(ledger)
+	if err != nil {
+		return nil, LedgerMerge{}, err
+	}
+	if known == nil {
+		known = &usageCSV{}
+	}
+
+	header := append([]string(nil), fetched.header...)
+	for _, name := range known.header {
+		if columnIndex(header, name) < 0 {
+			header = append(header, name)
+		}
+	}
+
+	knownCounts := make(map[string]int)
+	for _, row := range known.rows {
+		knownCounts[ledgerRowKey(known.header, row)]++
+	}
+
+	var merge LedgerMerge
+	merged := make([][]string, 0, len(fetched.rows)+len(known.rows))
+	fetchedCounts := make(map[string]int)
+	for _, row := range fetched.rows {
+		key := ledgerRowKey(fetched.header, row)
+		fetchedCounts[key]++
+		if fetchedCounts[key] <= knownCounts[key] {
+			merge.KnownRows++
+		} else {
+			merge.NewRows++
+		}
+		merged = append(merged, remapRow(fetched.header, row, header))
+	}
+	// Keep ledger rows the download no longer covers.
+	for _, row := range known.rows {
+		key := ledgerRowKey(known.header, row)
+		if fetchedCounts[key] > 0 {
+			fetchedCounts[key]--
+			continue
+		}
+		merged = append(merged, remapRow(known.header, row, header))
+	}
+
+	dateIdx := columnIndex(header, "Date")
+	sort.SliceStable(merged, func(i, j int) bool {
+		return normalizeLedgerDate(merged[i][dateIdx]) > normalizeLedgerDate(merged[j][dateIdx])
+	})
+	merge.TotalRows = len(merged)
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(header); err != nil {
+		return nil, LedgerMerge{}, err
+	}
+	if err := w.WriteAll(merged); err != nil {
+		return nil, LedgerMerge{}, err
+	}
+	return buf.Bytes(), merge, nil
+}
+
+// readUsageCSV returns nil for empty input. Rows shorter than the header are
+// padded so every row can be remapped by column name.
+func readUsageCSV(data []byte) (*usageCSV, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, nil
+	}
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+
+	header, err := r.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i, name := range header {
+		header[i] = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+	}
+
+	parsed := &usageCSV{header: header}
+	for {
+		row, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		for len(row) < len(header) {
+			row = append(row, "")
+		}
+		parsed.rows = append(parsed.rows, row)
+	}
+	return parsed, nil
+}
+
+func ledgerRowKey(header, row []string) string {
+	value := func(name string) string {
+		if idx := columnIndex(header, name); idx >= 0 && idx < len(row) {
+			return strings.TrimSpace(row[idx])
+		}
+		return ""
+	}
+	parts := make([]string, 0, len(ledgerKeyColumns)+len(ledgerTokenColumns))
+	for _, name := range ledgerKeyColumns {
+		if name == "Date" {
+			parts = append(parts, normalizeLedgerDate(value(name)))
+			continue
+		}
+		parts = append(parts, value(name))
+	}
+	for _, name := range ledgerTokenColumns {
+		parts = append(parts, strings.ReplaceAll(value(name), ",", ""))
+	}
+	return strings.Join(parts, "\x00")
+}
+
+// normalizeLedgerDate renders RFC 3339 timestamps in UTC with fixed precision,
+// so the same request matches regardless of export formatting and sorts
+// chronologically. Other values are returned unchanged.
+func normalizeLedgerDate(value string) string {
+	ts, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(value))
+	if err != nil {
+		return strings.TrimSpace(value)
+	}
+	return ts.UTC().Format("2006-01-02T15:04:05.000000000Z")
+}
+
+func remapRow(from, row, to []string) []string {
+	out := make([]string, len(to))
+	for i, name := range to {
+		if idx := columnIndex(from, name); idx >= 0 && idx < len(row) {
+			out[i] = row[idx]
+		}
+	}
+	return out
+}
+
+func columnIndex(header []string, name string) int {
+	for i, column := range header {
+		if column == name {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,65 @@
+package cursor
+
+import (
+	"strings"
+	"testing"
+)
+
+const ledgerTestHeader = "Date,Kind,Model,Input (w/ Cache Write),Input (w/o Cache Write),Cache Read,Output Tokens"
+
+func ledgerTestCSV(rows ...string) []byte {
+	return []byte(ledgerTestHeader + "\n" + strings.Join(rows, "\n") + "\n")
+}
+
+func TestMergeUsageCSV_KeepsRowsOutsideDownloadWindow(t *testing.T) {
+	ledger := ledgerTestCSV(
+		`"2026-03-02T10:00:00.000Z","Included","gpt-5","0","100","0","10"`,
+		`"2026-01-05T10:00:00.000Z","Included","auto","0","7","0","1"`,
+	)
+	download := ledgerTestCSV(
+		`"2026-03-03T09:00:00.000Z","On-Demand","gpt-5","0","50","0","5"`,
+		// Same request as the ledger row, with different timestamp precision.
+		`"2026-03-02T10:00:00Z","Included","gpt-5","0","100","0","10"`,
+	)
+
+	merged, merge, err := mergeUsageCSV(ledger, download)
+	if err != nil {
+		t.Fatalf("mergeUsageCSV returned error: %v", err)
+	}
+	if merge != (LedgerMerge{NewRows: 1, KnownRows: 1, TotalRows: 3}) {
+		t.Fatalf("merge = %+v, want 1 new, 1 known, 3 total", merge)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(merged)), "\n")
+	if len(lines) != 4 || lines[0] != ledgerTestHeader {
+		t.Fatalf("merged csv = %q, want header and 3 rows", merged)
+	}
+	for i, prefix := range []string{"2026-03-03T09", "2026-03-02T10", "2026-01-05T10"} {
+		if !strings.HasPrefix(lines[i+1], prefix) {
+			t.Fatalf("row %d = %q, want newest-first order starting with %s", i, lines[i+1], prefix)
+		}
+	}
+}
+
+func TestMergeUsageCSV_KeepsIdenticalRowsFromOneExport(t *testing.T) {
+	row := `"2026-03-02T10:00:00.000Z","Included","gpt-5","0","5","0","1"`
+	ledger := ledgerTestCSV(row, row)
+	download := ledgerTestCSV(row, row, row)
+
+	merged, merge, err := mergeUsageCSV(ledger, download)
+	if err != nil {
+		t.Fatalf("mergeUsageCSV returned error: %v", err)
+	}
+	if merge != (LedgerMerge{NewRows: 1, KnownRows: 2, TotalRows: 3}) {
+		t.Fatalf("merge = %+v, want the third identical row counted as new", merge)
+	}
+	if got := strings.Count(string(merged), "2026-03-02T10:00:00.000Z"); got != 3 {
+		t.Fatalf("merged csv has %d copies, want 3: %q", got, merged)
+	}
+}
+
+func TestMergeUsageCSV_RejectsDownloadWithoutDateColumn(t *testing.T) {
+	if _, _, err := mergeUsageCSV(nil, []byte("<html>sign in</html>\n")); err == nil {
+		t.Fatal("expected an error for a download without a Date column")
+	}
+}
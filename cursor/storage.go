@@ -3,14 +3,22 @@ package cursor
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"runtime"
 	"time"
 )
 
 var ErrNoCredentials = errors.New("cursor credentials not found")
 
+// DefaultProfile is the Cursor profile whose files live directly under the
+// storage root, as they did before named profiles existed.
+const DefaultProfile = "default"
+
+var profileNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]{0,63}$`)
+
 var (
 	userHomeDir                   = os.UserHomeDir
 	renameFile                    = os.Rename
@@ -24,9 +32,12 @@ type Credentials struct {
 	SavedAt      time.Time `json:"saved_at"`
 }
 
-// Store manages Cursor credentials and synced CSV cache files.
+// Store manages Cursor credentials, the synced CSV ledger, and raw sync snapshots.
+// Profile selects a named account; other profiles keep their files under
+// profiles/<name>/ with the same layout as the default profile.
 type Store struct {
 	RootDir     string
+	Profile     string
 	atomicWrite func(path string, data []byte, perm os.FileMode) error
 }
 
@@ -39,6 +50,27 @@ func NewStore(rootDir string) Store {
 	}
 }
 
+// ValidateProfileName reports whether name can be used as a Cursor profile.
+func ValidateProfileName(name string) error {
+	if !profileNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid Cursor profile %q: use up to 64 letters, digits, '.', '_', or '-', starting with a letter or digit", name)
+	}
+	return nil
+}
+
+// WithProfile returns a copy of s for the named profile. An empty name selects
+// DefaultProfile.
+func (s Store) WithProfile(name string) (Store, error) {
+	if name == "" {
+		name = DefaultProfile
+	}
+	if err := ValidateProfileName(name); err != nil {
+		return Store{}, err
+	}
+	s.Profile = name
+	return s, nil
+}
+
 // DefaultRootDir returns the default codetok-owned Cursor storage root.
 func DefaultRootDir() (string, error) {
 	home, err := userHomeDir()
@@ -58,20 +90,44 @@ func (s Store) SyncedCSVPath() (string, error) {
 	return s.syncedCSVPath()
 }
 
+// SnapshotDir returns the directory holding a dated copy of every sync download.
+// It sits outside synced/ so reports never read the snapshots directly.
+func (s Store) SnapshotDir() (string, error) {
+	dir, err := s.profileDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "snapshots"), nil
+}
+
 func (s Store) credentialsPath() (string, error) {
-	root, err := s.rootDir()
+	dir, err := s.profileDir()
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(root, "credentials.json"), nil
+	return filepath.Join(dir, "credentials.json"), nil
 }
 
 func (s Store) syncedCSVPath() (string, error) {
+	dir, err := s.profileDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "synced", "usage.csv"), nil
+}
+
+func (s Store) profileDir() (string, error) {
 	root, err := s.rootDir()
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(root, "synced", "usage.csv"), nil
+	if s.Profile == "" || s.Profile == DefaultProfile {
+		return root, nil
+	}
+	if err := ValidateProfileName(s.Profile); err != nil {
+		return "", err
+	}
+	return filepath.Join(root, "profiles", s.Profile), nil
 }
 
 func (s Store) rootDir() (string, error) {
@@ -164,6 +220,68 @@ func (s Store) WriteSyncedCSV(data []byte) (string, error) {
 	return path, nil
 }
 
+// MergeSyncedCSV merges a downloaded usage export into the synced CSV ledger,
+// keeping rows that have aged out of the dashboard's export window.
+func (s Store) MergeSyncedCSV(data []byte) (string, LedgerMerge, error) {
+	if len(data) == 0 {
+		return "", LedgerMerge{}, errors.New("cursor sync returned an empty CSV payload")
+	}
+
+	path, err := s.syncedCSVPath()
+	if err != nil {
+		return "", LedgerMerge{}, err
+	}
+	ledger, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return "", LedgerMerge{}, err
+	}
+	merged, merge, err := mergeUsageCSV(ledger, data)
+	if err != nil {
+		return "", LedgerMerge{}, err
+	}
+	if err := s.writer()(path, merged, 0o600); err != nil {
+		return "", LedgerMerge{}, err
+	}
+	return path, merge, nil
+}
+
+// WriteSnapshot stores a raw sync download under SnapshotDir, named by its UTC
+// download time. A download in the same second as an earlier one gets a
+// numbered suffix instead of replacing it.
+func (s Store) WriteSnapshot(data []byte, at time.Time) (string, error) {
+	dir, err := s.SnapshotDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+	stamp := "usage-" + at.UTC().Format("20060102T150405Z")
+	for n := 1; ; n++ {
+		name := stamp + ".csv"
+		if n > 1 {
+			name = fmt.Sprintf("%s-%d.csv", stamp, n)
+		}
+		path := filepath.Join(dir, name)
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if os.IsExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		_, err = f.Write(data)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			_ = os.Remove(path)
+			return "", err
+		}
+		return path, nil
+	}
+}
+
 func atomicWriteFile(path string, data []byte, perm os.FileMode) (err error) {
 	parent := filepath.Dir(path)
 	if err := os.MkdirAll(parent, 0o700); err != nil {
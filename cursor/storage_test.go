@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestStoreSaveCredentialsWritesRestrictedFile(t *testing.T) {
@@ -147,3 +148,73 @@ func TestStoreWriteSyncedCSVOverwritesExistingFileWhenRenameNeedsReplace(t *test
 		t.Fatalf("renameCalls = %d, want retry after removing existing destination", renameCalls)
 	}
 }
+
+func TestStoreWriteSnapshotKeepsDownloadsOfTheSameSecond(t *testing.T) {
+	store := NewStore(t.TempDir())
+	at := time.Date(2026, 3, 9, 12, 0, 0, 0, time.UTC)
+
+	first, err := store.WriteSnapshot([]byte("first"), at)
+	if err != nil {
+		t.Fatalf("WriteSnapshot returned error: %v", err)
+	}
+	second, err := store.WriteSnapshot([]byte("second"), at.Add(500*time.Millisecond))
+	if err != nil {
+		t.Fatalf("WriteSnapshot returned error: %v", err)
+	}
+	if filepath.Base(first) != "usage-20260309T120000Z.csv" || filepath.Base(second) != "usage-20260309T120000Z-2.csv" {
+		t.Fatalf("snapshot paths = %s, %s; want a suffix for the second download", first, second)
+	}
+	for path, want := range map[string]string{first: "first", second: "second"} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("ReadFile returned error: %v", err)
+		}
+		if string(data) != want {
+			t.Fatalf("%s = %q, want %q", path, data, want)
+		}
+	}
+}
+
+func TestStoreWithProfileSeparatesFiles(t *testing.T) {
+	root := t.TempDir()
+	work, err := NewStore(root).WithProfile("work")
+	if err != nil {
+		t.Fatalf("WithProfile returned error: %v", err)
+	}
+	personal, err := NewStore(root).WithProfile("")
+	if err != nil {
+		t.Fatalf("WithProfile returned error: %v", err)
+	}
+
+	for _, tc := range []struct {
+		store Store
+		dir   string
+	}{
+		{store: work, dir: filepath.Join(root, "profiles", "work")},
+		{store: personal, dir: root},
+	} {
+		credentials, err := tc.store.CredentialsPath()
+		if err != nil {
+			t.Fatalf("CredentialsPath returned error: %v", err)
+		}
+		synced, err := tc.store.SyncedCSVPath()
+		if err != nil {
+			t.Fatalf("SyncedCSVPath returned error: %v", err)
+		}
+		snapshots, err := tc.store.SnapshotDir()
+		if err != nil {
+			t.Fatalf("SnapshotDir returned error: %v", err)
+		}
+		if credentials != filepath.Join(tc.dir, "credentials.json") ||
+			synced != filepath.Join(tc.dir, "synced", "usage.csv") ||
+			snapshots != filepath.Join(tc.dir, "snapshots") {
+			t.Fatalf("profile %q paths = %s, %s, %s; want them under %s", tc.store.Profile, credentials, synced, snapshots, tc.dir)
+		}
+	}
+
+	for _, name := range []string{"../work", "work/personal", ".hidden", "has space"} {
+		if _, err := NewStore(root).WithProfile(name); err == nil {
+			t.Fatalf("WithProfile(%q) succeeded, want invalid profile error", name)
+		}
+	}
+}
@@ -3,17 +3,39 @@ package cursor
 import (
 	"context"
 	"errors"
+	"time"
 )
 
 // SyncResult describes a completed local CSV sync.
 type SyncResult struct {
-	Path  string
-	Bytes int
+	Path         string
+	SnapshotPath string
+	Bytes        int
+	// NewRows and KnownRows split the downloaded rows by whether the ledger
+	// already had them; TotalRows is the ledger size after the merge.
+	NewRows   int
+	KnownRows int
+	TotalRows int
 }
 
-// Sync fetches the Cursor usage CSV and atomically updates the local cache.
-func (s *Service) Sync(ctx context.Context) (SyncResult, error) {
-	creds, err := s.Store.LoadCredentials()
+// Sync fetches the Cursor usage CSV for r with the credential of profile, keeps
+// a dated snapshot of the download, and merges its rows into the profile's
+// ledger. A range with only Since set runs until now; a zero range fetches the
+// dashboard's default window. A range without usage returns a zero SyncResult.
+func (s *Service) Sync(ctx context.Context, profile string, r UsageRange) (SyncResult, error) {
+	store, err := s.Store.WithProfile(profile)
+	if err != nil {
+		return SyncResult{}, err
+	}
+	now := s.clock()
+	if !r.Since.IsZero() && r.Until.IsZero() {
+		r.Until = now
+	}
+	if !r.Since.IsZero() && !r.Until.After(r.Since) {
+		return SyncResult{}, errors.New("cursor sync range is empty: until must be after since")
+	}
+
+	creds, err := store.LoadCredentials()
 	if err != nil {
 		if errors.Is(err, ErrNoCredentials) {
 			return SyncResult{}, ErrNoCredentials
@@ -21,18 +43,40 @@ func (s *Service) Sync(ctx context.Context) (SyncResult, error) {
 		return SyncResult{}, err
 	}
 
-	data, err := s.Client.FetchUsageCSV(ctx, creds.SessionToken)
+	data, err := s.Client.FetchUsageCSV(ctx, creds.SessionToken, r)
 	if err != nil {
 		return SyncResult{}, err
 	}
+	if len(data) == 0 {
+		if !r.Since.IsZero() {
+			// A backfill range without usage has nothing to snapshot or merge.
+			return SyncResult{}, nil
+		}
+		return SyncResult{}, errors.New("cursor sync returned an empty CSV payload")
+	}
 
-	path, err := s.Store.WriteSyncedCSV(data)
+	snapshotPath, err := store.WriteSnapshot(data, now)
+	if err != nil {
+		return SyncResult{}, err
+	}
+	path, merge, err := store.MergeSyncedCSV(data)
 	if err != nil {
 		return SyncResult{}, err
 	}
 
 	return SyncResult{
-		Path:  path,
-		Bytes: len(data),
+		Path:         path,
+		SnapshotPath: snapshotPath,
+		Bytes:        len(data),
+		NewRows:      merge.NewRows,
+		KnownRows:    merge.KnownRows,
+		TotalRows:    merge.TotalRows,
 	}, nil
 }
+
+func (s *Service) clock() time.Time {
+	if s.now != nil {
+		return s.now()
+	}
+	return time.Now()
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 	"errors"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestServiceSyncWritesCSVToLocalCache(t *testing.T) {
@@ -17,7 +20,7 @@ func TestServiceSyncWritesCSVToLocalCache(t *testing.T) {
 		fetchCSV: []byte("Date,Model,Input (w/ Cache Write),Input (w/o Cache Write),Cache Read,Output Tokens\n"),
 	})
 
-	result, err := svc.Sync(context.Background())
+	result, err := svc.Sync(context.Background(), "", UsageRange{})
 	if err != nil {
 		t.Fatalf("Sync returned error: %v", err)
 	}
@@ -51,7 +54,7 @@ func TestServiceSyncPreservesExistingCacheOnFailure(t *testing.T) {
 		fetchErr: errors.New("network down"),
 	})
 
-	if _, err := svc.Sync(context.Background()); err == nil {
+	if _, err := svc.Sync(context.Background(), "", UsageRange{}); err == nil {
 		t.Fatal("expected Sync to fail")
 	}
 
@@ -67,3 +70,121 @@ func TestServiceSyncPreservesExistingCacheOnFailure(t *testing.T) {
 		t.Fatalf("csv = %q, want previous cache to remain", string(got))
 	}
 }
+
+func TestServiceSyncMergesIntoLedgerAndKeepsSnapshots(t *testing.T) {
+	store := NewStore(t.TempDir())
+	if err := store.SaveCredentials(Credentials{SessionToken: "token-123"}); err != nil {
+		t.Fatalf("SaveCredentials returned error: %v", err)
+	}
+	client := &stubAPIClient{fetchCSV: ledgerTestCSV(
+		`"2026-03-02T10:00:00.000Z","Included","gpt-5","0","100","0","10"`,
+		`"2026-01-05T10:00:00.000Z","Included","auto","0","7","0","1"`,
+	)}
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	svc := NewService(store, client)
+	svc.now = func() time.Time { return now }
+
+	first, err := svc.Sync(context.Background(), "", UsageRange{})
+	if err != nil {
+		t.Fatalf("first Sync returned error: %v", err)
+	}
+	if first.NewRows != 2 || first.KnownRows != 0 || first.TotalRows != 2 {
+		t.Fatalf("first result = %+v, want 2 new rows", first)
+	}
+
+	// The dashboard window moved on: the January row is no longer exported.
+	client.fetchCSV = ledgerTestCSV(
+		`"2026-03-09T08:00:00.000Z","On-Demand","gpt-5","0","50","0","5"`,
+		`"2026-03-02T10:00:00.000Z","Included","gpt-5","0","100","0","10"`,
+	)
+	now = now.AddDate(0, 0, 7)
+	second, err := svc.Sync(context.Background(), "", UsageRange{})
+	if err != nil {
+		t.Fatalf("second Sync returned error: %v", err)
+	}
+	if second.NewRows != 1 || second.KnownRows != 1 || second.TotalRows != 3 {
+		t.Fatalf("second result = %+v, want 1 new, 1 known, 3 total", second)
+	}
+
+	ledger, err := os.ReadFile(second.Path)
+	if err != nil {
+		t.Fatalf("ReadFile returned error: %v", err)
+	}
+	if !strings.Contains(string(ledger), "2026-01-05T10:00:00.000Z") {
+		t.Fatalf("ledger = %q, want the aged-out January row kept", ledger)
+	}
+
+	snapshotDir, err := store.SnapshotDir()
+	if err != nil {
+		t.Fatalf("SnapshotDir returned error: %v", err)
+	}
+	if filepath.Dir(second.SnapshotPath) != snapshotDir || filepath.Base(second.SnapshotPath) != "usage-20260309T120000Z.csv" {
+		t.Fatalf("SnapshotPath = %q, want dated file in %s", second.SnapshotPath, snapshotDir)
+	}
+	snapshots, err := os.ReadDir(snapshotDir)
+	if err != nil {
+		t.Fatalf("ReadDir returned error: %v", err)
+	}
+	if len(snapshots) != 2 {
+		t.Fatalf("got %d snapshots, want one per sync", len(snapshots))
+	}
+	raw, err := os.ReadFile(second.SnapshotPath)
+	if err != nil {
+		t.Fatalf("ReadFile returned error: %v", err)
+	}
+	if string(raw) != string(client.fetchCSV) {
+		t.Fatalf("snapshot = %q, want the raw download", raw)
+	}
+}
+
+func TestServiceSyncRangeWithoutUntilRunsToNow(t *testing.T) {
+	store := NewStore(t.TempDir())
+	if err := store.SaveCredentials(Credentials{SessionToken: "token-123"}); err != nil {
+		t.Fatalf("SaveCredentials returned error: %v", err)
+	}
+	client := &stubAPIClient{fetchCSV: ledgerTestCSV()}
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	svc := NewService(store, client)
+	svc.now = func() time.Time { return now }
+
+	since := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := svc.Sync(context.Background(), "", UsageRange{Since: since}); err != nil {
+		t.Fatalf("Sync returned error: %v", err)
+	}
+	if !client.fetchRange.Since.Equal(since) || !client.fetchRange.Until.Equal(now) {
+		t.Fatalf("fetch range = %+v, want %s to %s", client.fetchRange, since, now)
+	}
+
+	if _, err := svc.Sync(context.Background(), "", UsageRange{Since: now.AddDate(0, 0, 1)}); err == nil {
+		t.Fatal("expected Sync to reject a range starting after now")
+	}
+}
+
+func TestServiceSyncQuietRangeWritesNothing(t *testing.T) {
+	store := NewStore(t.TempDir())
+	if err := store.SaveCredentials(Credentials{SessionToken: "token-123"}); err != nil {
+		t.Fatalf("SaveCredentials returned error: %v", err)
+	}
+	client := &stubAPIClient{}
+	svc := NewService(store, client)
+
+	since := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	result, err := svc.Sync(context.Background(), "", UsageRange{Since: since, Until: since.AddDate(0, 0, 7)})
+	if err != nil {
+		t.Fatalf("Sync returned error: %v", err)
+	}
+	if result != (SyncResult{}) {
+		t.Fatalf("result = %+v, want no rows", result)
+	}
+	snapshotDir, err := store.SnapshotDir()
+	if err != nil {
+		t.Fatalf("SnapshotDir returned error: %v", err)
+	}
+	if _, err := os.Stat(snapshotDir); !os.IsNotExist(err) {
+		t.Fatalf("snapshot dir stat error = %v, want no snapshot for a quiet range", err)
+	}
+
+	if _, err := svc.Sync(context.Background(), "", UsageRange{}); err == nil {
+		t.Fatal("expected an empty default-window download to fail")
+	}
+}
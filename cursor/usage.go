@@ -0,0 +1,63 @@
+package cursor
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+)
+
+// UsageSummary reports the current Cursor billing cycle of one profile and how
+// much of its allowance has been used. Amounts are in the units Cursor's usage
+// summary reports for the plan.
+type UsageSummary struct {
+	Profile            string         `json:"profile"`
+	MembershipType     string         `json:"membership_type"`
+	LimitType          string         `json:"limit_type,omitempty"`
+	BillingCycleStart  time.Time      `json:"billing_cycle_start"`
+	BillingCycleEnd    time.Time      `json:"billing_cycle_end"`
+	CycleDaysRemaining int            `json:"cycle_days_remaining"`
+	Included           UsageAllowance `json:"included"`
+	OnDemand           UsageAllowance `json:"on_demand"`
+}
+
+// UsageAllowance is one part of a plan's allowance. Limit, Remaining, and
+// UsedPercent are nil when Cursor reports no limit.
+type UsageAllowance struct {
+	Enabled     bool     `json:"enabled"`
+	Used        float64  `json:"used"`
+	Limit       *float64 `json:"limit"`
+	Remaining   *float64 `json:"remaining"`
+	UsedPercent *float64 `json:"used_percent,omitempty"`
+}
+
+// Usage fetches the billing cycle and allowance of profile from Cursor.
+func (s *Service) Usage(ctx context.Context, profile string) (UsageSummary, error) {
+	store, err := s.Store.WithProfile(profile)
+	if err != nil {
+		return UsageSummary{}, err
+	}
+	creds, err := store.LoadCredentials()
+	if err != nil {
+		if errors.Is(err, ErrNoCredentials) {
+			return UsageSummary{}, ErrNoCredentials
+		}
+		return UsageSummary{}, err
+	}
+
+	summary, err := s.Client.FetchUsageSummary(ctx, creds.SessionToken)
+	if err != nil {
+		return UsageSummary{}, err
+	}
+	summary.Profile = store.Profile
+	summary.CycleDaysRemaining = daysUntil(s.clock(), summary.BillingCycleEnd)
+	return summary, nil
+}
+
+// daysUntil counts started days from now until end, or 0 once end has passed.
+func daysUntil(now, end time.Time) int {
+	if !end.After(now) {
+		return 0
+	}
+	return int(math.Ceil(end.Sub(now).Hours() / 24))
+}
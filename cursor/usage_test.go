@@ -0,0 +1,38 @@
+package cursor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestServiceUsageReportsProfileAndDaysLeft(t *testing.T) {
+	store := NewStore(t.TempDir())
+	work, err := store.WithProfile("work")
+	if err != nil {
+		t.Fatalf("WithProfile returned error: %v", err)
+	}
+	if err := work.SaveCredentials(Credentials{SessionToken: "work-token"}); err != nil {
+		t.Fatalf("SaveCredentials returned error: %v", err)
+	}
+
+	svc := NewService(store, &stubAPIClient{summary: UsageSummary{
+		MembershipType:    "pro",
+		BillingCycleStart: time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
+		BillingCycleEnd:   time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC),
+	}})
+	svc.now = func() time.Time { return time.Date(2026, 4, 19, 18, 0, 0, 0, time.UTC) }
+
+	summary, err := svc.Usage(context.Background(), "work")
+	if err != nil {
+		t.Fatalf("Usage returned error: %v", err)
+	}
+	if summary.Profile != "work" || summary.CycleDaysRemaining != 12 {
+		t.Fatalf("summary = %+v, want profile work with 12 days left", summary)
+	}
+
+	if _, err := svc.Usage(context.Background(), ""); !errors.Is(err, ErrNoCredentials) {
+		t.Fatalf("Usage for default profile error = %v, want ErrNoCredentials", err)
+	}
+}
@@ -67,7 +67,7 @@ func emptyDir(t *testing.T) string {
 func isolatedArgs(t *testing.T, extraArgs ...string) []string {
 	t.Helper()
 	empty := emptyDir(t)
-	base := []string{"--claude-dir", empty, "--codex-dir", empty, "--cursor-dir", empty}
+	base := []string{"--claude-dir", empty, "--codex-dir", empty, "--opencode-dir", empty, "--gemini-dir", empty, "--cursor-dir", empty}
 	return append(base, extraArgs...)
 }
 
@@ -78,6 +78,8 @@ func crossDayProviderArgs(t *testing.T) []string {
 		"--codex-dir", filepath.Join(root, "codex"),
 		"--claude-dir", filepath.Join(root, "claude"),
 		"--kimi-dir", filepath.Join(root, "kimi"),
+		"--opencode-dir", emptyDir(t),
+		"--gemini-dir", emptyDir(t),
 		"--cursor-dir", emptyDir(t),
 	}
 }
@@ -135,7 +137,7 @@ func writeCursorActivityDB(t *testing.T, rows []activityFixtureRow) string {
 func defaultCursorArgs(t *testing.T, extraArgs ...string) []string {
 	t.Helper()
 	empty := emptyDir(t)
-	base := []string{"--claude-dir", empty, "--codex-dir", empty, "--kimi-dir", empty}
+	base := []string{"--claude-dir", empty, "--codex-dir", empty, "--opencode-dir", empty, "--gemini-dir", empty, "--kimi-dir", empty}
 	return append(base, extraArgs...)
 }
 
@@ -444,6 +446,8 @@ func TestCursorActivityDoesNotPolluteDailyJSONTokenFields(t *testing.T) {
 	args := []string{
 		"--claude-dir", emptyDir(t),
 		"--codex-dir", emptyDir(t),
+		"--opencode-dir", emptyDir(t),
+		"--gemini-dir", emptyDir(t),
 		"--kimi-dir", emptyDir(t),
 		"daily", "--json", "--all",
 		"--cursor-dir", cursorDir,
@@ -466,10 +470,10 @@ func TestCursorActivityDoesNotPolluteDailyJSONTokenFields(t *testing.T) {
 		if !ok {
 			t.Fatalf("token_usage field missing or wrong type: %#v", row["token_usage"])
 		}
-		if len(tokenUsage) != 4 {
-			t.Fatalf("token_usage keys = %v, want exactly 4 token fields", tokenUsage)
+		if len(tokenUsage) != 5 {
+			t.Fatalf("token_usage keys = %v, want exactly 5 token fields", tokenUsage)
 		}
-		for _, key := range []string{"input_other", "output", "input_cache_read", "input_cache_creation"} {
+		for _, key := range []string{"input_other", "output", "output_reasoning", "input_cache_read", "input_cache_creation"} {
 			if _, ok := tokenUsage[key]; !ok {
 				t.Fatalf("token_usage missing key %q: %v", key, tokenUsage)
 			}
@@ -492,6 +496,8 @@ func TestCursorActivityDoesNotPolluteSessionJSONTokenFields(t *testing.T) {
 	args := []string{
 		"--claude-dir", emptyDir(t),
 		"--codex-dir", emptyDir(t),
+		"--opencode-dir", emptyDir(t),
+		"--gemini-dir", emptyDir(t),
 		"--kimi-dir", emptyDir(t),
 		"session", "--json",
 		"--cursor-dir", cursorDir,
@@ -514,10 +520,10 @@ func TestCursorActivityDoesNotPolluteSessionJSONTokenFields(t *testing.T) {
 		if !ok {
 			t.Fatalf("token_usage field missing or wrong type: %#v", session["token_usage"])
 		}
-		if len(tokenUsage) != 4 {
-			t.Fatalf("token_usage keys = %v, want exactly 4 token fields", tokenUsage)
+		if len(tokenUsage) != 5 {
+			t.Fatalf("token_usage keys = %v, want exactly 5 token fields", tokenUsage)
 		}
-		for _, key := range []string{"input_other", "output", "input_cache_read", "input_cache_creation"} {
+		for _, key := range []string{"input_other", "output", "output_reasoning", "input_cache_read", "input_cache_creation"} {
 			if _, ok := tokenUsage[key]; !ok {
 				t.Fatalf("token_usage missing key %q: %v", key, tokenUsage)
 			}
@@ -757,7 +763,7 @@ func TestClaudeSubagentSessions_JSONOutput(t *testing.T) {
 	bin := buildBinary(t)
 	claudeDir := claudeTestdataDir(t)
 	empty := emptyDir(t)
-	args := []string{"--claude-dir", claudeDir, "--codex-dir", empty, "--cursor-dir", empty, "--kimi-dir", empty, "session", "--json"}
+	args := []string{"--claude-dir", claudeDir, "--codex-dir", empty, "--opencode-dir", empty, "--gemini-dir", empty, "--cursor-dir", empty, "--kimi-dir", empty, "session", "--json"}
 	output := runCodetok(t, bin, args...)
 
 	var sessions []struct {
@@ -796,7 +802,7 @@ func TestClaudeSubagentSessions_DailyOutput(t *testing.T) {
 	bin := buildBinary(t)
 	claudeDir := claudeTestdataDir(t)
 	empty := emptyDir(t)
-	args := []string{"--claude-dir", claudeDir, "--codex-dir", empty, "--cursor-dir", empty, "--kimi-dir", empty, "daily", "--json", "--all"}
+	args := []string{"--claude-dir", claudeDir, "--codex-dir", empty, "--opencode-dir", empty, "--gemini-dir", empty, "--cursor-dir", empty, "--kimi-dir", empty, "daily", "--json", "--all"}
 	output := runCodetok(t, bin, args...)
 
 	var daily []provider.DailyStats
@@ -829,6 +835,8 @@ func TestDailyCommand_JSONOutput_CursorProvider(t *testing.T) {
 		"--kimi-dir", empty,
 		"--claude-dir", empty,
 		"--codex-dir", empty,
+		"--opencode-dir", empty,
+		"--gemini-dir", empty,
 	}
 	output := runCodetok(t, bin, args...)
 
@@ -875,6 +883,8 @@ func TestDailyCommand_DashboardOutput_CursorProvider(t *testing.T) {
 		"--kimi-dir", empty,
 		"--claude-dir", empty,
 		"--codex-dir", empty,
+		"--opencode-dir", empty,
+		"--gemini-dir", empty,
 	}
 	output := runCodetok(t, bin, args...)
 
@@ -896,6 +906,8 @@ func TestSessionCommand_JSONOutput_CursorProvider(t *testing.T) {
 		"--kimi-dir", empty,
 		"--claude-dir", empty,
 		"--codex-dir", empty,
+		"--opencode-dir", empty,
+		"--gemini-dir", empty,
 	}
 	output := runCodetok(t, bin, args...)
 
@@ -1139,3 +1151,76 @@ func TestSessionCommand_CursorDoesNotAccessRemoteAPI(t *testing.T) {
 		t.Fatalf("expected 0 proxy requests, got %d", requests())
 	}
 }
+
+func TestMCPCommand_ScriptedStdioClient(t *testing.T) {
+	bin := buildBinary(t)
+	args := isolatedArgs(t, "mcp", "--no-cache", "--kimi-dir", testdataDir(t))
+	script := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"e2e","version":"0"}}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"daily_usage","arguments":{"all":true}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"current_session_usage","arguments":{"provider":"kimi"}}}`,
+	}, "\n") + "\n"
+
+	cmd := exec.Command(bin, args...)
+	cmd.Stdin = strings.NewReader(script)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("mcp failed: %v\nstderr: %s", err, stderr.String())
+	}
+
+	type toolResult struct {
+		Content []struct {
+			Text string `json:"text"`
+		} `json:"content"`
+		IsError bool `json:"isError"`
+	}
+	results := make(map[string]json.RawMessage)
+	for _, line := range strings.Split(strings.TrimSpace(stdout.String()), "\n") {
+		var resp struct {
+			ID     json.RawMessage `json:"id"`
+			Result json.RawMessage `json:"result"`
+			Error  json.RawMessage `json:"error"`
+		}
+		if err := json.Unmarshal([]byte(line), &resp); err != nil {
+			t.Fatalf("stdout line is not JSON-RPC: %q", line)
+		}
+		if resp.Error != nil {
+			t.Fatalf("request %s failed: %s", resp.ID, resp.Error)
+		}
+		results[string(resp.ID)] = resp.Result
+	}
+	if len(results) != 4 {
+		t.Fatalf("expected 4 responses, got %d:\n%s", len(results), stdout.String())
+	}
+	if !strings.Contains(string(results["2"]), `"current_session_usage"`) {
+		t.Fatalf("tools/list missing current_session_usage: %s", results["2"])
+	}
+
+	var dailyResult toolResult
+	if err := json.Unmarshal(results["3"], &dailyResult); err != nil || dailyResult.IsError || len(dailyResult.Content) != 1 {
+		t.Fatalf("daily_usage result = %s (%v)", results["3"], err)
+	}
+	var daily []provider.DailyStats
+	if err := json.Unmarshal([]byte(dailyResult.Content[0].Text), &daily); err != nil {
+		t.Fatalf("daily_usage text is not daily JSON: %v", err)
+	}
+	totalTokens := 0
+	for _, d := range daily {
+		totalTokens += d.TokenUsage.Total()
+	}
+	if totalTokens != 1635+3610 {
+		t.Fatalf("daily_usage total = %d, want %d", totalTokens, 1635+3610)
+	}
+
+	var currentResult toolResult
+	if err := json.Unmarshal(results["4"], &currentResult); err != nil || currentResult.IsError {
+		t.Fatalf("current_session_usage result = %s (%v)", results["4"], err)
+	}
+	if !strings.Contains(currentResult.Content[0].Text, `"provider": "kimi"`) {
+		t.Fatalf("current_session_usage = %s, want a kimi session", currentResult.Content[0].Text)
+	}
+}
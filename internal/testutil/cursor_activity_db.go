@@ -10,11 +10,19 @@ import (
 )
 
 // CursorActivityRow represents one scored_commits fixture row for Cursor activity tests.
+// Empty commit metadata falls back to a generated hash, branch "main", and a
+// small scoredAt value.
 type CursorActivityRow struct {
 	ComposerAdded   int
 	ComposerDeleted int
 	TabAdded        int
 	TabDeleted      int
+
+	CommitHash    string
+	Branch        string
+	CommitDate    string
+	CommitMessage string
+	ScoredAt      int64
 }
 
 // WriteCursorActivityDB creates a temporary Cursor activity SQLite database for tests.
@@ -55,21 +63,28 @@ CREATE TABLE scored_commits (
 	}
 
 	for i, row := range rows {
+		scoredAt := row.ScoredAt
+		if scoredAt == 0 {
+			scoredAt = int64(1000 + i)
+		}
 		_, err := db.Exec(`
 INSERT INTO scored_commits (
 	commitHash, branchName, scoredAt, linesAdded, linesDeleted,
-	tabLinesAdded, tabLinesDeleted, composerLinesAdded, composerLinesDeleted
-) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	tabLinesAdded, tabLinesDeleted, composerLinesAdded, composerLinesDeleted,
+	commitMessage, commitDate
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 `,
-			cursorActivityCommitHash(i),
-			"main",
-			1000+i,
+			valueOr(row.CommitHash, cursorActivityCommitHash(i)),
+			valueOr(row.Branch, "main"),
+			scoredAt,
 			row.ComposerAdded+row.TabAdded,
 			row.ComposerDeleted+row.TabDeleted,
 			row.TabAdded,
 			row.TabDeleted,
 			row.ComposerAdded,
 			row.ComposerDeleted,
+			row.CommitMessage,
+			row.CommitDate,
 		)
 		if err != nil {
 			t.Fatalf("inserting fixture row %d: %v", i, err)
@@ -82,3 +97,10 @@ INSERT INTO scored_commits (
 func cursorActivityCommitHash(i int) string {
 	return fmt.Sprintf("commit-%d", i)
 }
+
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,170 @@
+package pricing
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/miss-you/codetok/provider"
+)
+
+//go:embed prices.json
+var defaultPricesJSON []byte
+
+var userHomeDir = os.UserHomeDir
+
+// Price holds USD prices per million tokens for one model.
+type Price struct {
+	Input      float64 `json:"input"`
+	Output     float64 `json:"output"`
+	CacheRead  float64 `json:"cache_read"`
+	CacheWrite float64 `json:"cache_write"`
+}
+
+// Cost returns the USD cost of usage at this price. Reasoning tokens are part
+// of Output and are billed at the output rate.
+func (p Price) Cost(usage provider.TokenUsage) float64 {
+	return (float64(usage.InputOther)*p.Input +
+		float64(usage.Output)*p.Output +
+		float64(usage.InputCacheRead)*p.CacheRead +
+		float64(usage.InputCacheCreate)*p.CacheWrite) / 1_000_000
+}
+
+// Table maps model names to prices.
+type Table struct {
+	prices map[string]Price
+}
+
+// Default returns the embedded default price table.
+func Default() *Table {
+	prices, err := parsePrices(defaultPricesJSON)
+	if err != nil {
+		panic(fmt.Sprintf("pricing: invalid embedded prices.json: %v", err))
+	}
+	return &Table{prices: prices}
+}
+
+// DefaultOverridePath returns the optional user price override file path.
+func DefaultOverridePath() (string, error) {
+	home, err := userHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".codetok", "pricing.json"), nil
+}
+
+// Load returns the default table with entries from an override file applied on top.
+// An empty path loads ~/.codetok/pricing.json when it exists. An explicit path must exist.
+func Load(path string) (*Table, error) {
+	table := Default()
+
+	explicit := strings.TrimSpace(path) != ""
+	if !explicit {
+		defaultPath, err := DefaultOverridePath()
+		if err != nil {
+			return table, nil
+		}
+		path = defaultPath
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !explicit && os.IsNotExist(err) {
+			return table, nil
+		}
+		return nil, fmt.Errorf("read pricing file: %w", err)
+	}
+	overrides, err := parsePrices(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse pricing file %s: %w", path, err)
+	}
+	for model, price := range overrides {
+		table.prices[model] = price
+	}
+	return table, nil
+}
+
+// Lookup returns the price for a model name. Names are matched case-insensitively,
+// ignoring any "vendor/" prefix; when there is no exact entry, the longest table
+// entry that prefixes the name at a "-", "@" or ":" boundary wins, so dated or
+// suffixed variants such as claude-sonnet-4-5-20250929 resolve to their base model.
+func (t *Table) Lookup(model string) (Price, bool) {
+	if t == nil {
+		return Price{}, false
+	}
+	key := normalizeKey(model)
+	if key == "" {
+		return Price{}, false
+	}
+	if price, ok := t.prices[key]; ok {
+		return price, true
+	}
+
+	var (
+		best      string
+		bestPrice Price
+	)
+	for name, price := range t.prices {
+		if len(name) <= len(best) || !strings.HasPrefix(key, name) {
+			continue
+		}
+		switch key[len(name)] {
+		case '-', '@', ':':
+			best = name
+			bestPrice = price
+		}
+	}
+	return bestPrice, best != ""
+}
+
+// Estimate prices usage for one model. Unpriced models yield an unknown estimate
+// that names the model.
+func (t *Table) Estimate(model string, usage provider.TokenUsage) provider.CostEstimate {
+	price, ok := t.Lookup(model)
+	if !ok {
+		return provider.CostEstimate{
+			Status:         provider.CostStatusUnknown,
+			UnpricedModels: []string{model},
+		}
+	}
+	return provider.CostEstimate{
+		USD:    price.Cost(usage),
+		Status: provider.CostStatusKnown,
+	}
+}
+
+func parsePrices(data []byte) (map[string]Price, error) {
+	var raw map[string]Price
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	prices := make(map[string]Price, len(raw))
+	for model, price := range raw {
+		key := normalizeKey(model)
+		if key == "" {
+			return nil, fmt.Errorf("empty model name")
+		}
+		if price.Input < 0 || price.Output < 0 || price.CacheRead < 0 || price.CacheWrite < 0 {
+			return nil, fmt.Errorf("negative price for model %q", model)
+		}
+		prices[key] = price
+	}
+	return prices, nil
+}
+
+func normalizeKey(model string) string {
+	key := strings.ToLower(strings.TrimSpace(model))
+	if i := strings.LastIndex(key, "/"); i >= 0 {
+		key = key[i+1:]
+	}
+	key = strings.ReplaceAll(key, "_", "-")
+	key = strings.ReplaceAll(key, " ", "-")
+	return key
+}
@@ -0,0 +1,166 @@
+package pricing
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/miss-you/codetok/provider"
+)
+
+func TestDefault_CoversNormalizedModelAliases(t *testing.T) {
+	table := Default()
+	for _, model := range []string{
+		"kimi-k2.5",
+		"kimi-k2-thinking",
+		"claude-haiku",
+		"claude-3-5-haiku",
+		"claude-3-haiku",
+		"claude-sonnet-4-5",
+		"gpt-5-codex",
+		"gemini-2.5-pro",
+	} {
+		if _, ok := table.Lookup(model); !ok {
+			t.Errorf("Lookup(%q) found no price", model)
+		}
+	}
+}
+
+func TestLookup_MatchesLongestPrefixAtBoundary(t *testing.T) {
+	table := &Table{prices: map[string]Price{
+		"gpt-5":             {Input: 1},
+		"gpt-5-codex":       {Input: 2},
+		"claude-4.5-sonnet": {Input: 3},
+	}}
+
+	tests := []struct {
+		model string
+		want  float64
+		ok    bool
+	}{
+		{model: "gpt-5", want: 1, ok: true},
+		{model: "GPT-5-Codex", want: 2, ok: true},
+		{model: "gpt-5-codex-high", want: 2, ok: true},
+		{model: "gpt-5-2025-08-07", want: 1, ok: true},
+		{model: "openai/gpt-5", want: 1, ok: true},
+		{model: "claude-4.5-sonnet-thinking", want: 3, ok: true},
+		{model: "gpt-5.1", ok: false},
+		{model: "gpt-50", ok: false},
+		{model: "", ok: false},
+	}
+	for _, tt := range tests {
+		price, ok := table.Lookup(tt.model)
+		if ok != tt.ok || price.Input != tt.want {
+			t.Errorf("Lookup(%q) = %+v, %v; want input %v, %v", tt.model, price, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestPriceCost_UsesPerMillionRates(t *testing.T) {
+	price := Price{Input: 3, Output: 15, CacheRead: 0.3, CacheWrite: 3.75}
+	usage := provider.TokenUsage{
+		InputOther:       1_000_000,
+		Output:           200_000,
+		OutputReasoning:  50_000,
+		InputCacheRead:   2_000_000,
+		InputCacheCreate: 100_000,
+	}
+
+	got := price.Cost(usage)
+	want := 3 + 3 + 0.6 + 0.375
+	if math.Abs(got-want) > 1e-9 {
+		t.Fatalf("Cost = %v, want %v", got, want)
+	}
+}
+
+func TestEstimate_MarksUnpricedModelsUnknown(t *testing.T) {
+	table := Default()
+
+	known := table.Estimate("claude-sonnet-4-5", provider.TokenUsage{InputOther: 1_000_000})
+	if known.Status != provider.CostStatusKnown || math.Abs(known.USD-3) > 1e-9 {
+		t.Fatalf("known estimate = %+v, want $3 known", known)
+	}
+
+	unknown := table.Estimate("mystery-model", provider.TokenUsage{InputOther: 1_000_000})
+	if unknown.Status != provider.CostStatusUnknown || unknown.USD != 0 {
+		t.Fatalf("unknown estimate = %+v, want zero unknown", unknown)
+	}
+	if len(unknown.UnpricedModels) != 1 || unknown.UnpricedModels[0] != "mystery-model" {
+		t.Fatalf("UnpricedModels = %v, want [mystery-model]", unknown.UnpricedModels)
+	}
+}
+
+func TestLoad_AppliesOverrideFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pricing.json")
+	content := `{"claude-sonnet-4-5": {"input": 1, "output": 2}, "internal-model": {"input": 4, "output": 8, "cache_read": 0.4, "cache_write": 5}}`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	table, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if price, _ := table.Lookup("claude-sonnet-4-5"); price.Input != 1 || price.Output != 2 {
+		t.Fatalf("override price = %+v, want input 1 output 2", price)
+	}
+	if _, ok := table.Lookup("internal-model"); !ok {
+		t.Fatal("override should add new models")
+	}
+	if _, ok := table.Lookup("gpt-5-codex"); !ok {
+		t.Fatal("override should keep default entries")
+	}
+}
+
+func TestLoad_UsesDefaultOverridePathWhenPresent(t *testing.T) {
+	home := t.TempDir()
+	restore := userHomeDir
+	userHomeDir = func() (string, error) { return home, nil }
+	t.Cleanup(func() { userHomeDir = restore })
+
+	table, err := Load("")
+	if err != nil {
+		t.Fatalf("Load without override returned error: %v", err)
+	}
+	if _, ok := table.Lookup("internal-model"); ok {
+		t.Fatal("unexpected override entry without a pricing file")
+	}
+
+	if err := os.MkdirAll(filepath.Join(home, ".codetok"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, ".codetok", "pricing.json"), []byte(`{"internal-model": {"input": 4}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	table, err = Load("")
+	if err != nil {
+		t.Fatalf("Load with default override returned error: %v", err)
+	}
+	if _, ok := table.Lookup("internal-model"); !ok {
+		t.Fatal("default override path was not applied")
+	}
+}
+
+func TestLoad_RejectsMissingOrInvalidExplicitFile(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := Load(filepath.Join(dir, "missing.json")); err == nil {
+		t.Fatal("expected error for missing explicit pricing file")
+	}
+
+	invalid := filepath.Join(dir, "invalid.json")
+	if err := os.WriteFile(invalid, []byte(`{"gpt-5": {"input": 1, "outptu": 2}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(invalid); err == nil {
+		t.Fatal("expected error for unknown price field")
+	}
+
+	negative := filepath.Join(dir, "negative.json")
+	if err := os.WriteFile(negative, []byte(`{"gpt-5": {"input": -1}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(negative); err == nil {
+		t.Fatal("expected error for negative price")
+	}
+}
@@ -0,0 +1,368 @@
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// usageEventCacheFormat versions the on-disk layout. Caches written with a
+// different format are discarded on open.
+const usageEventCacheFormat = 2
+
+// maxJSONLLineSize matches the bufio.Scanner buffer the JSONL parsers use.
+const maxJSONLLineSize = 1024 * 1024
+
+// resumeCheckSize is how many bytes before a resume offset must be unchanged
+// for an append-only log to be resumed instead of reparsed.
+const resumeCheckSize = 4096
+
+// JSONLUsageEventParser incrementally parses usage events from an append-only
+// JSONL log, one line at a time. The exported fields of an implementation are
+// its resumable state; UsageEventCache persists them with encoding/gob.
+type JSONLUsageEventParser interface {
+	ParseLine(line []byte)
+	Events() []UsageEvent
+}
+
+// UsageEventCache persists parsed usage events per source file, keyed by path,
+// size, modification time, and parser version, so reports only reparse new or
+// changed logs. A nil *UsageEventCache parses without caching.
+type UsageEventCache struct {
+	path    string
+	mu      sync.Mutex
+	entries map[string]*usageEventCacheEntry
+	dirty   bool
+}
+
+type usageEventCacheFile struct {
+	Format  int
+	Entries map[string]*usageEventCacheEntry
+}
+
+type usageEventCacheEntry struct {
+	SourcePath string
+	Version    string
+	Size       int64
+	ModTime    int64
+	// Events holds the parse result of whole-file entries.
+	Events []UsageEvent
+	// Offset, Tail, and State let JSONL entries resume after the last complete line.
+	Offset int64
+	Tail   [sha256.Size]byte
+	State  []byte
+	// MalformedLines counts complete lines before Offset that were not valid JSON.
+	MalformedLines int
+}
+
+// DefaultUsageEventCachePath returns the default cache file under ~/.codetok/cache.
+func DefaultUsageEventCachePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".codetok", "cache", "usage-events.gob"), nil
+}
+
+// OpenUsageEventCache loads the cache file at path. A missing, unreadable, or
+// outdated cache file yields an empty cache.
+func OpenUsageEventCache(path string) (*UsageEventCache, error) {
+	c := &UsageEventCache{
+		path:    path,
+		entries: make(map[string]*usageEventCacheEntry),
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return c, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var file usageEventCacheFile
+	if err := gob.NewDecoder(bufio.NewReader(f)).Decode(&file); err != nil || file.Format != usageEventCacheFormat {
+		c.dirty = true
+		return c, nil
+	}
+	if file.Entries != nil {
+		c.entries = file.Entries
+	}
+	return c, nil
+}
+
+// ClearUsageEventCache removes the cache file at path.
+func ClearUsageEventCache(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Save writes the cache back to disk when anything changed, dropping entries
+// whose source files no longer exist.
+func (c *UsageEventCache) Save() error {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, entry := range c.entries {
+		if _, err := os.Stat(entry.SourcePath); os.IsNotExist(err) {
+			delete(c.entries, key)
+			c.dirty = true
+		}
+	}
+	if !c.dirty {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	file := usageEventCacheFile{Format: usageEventCacheFormat, Entries: c.entries}
+	if err := gob.NewEncoder(&buf).Encode(file); err != nil {
+		return err
+	}
+	if err := writeFileAtomic(c.path, buf.Bytes()); err != nil {
+		return err
+	}
+	c.dirty = false
+	return nil
+}
+
+// ParseFile returns the cached events for path when its size and modification
+// time still match, and otherwise calls parseFn and caches the result.
+func (c *UsageEventCache) ParseFile(providerName, version, path string, parseFn UsageEventParseFunc) ([]UsageEvent, error) {
+	if c == nil {
+		return parseFn(path)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return parseFn(path)
+	}
+	key := usageEventCacheKey(providerName, path)
+	if entry := c.lookup(key); entry != nil && entry.State == nil &&
+		entry.Version == version && entry.Size == info.Size() && entry.ModTime == info.ModTime().UnixNano() {
+		return append([]UsageEvent(nil), entry.Events...), nil
+	}
+
+	events, err := parseFn(path)
+	if err != nil {
+		return nil, err
+	}
+	c.store(key, &usageEventCacheEntry{
+		SourcePath: path,
+		Version:    version,
+		Size:       info.Size(),
+		ModTime:    info.ModTime().UnixNano(),
+		Events:     append([]UsageEvent(nil), events...),
+	})
+	return events, nil
+}
+
+// ParseJSONLFile parses an append-only JSONL log with a parser from newParser.
+// When the log only grew since it was cached, parsing resumes from the last
+// cached line instead of starting over. Lines that are not valid JSON are
+// reported to diagnostics.
+func (c *UsageEventCache) ParseJSONLFile(providerName, version, path string, newParser func() JSONLUsageEventParser, diagnostics *Diagnostics) ([]UsageEvent, error) {
+	if c == nil {
+		events, malformed, err := parseJSONLUsageEvents(path, newParser())
+		if err != nil {
+			return nil, err
+		}
+		diagnostics.AddMalformedLines(providerName, path, malformed)
+		return events, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	key := usageEventCacheKey(providerName, path)
+	entry := c.lookup(key)
+	unchanged := entry != nil && entry.Size == info.Size() && entry.ModTime == info.ModTime().UnixNano()
+
+	parser := newParser()
+	var offset int64
+	var malformed int
+	resumed := false
+	if entry != nil && entry.Version == version && entry.State != nil && canResumeJSONL(f, info.Size(), entry, unchanged) {
+		cached := newParser()
+		if err := gob.NewDecoder(bytes.NewReader(entry.State)).Decode(cached); err == nil {
+			parser, offset, malformed, resumed = cached, entry.Offset, entry.MalformedLines, true
+		}
+	}
+	if resumed && unchanged {
+		if _, _, err := readJSONLLines(f, offset, parser, nil); err != nil {
+			return nil, err
+		}
+		diagnostics.AddMalformedLines(providerName, path, malformed)
+		return parser.Events(), nil
+	}
+
+	var state []byte
+	next, newMalformed, err := readJSONLLines(f, offset, parser, func() error {
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(parser); err != nil {
+			return err
+		}
+		state = buf.Bytes()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	tail, err := jsonlTailHash(f, next)
+	if err != nil {
+		return nil, err
+	}
+	malformed += newMalformed
+	c.store(key, &usageEventCacheEntry{
+		SourcePath:     path,
+		Version:        version,
+		Size:           info.Size(),
+		ModTime:        info.ModTime().UnixNano(),
+		Offset:         next,
+		Tail:           tail,
+		State:          state,
+		MalformedLines: malformed,
+	})
+	diagnostics.AddMalformedLines(providerName, path, malformed)
+	return parser.Events(), nil
+}
+
+// ParseJSONLUsageEvents feeds every line of path to parser and returns its events.
+func ParseJSONLUsageEvents(path string, parser JSONLUsageEventParser) ([]UsageEvent, error) {
+	events, _, err := parseJSONLUsageEvents(path, parser)
+	return events, err
+}
+
+func parseJSONLUsageEvents(path string, parser JSONLUsageEventParser) ([]UsageEvent, int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+	_, malformed, err := readJSONLLines(f, 0, parser, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	return parser.Events(), malformed, nil
+}
+
+// readJSONLLines feeds lines from offset to parser and returns the offset just
+// past the last complete line, along with how many complete non-empty lines
+// were not valid JSON. checkpoint, when set, runs once at that offset, before
+// a trailing line without a newline is parsed. The trailing line may still be
+// being written, so it is never counted as malformed.
+func readJSONLLines(f *os.File, offset int64, parser JSONLUsageEventParser, checkpoint func() error) (int64, int, error) {
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return 0, 0, err
+	}
+	malformed := 0
+	r := bufio.NewReaderSize(f, 64*1024)
+	for {
+		raw, err := r.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return 0, 0, err
+		}
+		complete := err == nil
+		line := bytes.TrimSuffix(raw, []byte("\n"))
+		if len(line) >= maxJSONLLineSize {
+			return 0, 0, bufio.ErrTooLong
+		}
+		line = bytes.TrimSuffix(line, []byte("\r"))
+
+		if !complete {
+			if checkpoint != nil {
+				if err := checkpoint(); err != nil {
+					return 0, 0, err
+				}
+			}
+			if len(raw) > 0 {
+				parser.ParseLine(line)
+			}
+			return offset, malformed, nil
+		}
+		if len(bytes.TrimSpace(line)) > 0 && !json.Valid(line) {
+			malformed++
+		}
+		parser.ParseLine(line)
+		offset += int64(len(raw))
+	}
+}
+
+func canResumeJSONL(f *os.File, size int64, entry *usageEventCacheEntry, unchanged bool) bool {
+	if unchanged {
+		return true
+	}
+	if size < entry.Size || entry.Offset > size {
+		return false
+	}
+	tail, err := jsonlTailHash(f, entry.Offset)
+	return err == nil && tail == entry.Tail
+}
+
+func jsonlTailHash(f *os.File, offset int64) ([sha256.Size]byte, error) {
+	start := offset - resumeCheckSize
+	if start < 0 {
+		start = 0
+	}
+	buf := make([]byte, offset-start)
+	if _, err := f.ReadAt(buf, start); err != nil && !errors.Is(err, io.EOF) {
+		return [sha256.Size]byte{}, err
+	}
+	return sha256.Sum256(buf), nil
+}
+
+func (c *UsageEventCache) lookup(key string) *usageEventCacheEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.entries[key]
+}
+
+func (c *UsageEventCache) store(key string, entry *usageEventCacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = entry
+	c.dirty = true
+}
+
+func usageEventCacheKey(providerName, path string) string {
+	return providerName + "\x00" + path
+}
+
+func writeFileAtomic(path string, data []byte) (err error) {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
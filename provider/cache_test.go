@@ -0,0 +1,301 @@
+package provider
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// lineParser records every non-empty line as one usage event. parsed counts
+// ParseLine calls and is not part of the cached state.
+type lineParser struct {
+	Lines  []string
+	parsed *int
+}
+
+func (p *lineParser) ParseLine(line []byte) {
+	*p.parsed++
+	if len(line) > 0 {
+		p.Lines = append(p.Lines, string(line))
+	}
+}
+
+func (p *lineParser) Events() []UsageEvent {
+	events := make([]UsageEvent, 0, len(p.Lines))
+	for _, line := range p.Lines {
+		events = append(events, UsageEvent{ProviderName: "test", EventID: line})
+	}
+	return events
+}
+
+func eventIDs(events []UsageEvent) []string {
+	ids := make([]string, 0, len(events))
+	for _, e := range events {
+		ids = append(ids, e.EventID)
+	}
+	return ids
+}
+
+func writeTestFile(t *testing.T, path, content string, modTime time.Time) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func appendTestFile(t *testing.T, path, content string, modTime time.Time) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUsageEventCache_ParseJSONLFileResumesAfterAppend(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "session.jsonl")
+	modTime := time.Date(2026, 4, 16, 9, 0, 0, 0, time.UTC)
+	writeTestFile(t, logPath, "a\nb\npart", modTime)
+
+	cachePath := filepath.Join(dir, "cache", "usage-events.gob")
+	parsed := 0
+	newParser := func() JSONLUsageEventParser { return &lineParser{parsed: &parsed} }
+	parse := func() []string {
+		t.Helper()
+		cache, err := OpenUsageEventCache(cachePath)
+		if err != nil {
+			t.Fatalf("OpenUsageEventCache: %v", err)
+		}
+		events, err := cache.ParseJSONLFile("test", "v1", logPath, newParser, nil)
+		if err != nil {
+			t.Fatalf("ParseJSONLFile: %v", err)
+		}
+		if err := cache.Save(); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+		return eventIDs(events)
+	}
+
+	if got, want := parse(), []string{"a", "b", "part"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("first parse = %v, want %v", got, want)
+	}
+	if parsed != 3 {
+		t.Fatalf("first parse fed %d lines, want 3", parsed)
+	}
+
+	parsed = 0
+	if got, want := parse(), []string{"a", "b", "part"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("cached parse = %v, want %v", got, want)
+	}
+	if parsed != 1 {
+		t.Fatalf("cached parse fed %d lines, want only the trailing partial line", parsed)
+	}
+
+	parsed = 0
+	appendTestFile(t, logPath, "ial\nc\n", modTime.Add(time.Minute))
+	if got, want := parse(), []string{"a", "b", "partial", "c"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("resumed parse = %v, want %v", got, want)
+	}
+	if parsed != 2 {
+		t.Fatalf("resumed parse fed %d lines, want 2 appended lines", parsed)
+	}
+}
+
+func TestUsageEventCache_ParseJSONLFileReparsesRewrittenFile(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "session.jsonl")
+	modTime := time.Date(2026, 4, 16, 9, 0, 0, 0, time.UTC)
+	writeTestFile(t, logPath, "a\nb\n", modTime)
+
+	cache, err := OpenUsageEventCache(filepath.Join(dir, "usage-events.gob"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed := 0
+	newParser := func() JSONLUsageEventParser { return &lineParser{parsed: &parsed} }
+	if _, err := cache.ParseJSONLFile("test", "v1", logPath, newParser, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	writeTestFile(t, logPath, "x\ny\nz\n", modTime.Add(time.Minute))
+	events, err := cache.ParseJSONLFile("test", "v1", logPath, newParser, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := eventIDs(events), []string{"x", "y", "z"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("events = %v, want %v", got, want)
+	}
+
+	parsed = 0
+	events, err = cache.ParseJSONLFile("test", "v2", logPath, newParser, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed != 3 || len(events) != 3 {
+		t.Fatalf("new parser version fed %d lines and returned %d events, want a full reparse", parsed, len(events))
+	}
+}
+
+func TestUsageEventCache_ParseFileReusesUnchangedFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "chat.json")
+	modTime := time.Date(2026, 4, 16, 9, 0, 0, 0, time.UTC)
+	writeTestFile(t, path, "{}", modTime)
+
+	calls := 0
+	parseFn := func(path string) ([]UsageEvent, error) {
+		calls++
+		return []UsageEvent{{ProviderName: "test", SourcePath: path, Timestamp: modTime}}, nil
+	}
+
+	cachePath := filepath.Join(dir, "usage-events.gob")
+	cache, err := OpenUsageEventCache(cachePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.ParseFile("test", "v1", path, parseFn); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := OpenUsageEventCache(cachePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	events, err := reopened.ParseFile("test", "v1", path, parseFn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 || len(events) != 1 || !events[0].Timestamp.Equal(modTime) {
+		t.Fatalf("calls = %d, events = %#v; want cached event without reparsing", calls, events)
+	}
+
+	writeTestFile(t, path, "{\"changed\":true}", modTime.Add(time.Minute))
+	if _, err := reopened.ParseFile("test", "v1", path, parseFn); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Fatalf("calls = %d, want a reparse after the file changed", calls)
+	}
+}
+
+func TestUsageEventCache_SaveDropsDeletedFilesAndClearRemovesCache(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "chat.json")
+	writeTestFile(t, path, "{}", time.Date(2026, 4, 16, 9, 0, 0, 0, time.UTC))
+	cachePath := filepath.Join(dir, "usage-events.gob")
+
+	cache, err := OpenUsageEventCache(cachePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.ParseFile("test", "v1", path, func(string) ([]UsageEvent, error) { return nil, nil }); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.Save(); err != nil {
+		t.Fatal(err)
+	}
+	reopened, err := OpenUsageEventCache(cachePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reopened.entries) != 0 {
+		t.Fatalf("entries = %#v, want deleted source dropped", reopened.entries)
+	}
+
+	if err := ClearUsageEventCache(cachePath); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(cachePath); !os.IsNotExist(err) {
+		t.Fatalf("cache file still exists after clear: %v", err)
+	}
+	if err := ClearUsageEventCache(cachePath); err != nil {
+		t.Fatalf("clearing a missing cache: %v", err)
+	}
+}
+
+func TestUsageEventCache_NilCacheParsesDirectly(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "session.jsonl")
+	writeTestFile(t, logPath, "a\r\n\nb", time.Now())
+
+	var cache *UsageEventCache
+	parsed := 0
+	events, err := cache.ParseJSONLFile("test", "v1", logPath, func() JSONLUsageEventParser { return &lineParser{parsed: &parsed} }, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := eventIDs(events), []string{"a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("events = %v, want %v", got, want)
+	}
+	if parsed != 3 {
+		t.Fatalf("parsed %d lines, want 3 including the empty line", parsed)
+	}
+	if err := cache.Save(); err != nil {
+		t.Fatalf("nil Save: %v", err)
+	}
+}
+
+func TestUsageEventCache_ParseJSONLFileReportsMalformedLines(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "session.jsonl")
+	modTime := time.Date(2026, 4, 16, 9, 0, 0, 0, time.UTC)
+	writeTestFile(t, logPath, "{\"n\":1}\nnot json\n\n{\"n\":", modTime)
+
+	cachePath := filepath.Join(dir, "usage-events.gob")
+	parsed := 0
+	newParser := func() JSONLUsageEventParser { return &lineParser{parsed: &parsed} }
+	malformed := func(cache *UsageEventCache) int {
+		t.Helper()
+		var diagnostics Diagnostics
+		if _, err := cache.ParseJSONLFile("test", "v1", logPath, newParser, &diagnostics); err != nil {
+			t.Fatal(err)
+		}
+		if err := cache.Save(); err != nil {
+			t.Fatal(err)
+		}
+		_, lines := diagnostics.Counts()
+		return lines
+	}
+	open := func() *UsageEventCache {
+		t.Helper()
+		cache, err := OpenUsageEventCache(cachePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cache
+	}
+
+	if got := malformed(open()); got != 1 {
+		t.Fatalf("first parse reported %d malformed lines, want 1 (trailing partial line is not malformed)", got)
+	}
+	if got := malformed(open()); got != 1 {
+		t.Fatalf("cached parse reported %d malformed lines, want 1", got)
+	}
+	appendTestFile(t, logPath, "2}\n{broken\n", modTime.Add(time.Minute))
+	if got := malformed(open()); got != 2 {
+		t.Fatalf("resumed parse reported %d malformed lines, want 2", got)
+	}
+	if got := malformed(nil); got != 2 {
+		t.Fatalf("uncached parse reported %d malformed lines, want 2", got)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/miss-you/codetok/provider"
@@ -32,6 +33,7 @@ type claudeEvent struct {
 	UserType  string    `json:"userType"`
 	SessionID string    `json:"sessionId"`
 	RequestID string    `json:"requestId"`
+	Cwd       string    `json:"cwd"`
 	Timestamp string    `json:"timestamp"`
 	Message   claudeMsg `json:"message"`
 }
@@ -65,7 +67,7 @@ func (p *Provider) CollectSessions(baseDir string) ([]provider.SessionInfo, erro
 	// Phase 2: Parse all sessions in parallel
 	sessions := provider.ParseParallel(paths, 0, func(path string) (provider.SessionInfo, error) {
 		return parseSession(path, pathToSlug[path])
-	})
+	}, nil)
 
 	return sessions, nil
 }
@@ -90,19 +92,56 @@ func (p *Provider) collectUsageEvents(baseDir string, opts provider.UsageEventCo
 	if opts.Metrics != nil {
 		opts.Metrics.ParsedFiles += len(paths)
 	}
-	events := collectUsageEventsWithParser(paths, pathToSlug, 0, parseUsageEvents)
+	slugs := newProjectSlugResolver()
+	events := collectUsageEventsWithParser(paths, pathToSlug, 0, func(path, projectSlug string) ([]provider.UsageEvent, error) {
+		return opts.Cache.ParseJSONLFile(p.Name(), usageEventCacheVersion, path, func() provider.JSONLUsageEventParser {
+			return newUsageEventParser(path, projectSlug, slugs)
+		}, opts.Diagnostics)
+	}, opts.Diagnostics.FileErrorHandler(p.Name()))
 	if opts.Metrics != nil {
 		opts.Metrics.EmittedEvents += len(events)
 	}
 	return events, nil
 }
 
+// JSONLSourceLister lists Claude Code session files under baseDir so they can
+// be followed while they grow. Project slugs are decoded once per lister.
+func (p *Provider) JSONLSourceLister(baseDir string) func() ([]provider.JSONLSource, error) {
+	slugs := newProjectSlugResolver()
+	return func() ([]provider.JSONLSource, error) {
+		paths, pathToSlug, err := collectSessionPaths(baseDir)
+		if err != nil {
+			return nil, err
+		}
+		sources := make([]provider.JSONLSource, 0, len(paths))
+		for _, path := range paths {
+			path, projectSlug := path, pathToSlug[path]
+			sources = append(sources, provider.JSONLSource{
+				Path: path,
+				NewParser: func() provider.JSONLUsageEventParser {
+					return newUsageEventParser(path, projectSlug, slugs)
+				},
+			})
+		}
+		return sources, nil
+	}
+}
+
+// CollectTranscriptUsageEvents parses one session file, such as the
+// transcript_path Claude Code passes to statusline commands.
+func (p *Provider) CollectTranscriptUsageEvents(path string, opts provider.UsageEventCollectOptions) ([]provider.UsageEvent, error) {
+	projectSlug := filepath.Base(filepath.Dir(path))
+	return opts.Cache.ParseJSONLFile(p.Name(), usageEventCacheVersion, path, func() provider.JSONLUsageEventParser {
+		return newUsageEventParser(path, projectSlug, nil)
+	}, opts.Diagnostics)
+}
+
 type claudeUsageEventParser func(path, projectSlug string) ([]provider.UsageEvent, error)
 
-func collectUsageEventsWithParser(paths []string, pathToSlug map[string]string, maxWorkers int, parseFn claudeUsageEventParser) []provider.UsageEvent {
+func collectUsageEventsWithParser(paths []string, pathToSlug map[string]string, maxWorkers int, parseFn claudeUsageEventParser, onError provider.ParseErrorFunc) []provider.UsageEvent {
 	events := provider.ParseUsageEventsParallel(paths, maxWorkers, func(path string) ([]provider.UsageEvent, error) {
 		return parseFn(path, pathToSlug[path])
-	})
+	}, onError)
 	sortUsageEvents(events)
 	return events
 }
@@ -315,6 +354,9 @@ func parseSession(path, projectSlug string) (provider.SessionInfo, error) {
 		if info.SessionID == "" && event.SessionID != "" {
 			info.SessionID = event.SessionID
 		}
+		if info.ProjectPath == "" {
+			info.ProjectPath = strings.TrimSpace(event.Cwd)
+		}
 	}
 
 	if err := scanner.Err(); err != nil {
@@ -336,6 +378,9 @@ func parseSession(path, projectSlug string) (provider.SessionInfo, error) {
 		info.SessionID = strings.TrimSuffix(base, ".jsonl")
 	}
 
+	if info.ProjectPath == "" {
+		info.ProjectPath = decodeProjectSlug(projectSlug)
+	}
 	info.Title = truncateTitle(title, 80)
 	info.Turns = turns
 	info.TokenUsage = usage
@@ -347,106 +392,131 @@ func parseSession(path, projectSlug string) (provider.SessionInfo, error) {
 
 // parseUsageEvents parses timestamped Claude Code usage events from one JSONL session file.
 func parseUsageEvents(path, projectSlug string) ([]provider.UsageEvent, error) {
-	f, err := os.Open(path)
-	if err != nil {
-		return nil, err
+	return provider.ParseJSONLUsageEvents(path, newUsageEventParser(path, projectSlug, nil))
+}
+
+// usageEventCacheVersion must change whenever usageEventParser output changes.
+const usageEventCacheVersion = "claude/2"
+
+// usageEventParser incrementally parses a Claude Code session file. Its
+// exported fields are the resumable state kept by the usage event cache.
+type usageEventParser struct {
+	Path        string
+	ProjectSlug string
+	// Dedup keeps the latest event per messageId:requestId key.
+	Dedup         map[string]provider.UsageEvent
+	UniqueCounter int
+	SessionID     string
+	ModelName     string
+	Title         string
+	ProjectPath   string
+
+	// slugs decodes ProjectSlug when the log records no cwd. It is not part
+	// of the cached state.
+	slugs *projectSlugResolver
+}
+
+func newUsageEventParser(path, projectSlug string, slugs *projectSlugResolver) *usageEventParser {
+	return &usageEventParser{
+		Path:        path,
+		ProjectSlug: projectSlug,
+		Dedup:       make(map[string]provider.UsageEvent),
+		slugs:       slugs,
 	}
-	defer f.Close()
+}
 
-	type usageEventEntry struct {
-		event provider.UsageEvent
+// ParseLine consumes one JSONL line.
+func (p *usageEventParser) ParseLine(line []byte) {
+	if len(line) == 0 {
+		return
 	}
 
-	dedupUsage := make(map[string]usageEventEntry)
-	var uniqueCounter int
-	var sessionID string
-	var modelName string
-	var title string
+	var event claudeEvent
+	if err := json.Unmarshal(line, &event); err != nil {
+		// Skip malformed lines
+		return
+	}
 
-	scanner := bufio.NewScanner(f)
-	// Increase buffer size for long lines
-	scanner.Buffer(make([]byte, 0, 1024*1024), 1024*1024)
+	if p.SessionID == "" && event.SessionID != "" {
+		p.SessionID = event.SessionID
+	}
+	if p.ProjectPath == "" {
+		p.ProjectPath = strings.TrimSpace(event.Cwd)
+	}
 
-	for scanner.Scan() {
-		line := scanner.Bytes()
-		if len(line) == 0 {
-			continue
+	switch event.Type {
+	case "user":
+		if event.UserType != "" && event.UserType != "external" {
+			return
 		}
-
-		var event claudeEvent
-		if err := json.Unmarshal(line, &event); err != nil {
-			// Skip malformed lines
-			continue
+		if p.Title == "" {
+			p.Title = extractUserText(event.Message.Content)
 		}
 
-		if sessionID == "" && event.SessionID != "" {
-			sessionID = event.SessionID
+	case "assistant":
+		model := strings.TrimSpace(event.Message.Model)
+		if p.ModelName == "" && model != "" {
+			p.ModelName = model
+		}
+		if event.Message.Usage == nil {
+			return
 		}
 
-		switch event.Type {
-		case "user":
-			if event.UserType != "" && event.UserType != "external" {
-				continue
-			}
-			if title == "" {
-				title = extractUserText(event.Message.Content)
-			}
-
-		case "assistant":
-			model := strings.TrimSpace(event.Message.Model)
-			if modelName == "" && model != "" {
-				modelName = model
-			}
-			if event.Message.Usage == nil {
-				continue
-			}
-
-			ts, err := time.Parse(time.RFC3339Nano, event.Timestamp)
-			if err != nil || ts.IsZero() {
-				continue
-			}
-
-			key := dedupKey(event.Message.ID, event.RequestID, &uniqueCounter)
-			dedupUsage[key] = usageEventEntry{
-				event: provider.UsageEvent{
-					ProviderName: "claude",
-					ModelName:    model,
-					SessionID:    event.SessionID,
-					WorkDirHash:  projectSlug,
-					Timestamp:    ts,
-					TokenUsage:   tokenUsageFromClaudeUsage(event.Message.Usage),
-					SourcePath:   path,
-					EventID:      key,
-				},
-			}
+		ts, err := time.Parse(time.RFC3339Nano, event.Timestamp)
+		if err != nil || ts.IsZero() {
+			return
 		}
-	}
 
-	if err := scanner.Err(); err != nil {
-		return nil, err
+		key := dedupKey(event.Message.ID, event.RequestID, &p.UniqueCounter)
+		eventID := key
+		if event.Message.ID == "" && event.RequestID == "" {
+			// Fallback keys only count within this transcript.
+			eventID = p.Path + "#" + key
+		}
+		if p.Dedup == nil {
+			p.Dedup = make(map[string]provider.UsageEvent)
+		}
+		p.Dedup[key] = provider.UsageEvent{
+			ProviderName: "claude",
+			ModelName:    model,
+			SessionID:    event.SessionID,
+			WorkDirHash:  p.ProjectSlug,
+			Timestamp:    ts,
+			TokenUsage:   tokenUsageFromClaudeUsage(event.Message.Usage),
+			SourcePath:   p.Path,
+			EventID:      eventID,
+		}
 	}
+}
 
+// Events returns the deduplicated usage events parsed so far.
+func (p *usageEventParser) Events() []provider.UsageEvent {
+	sessionID := p.SessionID
 	if sessionID == "" {
-		base := filepath.Base(path)
+		base := filepath.Base(p.Path)
 		sessionID = strings.TrimSuffix(base, ".jsonl")
 	}
-	title = truncateTitle(title, 80)
+	title := truncateTitle(p.Title, 80)
+	projectPath := p.ProjectPath
+	if projectPath == "" {
+		projectPath = p.slugs.resolve(p.ProjectSlug)
+	}
 
-	events := make([]provider.UsageEvent, 0, len(dedupUsage))
-	for _, entry := range dedupUsage {
-		event := entry.event
+	events := make([]provider.UsageEvent, 0, len(p.Dedup))
+	for _, event := range p.Dedup {
 		if event.SessionID == "" {
 			event.SessionID = sessionID
 		}
 		if event.ModelName == "" {
-			event.ModelName = modelName
+			event.ModelName = p.ModelName
 		}
 		event.Title = title
+		event.ProjectPath = projectPath
 		events = append(events, event)
 	}
 
 	sortUsageEvents(events)
-	return events, nil
+	return events
 }
 
 func sortUsageEvents(events []provider.UsageEvent) {
@@ -520,3 +590,84 @@ func truncateTitle(s string, max int) string {
 	}
 	return string(runes[:max-3]) + "..."
 }
+
+// decodeProjectSlug recovers the working directory from a Claude Code project
+// directory name. Claude Code replaces every non-alphanumeric path character
+// with "-", so the slug alone is ambiguous: each step prefers the longest
+// existing directory entry whose own slug matches, and falls back to treating
+// the remaining dashes as path separators.
+func decodeProjectSlug(slug string) string {
+	if !strings.HasPrefix(slug, "-") {
+		return ""
+	}
+	parts := strings.Split(slug[1:], "-")
+	dir := string(filepath.Separator)
+	for i := 0; i < len(parts); {
+		name, next := matchSlugEntry(dir, parts, i)
+		if next == i {
+			return filepath.Join(dir, strings.Join(parts[i:], string(filepath.Separator)))
+		}
+		dir = filepath.Join(dir, name)
+		i = next
+	}
+	return dir
+}
+
+// matchSlugEntry finds the entry in dir matching the longest run of slug parts
+// starting at i. It returns i as next when nothing matches.
+func matchSlugEntry(dir string, parts []string, i int) (string, int) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", i
+	}
+	bestName, bestNext := "", i
+	for _, entry := range entries {
+		entrySlug := projectSlugPart(entry.Name())
+		for j := len(parts); j > bestNext; j-- {
+			if entrySlug == strings.Join(parts[i:j], "-") {
+				bestName, bestNext = entry.Name(), j
+				break
+			}
+		}
+	}
+	return bestName, bestNext
+}
+
+// projectSlugResolver memoizes decodeProjectSlug, which reads a directory at
+// every path step, so each slug is decoded once per collection rather than on
+// every Events call.
+type projectSlugResolver struct {
+	mu    sync.Mutex
+	paths map[string]string
+}
+
+func newProjectSlugResolver() *projectSlugResolver {
+	return &projectSlugResolver{paths: make(map[string]string)}
+}
+
+// resolve returns decodeProjectSlug(slug). A nil resolver decodes every time.
+func (r *projectSlugResolver) resolve(slug string) string {
+	if r == nil {
+		return decodeProjectSlug(slug)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	path, ok := r.paths[slug]
+	if !ok {
+		path = decodeProjectSlug(slug)
+		r.paths[slug] = path
+	}
+	return path
+}
+
+func projectSlugPart(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('-')
+		}
+	}
+	return b.String()
+}
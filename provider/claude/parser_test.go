@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"sync/atomic"
 	"testing"
@@ -257,6 +258,71 @@ func TestParseClaudeUsageEvents_UsesAssistantTimestampAndMetadata(t *testing.T)
 	}
 }
 
+func TestParseClaudeUsageEvents_ProjectPathPrefersRecordedCwd(t *testing.T) {
+	events, err := parseUsageEvents(filepath.Join("testdata", "project-a", "session-1.jsonl"), "-Users-apple-projects-other")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) == 0 {
+		t.Fatal("expected usage events")
+	}
+	for _, event := range events {
+		if event.ProjectPath != "/Users/apple/projects/myapp" {
+			t.Fatalf("ProjectPath = %q, want cwd from the session log", event.ProjectPath)
+		}
+	}
+}
+
+func TestDecodeProjectSlug_ResolvesDashesAgainstExistingDirectories(t *testing.T) {
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	project := filepath.Join(root, "my-app.v2", "sub_dir")
+	if err := os.MkdirAll(project, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := decodeProjectSlug(projectSlugPart(project)); got != project {
+		t.Fatalf("decodeProjectSlug = %q, want %q", got, project)
+	}
+
+	missing := projectSlugPart(filepath.Join(root, "gone")) + "-repo"
+	if got, want := decodeProjectSlug(missing), filepath.Join(root, "gone", "repo"); got != want {
+		t.Fatalf("decodeProjectSlug(missing) = %q, want %q", got, want)
+	}
+	if got := decodeProjectSlug("project-a"); got != "" {
+		t.Fatalf("decodeProjectSlug(non-path slug) = %q, want empty", got)
+	}
+}
+
+func TestProjectSlugResolver_DecodesEachSlugOnce(t *testing.T) {
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	project := filepath.Join(root, "my-app")
+	if err := os.MkdirAll(project, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	slugs := newProjectSlugResolver()
+	slug := projectSlugPart(project)
+	if got := slugs.resolve(slug); got != project {
+		t.Fatalf("resolve = %q, want %q", got, project)
+	}
+	// A second lookup must not read the filesystem again.
+	if err := os.Remove(project); err != nil {
+		t.Fatal(err)
+	}
+	if got := slugs.resolve(slug); got != project {
+		t.Fatalf("memoized resolve = %q, want %q", got, project)
+	}
+	if got := decodeProjectSlug(slug); got != filepath.Join(root, "my", "app") {
+		t.Fatalf("decodeProjectSlug after removal = %q, want the dash fallback", got)
+	}
+}
+
 func TestParseClaudeUsageEvents_CrossDayAssistantMessages(t *testing.T) {
 	dir := t.TempDir()
 	sessionPath := filepath.Join(dir, "cross-day.jsonl")
@@ -334,6 +400,65 @@ func TestParseClaudeUsageEvents_DedupStreamingKeepsLatestUsage(t *testing.T) {
 	}
 }
 
+func TestCollectClaudeUsageEvents_CacheResumesAppendedSession(t *testing.T) {
+	dir := t.TempDir()
+	projectDir := filepath.Join(dir, "project-x")
+	if err := os.MkdirAll(projectDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	sessionPath := filepath.Join(projectDir, "s1.jsonl")
+	first := `{"type":"user","userType":"external","sessionId":"s1","cwd":"/work/x","timestamp":"2026-04-16T09:59:00Z","message":{"role":"user","content":"Stream"}}
+{"type":"assistant","requestId":"req-A","sessionId":"s1","timestamp":"2026-04-16T10:00:00Z","message":{"id":"msg-A","model":"claude-sonnet-4-6","usage":{"input_tokens":50,"output_tokens":5}}}
+`
+	appended := `{"type":"assistant","requestId":"req-A","sessionId":"s1","timestamp":"2026-04-16T10:00:02Z","message":{"id":"msg-A","model":"claude-sonnet-4-6","usage":{"input_tokens":50,"output_tokens":30}}}
+{"type":"assistant","requestId":"req-B","sessionId":"s1","timestamp":"2026-04-16T10:02:00Z","message":{"id":"msg-B","model":"claude-haiku-4-5","usage":{"input_tokens":200,"output_tokens":40}}}
+`
+	if err := os.WriteFile(sessionPath, []byte(first), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cachePath := filepath.Join(dir, "cache", "usage-events.gob")
+	collect := func() []provider.UsageEvent {
+		t.Helper()
+		cache, err := provider.OpenUsageEventCache(cachePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		events, err := (&Provider{}).CollectUsageEventsInRange(dir, provider.UsageEventCollectOptions{Cache: cache})
+		if err != nil {
+			t.Fatalf("CollectUsageEventsInRange: %v", err)
+		}
+		if err := cache.Save(); err != nil {
+			t.Fatal(err)
+		}
+		return events
+	}
+
+	if events := collect(); len(events) != 1 || events[0].TokenUsage.Output != 5 {
+		t.Fatalf("first collect = %#v, want one streaming event", events)
+	}
+	f, err := os.OpenFile(sessionPath, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString(appended); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	got := collect()
+	want, err := parseUsageEvents(sessionPath, "project-x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("cached events = %#v\nwant uncached %#v", got, want)
+	}
+	if len(got) != 2 || got[0].TokenUsage.Output != 30 || got[0].Title != "Stream" || got[0].ProjectPath != "/work/x" {
+		t.Fatalf("resumed events = %#v, want latest msg-A usage with session metadata", got)
+	}
+}
+
 func TestParseClaudeUsageEvents_DedupUsesLatestFileRecord(t *testing.T) {
 	dir := t.TempDir()
 	sessionPath := filepath.Join(dir, "dedup-file-order.jsonl")
@@ -390,6 +515,9 @@ func TestParseClaudeUsageEvents_NoIDsRemainUnique(t *testing.T) {
 	if events[0].EventID == "" || events[1].EventID == "" || events[0].EventID == events[1].EventID {
 		t.Fatalf("events should have distinct non-empty fallback IDs: %#v", events)
 	}
+	if !strings.HasPrefix(events[0].EventID, sessionPath+"#") {
+		t.Errorf("fallback EventID = %q, want it scoped to %s", events[0].EventID, sessionPath)
+	}
 	if events[0].TokenUsage.Total() != 15 {
 		t.Errorf("first total = %d, want 15", events[0].TokenUsage.Total())
 	}
@@ -552,7 +680,7 @@ func TestCollectClaudeUsageEventsWithParser_ParsesInParallelAndSorts(t *testing.
 
 	resultCh := make(chan []provider.UsageEvent, 1)
 	go func() {
-		resultCh <- collectUsageEventsWithParser(paths, pathToSlug, 2, parser)
+		resultCh <- collectUsageEventsWithParser(paths, pathToSlug, 2, parser, nil)
 	}()
 
 	startedPaths := make(map[string]bool)
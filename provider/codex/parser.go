@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -51,6 +52,29 @@ type eventMsgPayload struct {
 	Info    json.RawMessage `json:"info"`
 	Context json.RawMessage `json:"context"`
 	Payload json.RawMessage `json:"payload"`
+	// RateLimits is set on token_count events. It stays raw so an unexpected
+	// shape cannot break usage parsing.
+	RateLimits json.RawMessage `json:"rate_limits"`
+}
+
+// codexRateLimits holds the rate_limits field of a token_count event.
+type codexRateLimits struct {
+	Primary   *codexRateLimitWindow `json:"primary"`
+	Secondary *codexRateLimitWindow `json:"secondary"`
+	// Early Codex releases logged flat fields instead of window objects.
+	PrimaryUsedPercent     *float64 `json:"primary_used_percent"`
+	SecondaryUsedPercent   *float64 `json:"secondary_used_percent"`
+	PrimaryWindowMinutes   int      `json:"primary_window_minutes"`
+	SecondaryWindowMinutes int      `json:"secondary_window_minutes"`
+}
+
+// codexRateLimitWindow is one window of rate_limits. Newer releases log the
+// reset as a Unix time, older ones as seconds from the event.
+type codexRateLimitWindow struct {
+	UsedPercent     float64 `json:"used_percent"`
+	WindowMinutes   int     `json:"window_minutes"`
+	ResetsAt        int64   `json:"resets_at"`
+	ResetsInSeconds *int64  `json:"resets_in_seconds"`
 }
 
 type codexDirectModelFields struct {
@@ -91,17 +115,19 @@ type codexTokenUsage struct {
 	TotalTokens           int `json:"total_tokens"`
 }
 
+// codexUsageState fields are exported so the usage event cache can persist them.
 type codexUsageState struct {
-	previousTotal  *codexTokenUsage
-	pendingLast    codexTokenUsage
-	hasPendingLast bool
+	PreviousTotal  *codexTokenUsage
+	PendingLast    codexTokenUsage
+	HasPendingLast bool
 }
 
 func (u codexTokenUsage) toProviderTokenUsage() provider.TokenUsage {
 	return provider.TokenUsage{
-		InputOther:     u.InputTokens - u.CachedInputTokens,
-		InputCacheRead: u.CachedInputTokens,
-		Output:         u.OutputTokens,
+		InputOther:      u.InputTokens - u.CachedInputTokens,
+		InputCacheRead:  u.CachedInputTokens,
+		Output:          u.OutputTokens,
+		OutputReasoning: u.ReasoningOutputTokens,
 	}
 }
 
@@ -116,7 +142,7 @@ func (p *Provider) CollectSessions(baseDir string) ([]provider.SessionInfo, erro
 	// Parse all sessions in parallel.
 	sessions := provider.ParseParallel(paths, 0, func(path string) (provider.SessionInfo, error) {
 		return parseCodexSession(path)
-	})
+	}, nil)
 
 	return sessions, nil
 }
@@ -139,13 +165,63 @@ func (p *Provider) collectUsageEvents(baseDir string, opts provider.UsageEventCo
 	if opts.Metrics != nil {
 		opts.Metrics.ParsedFiles += len(paths)
 	}
-	events := provider.ParseUsageEventsParallel(paths, 0, parseCodexUsageEvents)
+	events := provider.ParseUsageEventsParallel(paths, 0, func(path string) ([]provider.UsageEvent, error) {
+		return opts.Cache.ParseJSONLFile(p.Name(), codexUsageEventCacheVersion, path, func() provider.JSONLUsageEventParser {
+			return newCodexUsageEventParser(path)
+		}, opts.Diagnostics)
+	}, opts.Diagnostics.FileErrorHandler(p.Name()))
 	if opts.Metrics != nil {
 		opts.Metrics.EmittedEvents += len(events)
 	}
 	return events, nil
 }
 
+// JSONLSourceLister lists Codex rollout files under baseDir so they can be
+// followed while they grow.
+func (p *Provider) JSONLSourceLister(baseDir string) func() ([]provider.JSONLSource, error) {
+	return func() ([]provider.JSONLSource, error) {
+		paths, err := collectCodexSessionPaths(baseDir)
+		if err != nil {
+			return nil, err
+		}
+		sources := make([]provider.JSONLSource, 0, len(paths))
+		for _, path := range paths {
+			path := path
+			sources = append(sources, provider.JSONLSource{
+				Path: path,
+				NewParser: func() provider.JSONLUsageEventParser {
+					return newCodexUsageEventParser(path)
+				},
+			})
+		}
+		return sources, nil
+	}
+}
+
+// CollectRateLimits returns the rate-limit snapshots logged with token_count
+// events in the rollout files under baseDir, sorted by time.
+func (p *Provider) CollectRateLimits(baseDir string, opts provider.UsageEventCollectOptions) ([]provider.RateLimitSnapshot, error) {
+	paths, err := collectCodexSessionPaths(baseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	paths = filterCodexUsageEventPaths(paths, opts)
+	perFile := provider.ParseParallel(paths, 0, parseCodexRateLimits, opts.Diagnostics.FileErrorHandler(p.Name()))
+	var snapshots []provider.RateLimitSnapshot
+	for _, fileSnapshots := range perFile {
+		for _, snapshot := range fileSnapshots {
+			if opts.ContainsTimestamp(snapshot.Timestamp) {
+				snapshots = append(snapshots, snapshot)
+			}
+		}
+	}
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return snapshots[i].Timestamp.Before(snapshots[j].Timestamp)
+	})
+	return snapshots, nil
+}
+
 func filterCodexUsageEventPaths(paths []string, opts provider.UsageEventCollectOptions) []string {
 	if !opts.HasRange() {
 		if opts.Metrics != nil {
@@ -334,6 +410,9 @@ func parseCodexSession(path string) (provider.SessionInfo, error) {
 			if info.SessionID == "" {
 				info.SessionID = meta.ID
 			}
+			if info.ProjectPath == "" {
+				info.ProjectPath = strings.TrimSpace(meta.Cwd)
+			}
 			if meta.Timestamp != "" && startTime.IsZero() {
 				ts, err := time.Parse(time.RFC3339Nano, meta.Timestamp)
 				if err == nil {
@@ -410,123 +489,223 @@ func parseCodexSession(path string) (provider.SessionInfo, error) {
 }
 
 func parseCodexUsageEvents(path string) ([]provider.UsageEvent, error) {
+	return provider.ParseJSONLUsageEvents(path, newCodexUsageEventParser(path))
+}
+
+// codexUsageEventCacheVersion must change whenever codexUsageEventParser output changes.
+const codexUsageEventCacheVersion = "codex/1"
+
+// codexUsageEventParser incrementally parses a Codex rollout file. Its
+// exported fields are the resumable state kept by the usage event cache.
+type codexUsageEventParser struct {
+	Path         string
+	EventList    []provider.UsageEvent
+	SessionID    string
+	ProjectPath  string
+	Title        string
+	CurrentModel string
+	UsageState   codexUsageState
+	LineNumber   int
+}
+
+func newCodexUsageEventParser(path string) *codexUsageEventParser {
+	return &codexUsageEventParser{Path: path}
+}
+
+// Events returns the usage events parsed so far.
+func (p *codexUsageEventParser) Events() []provider.UsageEvent {
+	return append([]provider.UsageEvent(nil), p.EventList...)
+}
+
+// ParseLine consumes one JSONL line.
+func (p *codexUsageEventParser) ParseLine(line []byte) {
+	p.LineNumber++
+	if len(line) == 0 {
+		return
+	}
+
+	var event codexEvent
+	if err := json.Unmarshal(line, &event); err != nil {
+		return
+	}
+
+	switch event.Type {
+	case "session_meta":
+		var meta sessionMetaPayload
+		if err := json.Unmarshal(event.Payload, &meta); err != nil {
+			return
+		}
+		if p.SessionID == "" && strings.TrimSpace(meta.ID) != "" {
+			p.SessionID = strings.TrimSpace(meta.ID)
+			for i := range p.EventList {
+				p.EventList[i].SessionID = p.SessionID
+			}
+		}
+		if p.ProjectPath == "" && strings.TrimSpace(meta.Cwd) != "" {
+			p.ProjectPath = strings.TrimSpace(meta.Cwd)
+			for i := range p.EventList {
+				p.EventList[i].ProjectPath = p.ProjectPath
+			}
+		}
+		if model := meta.firstModel(); model != "" {
+			p.CurrentModel = model
+		}
+
+	case "event_msg":
+		var msg eventMsgPayload
+		if err := json.Unmarshal(event.Payload, &msg); err != nil {
+			return
+		}
+
+		switch msg.Type {
+		case "user_message":
+			if model := msg.firstModel(extractModelFromRawJSON(msg.Info)); model != "" {
+				p.CurrentModel = model
+			}
+			if p.Title == "" && strings.TrimSpace(msg.Message) != "" {
+				p.Title = strings.TrimSpace(msg.Message)
+				for i := range p.EventList {
+					if p.EventList[i].Title == "" {
+						p.EventList[i].Title = p.Title
+					}
+				}
+			}
+
+		case "token_count":
+			if len(msg.Info) == 0 || bytes.Equal(bytes.TrimSpace(msg.Info), []byte("null")) {
+				if model := msg.firstModel(""); model != "" {
+					p.CurrentModel = model
+				}
+				return
+			}
+			var tci tokenCountInfo
+			if err := json.Unmarshal(msg.Info, &tci); err != nil {
+				if model := msg.firstModel(extractModelFromRawJSON(msg.Info)); model != "" {
+					p.CurrentModel = model
+				}
+				return
+			}
+			if model := msg.firstModel(tci.firstModel()); model != "" {
+				p.CurrentModel = model
+			}
+			ts, err := time.Parse(time.RFC3339Nano, event.Timestamp)
+			if err != nil {
+				return
+			}
+			usage, ok := codexUsageDelta(tci, &p.UsageState)
+			if !ok || usage.Total() == 0 {
+				return
+			}
+			p.EventList = append(p.EventList, provider.UsageEvent{
+				ProviderName: "codex",
+				ModelName:    p.CurrentModel,
+				SessionID:    p.SessionID,
+				Title:        p.Title,
+				ProjectPath:  p.ProjectPath,
+				Timestamp:    ts,
+				TokenUsage:   usage,
+				SourcePath:   p.Path,
+				EventID:      fmt.Sprintf("%s:%d", p.Path, p.LineNumber),
+			})
+
+		default:
+			if model := msg.firstModel(extractModelFromRawJSON(msg.Info)); model != "" {
+				p.CurrentModel = model
+			}
+		}
+
+	default:
+		if model := extractModelFromRawJSON(event.Payload); model != "" {
+			p.CurrentModel = model
+		}
+	}
+}
+
+// parseCodexRateLimits reads the rate-limit snapshots of one rollout file.
+func parseCodexRateLimits(path string) ([]provider.RateLimitSnapshot, error) {
 	f, err := os.Open(path)
 	if err != nil {
 		return nil, err
 	}
 	defer f.Close()
 
-	var events []provider.UsageEvent
-	var sessionID string
-	var title string
-	var currentModel string
-	var usageState codexUsageState
-	var lineNumber int
-
 	scanner := bufio.NewScanner(f)
 	scanner.Buffer(make([]byte, 0, 1024*1024), 1024*1024)
 
+	var sessionID string
+	var snapshots []provider.RateLimitSnapshot
 	for scanner.Scan() {
-		lineNumber++
-		line := scanner.Bytes()
-		if len(line) == 0 {
-			continue
-		}
-
 		var event codexEvent
-		if err := json.Unmarshal(line, &event); err != nil {
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
 			continue
 		}
-
 		switch event.Type {
 		case "session_meta":
 			var meta sessionMetaPayload
-			if err := json.Unmarshal(event.Payload, &meta); err != nil {
-				continue
-			}
-			if sessionID == "" && strings.TrimSpace(meta.ID) != "" {
+			if err := json.Unmarshal(event.Payload, &meta); err == nil && sessionID == "" {
 				sessionID = strings.TrimSpace(meta.ID)
-				for i := range events {
-					events[i].SessionID = sessionID
-				}
 			}
-			if model := meta.firstModel(); model != "" {
-				currentModel = model
-			}
-
 		case "event_msg":
 			var msg eventMsgPayload
-			if err := json.Unmarshal(event.Payload, &msg); err != nil {
+			if err := json.Unmarshal(event.Payload, &msg); err != nil || msg.Type != "token_count" {
 				continue
 			}
-
-			switch msg.Type {
-			case "user_message":
-				if model := msg.firstModel(extractModelFromRawJSON(msg.Info)); model != "" {
-					currentModel = model
-				}
-				if title == "" && strings.TrimSpace(msg.Message) != "" {
-					title = strings.TrimSpace(msg.Message)
-					for i := range events {
-						if events[i].Title == "" {
-							events[i].Title = title
-						}
-					}
-				}
-
-			case "token_count":
-				if len(msg.Info) == 0 || bytes.Equal(bytes.TrimSpace(msg.Info), []byte("null")) {
-					if model := msg.firstModel(""); model != "" {
-						currentModel = model
-					}
-					continue
-				}
-				var tci tokenCountInfo
-				if err := json.Unmarshal(msg.Info, &tci); err != nil {
-					if model := msg.firstModel(extractModelFromRawJSON(msg.Info)); model != "" {
-						currentModel = model
-					}
-					continue
-				}
-				if model := msg.firstModel(tci.firstModel()); model != "" {
-					currentModel = model
-				}
-				ts, err := time.Parse(time.RFC3339Nano, event.Timestamp)
-				if err != nil {
-					continue
-				}
-				usage, ok := codexUsageDelta(tci, &usageState)
-				if !ok || usage.Total() == 0 {
-					continue
-				}
-				events = append(events, provider.UsageEvent{
-					ProviderName: "codex",
-					ModelName:    currentModel,
-					SessionID:    sessionID,
-					Title:        title,
-					Timestamp:    ts,
-					TokenUsage:   usage,
-					SourcePath:   path,
-					EventID:      fmt.Sprintf("%s:%d", path, lineNumber),
-				})
-
-			default:
-				if model := msg.firstModel(extractModelFromRawJSON(msg.Info)); model != "" {
-					currentModel = model
-				}
+			var limits codexRateLimits
+			if err := json.Unmarshal(msg.RateLimits, &limits); err != nil {
+				continue
 			}
-
-		default:
-			if model := extractModelFromRawJSON(event.Payload); model != "" {
-				currentModel = model
+			ts, err := time.Parse(time.RFC3339Nano, event.Timestamp)
+			if err != nil {
+				continue
+			}
+			primary, secondary := limits.windows(ts)
+			if primary == nil && secondary == nil {
+				continue
 			}
+			snapshots = append(snapshots, provider.RateLimitSnapshot{
+				ProviderName: "codex",
+				Timestamp:    ts,
+				Primary:      primary,
+				Secondary:    secondary,
+			})
 		}
 	}
-
 	if err := scanner.Err(); err != nil {
 		return nil, err
 	}
-	return events, nil
+	for i := range snapshots {
+		snapshots[i].SessionID = sessionID
+	}
+	return snapshots, nil
+}
+
+// windows converts both rate-limit windows, resolving relative reset times
+// against the event time.
+func (r codexRateLimits) windows(ts time.Time) (*provider.RateLimitWindow, *provider.RateLimitWindow) {
+	primary, secondary := r.Primary.toProvider(ts), r.Secondary.toProvider(ts)
+	if primary == nil && r.PrimaryUsedPercent != nil {
+		primary = &provider.RateLimitWindow{UsedPercent: *r.PrimaryUsedPercent, WindowMinutes: r.PrimaryWindowMinutes}
+	}
+	if secondary == nil && r.SecondaryUsedPercent != nil {
+		secondary = &provider.RateLimitWindow{UsedPercent: *r.SecondaryUsedPercent, WindowMinutes: r.SecondaryWindowMinutes}
+	}
+	return primary, secondary
+}
+
+func (w *codexRateLimitWindow) toProvider(ts time.Time) *provider.RateLimitWindow {
+	if w == nil {
+		return nil
+	}
+	window := &provider.RateLimitWindow{UsedPercent: w.UsedPercent, WindowMinutes: w.WindowMinutes}
+	switch {
+	case w.ResetsAt > 0:
+		resetsAt := time.Unix(w.ResetsAt, 0).UTC()
+		window.ResetsAt = &resetsAt
+	case w.ResetsInSeconds != nil:
+		resetsAt := ts.Add(time.Duration(*w.ResetsInSeconds) * time.Second)
+		window.ResetsAt = &resetsAt
+	}
+	return window
 }
 
 func codexUsageDelta(info tokenCountInfo, state *codexUsageState) (provider.TokenUsage, bool) {
@@ -534,7 +713,7 @@ func codexUsageDelta(info tokenCountInfo, state *codexUsageState) (provider.Toke
 		last := *info.LastTokenUsage
 		if info.TotalTokenUsage != nil {
 			total := *info.TotalTokenUsage
-			state.previousTotal = &total
+			state.PreviousTotal = &total
 			state.clearPendingLast()
 		} else {
 			state.addPendingLast(last)
@@ -547,25 +726,25 @@ func codexUsageDelta(info tokenCountInfo, state *codexUsageState) (provider.Toke
 
 	total := *info.TotalTokenUsage
 	delta := total
-	if state.previousTotal != nil && !codexTotalDecreased(total, *state.previousTotal) {
-		delta = subtractCodexRawTokenUsage(total, *state.previousTotal)
+	if state.PreviousTotal != nil && !codexTotalDecreased(total, *state.PreviousTotal) {
+		delta = subtractCodexRawTokenUsage(total, *state.PreviousTotal)
 	}
-	if state.hasPendingLast && canSubtractCodexRawTokenUsage(delta, state.pendingLast) {
-		delta = subtractCodexRawTokenUsage(delta, state.pendingLast)
+	if state.HasPendingLast && canSubtractCodexRawTokenUsage(delta, state.PendingLast) {
+		delta = subtractCodexRawTokenUsage(delta, state.PendingLast)
 	}
-	state.previousTotal = &total
+	state.PreviousTotal = &total
 	state.clearPendingLast()
 	return delta.toProviderTokenUsage(), true
 }
 
 func (s *codexUsageState) addPendingLast(usage codexTokenUsage) {
-	s.pendingLast = addCodexRawTokenUsage(s.pendingLast, usage)
-	s.hasPendingLast = true
+	s.PendingLast = addCodexRawTokenUsage(s.PendingLast, usage)
+	s.HasPendingLast = true
 }
 
 func (s *codexUsageState) clearPendingLast() {
-	s.pendingLast = codexTokenUsage{}
-	s.hasPendingLast = false
+	s.PendingLast = codexTokenUsage{}
+	s.HasPendingLast = false
 }
 
 func addCodexRawTokenUsage(dst, src codexTokenUsage) codexTokenUsage {
@@ -606,6 +785,7 @@ func codexTotalDecreased(current, previous codexTokenUsage) bool {
 func addCodexTokenUsage(dst *provider.TokenUsage, src provider.TokenUsage) {
 	dst.InputOther += src.InputOther
 	dst.Output += src.Output
+	dst.OutputReasoning += src.OutputReasoning
 	dst.InputCacheRead += src.InputCacheRead
 	dst.InputCacheCreate += src.InputCacheCreate
 }
@@ -275,6 +275,9 @@ func TestParseCodexSession_MultipleTokenCounts(t *testing.T) {
 	if info.TokenUsage.Output != 800 {
 		t.Errorf("Output = %d, want 800", info.TokenUsage.Output)
 	}
+	if info.TokenUsage.OutputReasoning != 100 {
+		t.Errorf("OutputReasoning = %d, want 100", info.TokenUsage.OutputReasoning)
+	}
 	if info.Turns != 2 {
 		t.Errorf("Turns = %d, want 2", info.Turns)
 	}
@@ -442,6 +445,35 @@ func TestParseCodexUsageEvents_TotalUsageDeltasAcrossDays(t *testing.T) {
 	}
 }
 
+func TestParseCodexUsageEvents_ReasoningOutputDeltas(t *testing.T) {
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "rollout-reasoning.jsonl")
+	content := `{"timestamp":"2026-04-15T10:00:00Z","type":"session_meta","payload":{"id":"reasoning-session","timestamp":"2026-04-15T10:00:00Z","cwd":"/test"}}
+{"timestamp":"2026-04-15T10:01:00Z","type":"event_msg","payload":{"type":"token_count","info":{"total_token_usage":{"input_tokens":1000,"cached_input_tokens":0,"output_tokens":300,"reasoning_output_tokens":120,"total_tokens":1300}}}}
+{"timestamp":"2026-04-15T10:02:00Z","type":"event_msg","payload":{"type":"token_count","info":{"total_token_usage":{"input_tokens":1500,"cached_input_tokens":0,"output_tokens":450,"reasoning_output_tokens":200,"total_tokens":1950}}}}
+`
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := parseCodexUsageEvents(filePath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2: %#v", len(events), events)
+	}
+	if events[0].TokenUsage.OutputReasoning != 120 {
+		t.Errorf("first OutputReasoning = %d, want 120", events[0].TokenUsage.OutputReasoning)
+	}
+	if events[1].TokenUsage.OutputReasoning != 80 {
+		t.Errorf("second OutputReasoning = %d, want 80", events[1].TokenUsage.OutputReasoning)
+	}
+	if events[1].TokenUsage.Total() != 650 {
+		t.Errorf("second Total = %d, want 650 (reasoning is part of output)", events[1].TokenUsage.Total())
+	}
+}
+
 func TestParseCodexUsageEvents_CumulativeResetStartsFreshDelta(t *testing.T) {
 	dir := t.TempDir()
 	filePath := filepath.Join(dir, "rollout-reset.jsonl")
@@ -603,6 +635,9 @@ func TestParseCodexUsageEvents_KeepsFirstSessionMetadata(t *testing.T) {
 		if event.Title != "first title" {
 			t.Errorf("event %d Title = %q, want first title", i, event.Title)
 		}
+		if event.ProjectPath != "/test" {
+			t.Errorf("event %d ProjectPath = %q, want first session cwd", i, event.ProjectPath)
+		}
 	}
 }
 
@@ -1178,6 +1213,54 @@ func TestCollectCodexUsageEventsInRange_KeepsUnparseableDatedPath(t *testing.T)
 	}
 }
 
+func TestCollectCodexRateLimits_ParsesWindowsAndRange(t *testing.T) {
+	baseDir := t.TempDir()
+	dir := filepath.Join(baseDir, "2026", "04", "16")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := `{"timestamp":"2026-04-15T23:00:00Z","type":"session_meta","payload":{"id":"limits","timestamp":"2026-04-15T23:00:00Z","cwd":"/test"}}
+{"timestamp":"2026-04-15T23:30:00Z","type":"event_msg","payload":{"type":"token_count","info":null,"rate_limits":{"primary":{"used_percent":5,"window_minutes":300,"resets_at":1776300000}}}}
+{"timestamp":"2026-04-16T10:00:00Z","type":"event_msg","payload":{"type":"token_count","info":{"total_token_usage":{"input_tokens":100,"cached_input_tokens":0,"output_tokens":10,"total_tokens":110}},"rate_limits":{"primary":{"used_percent":42.5,"window_minutes":300,"resets_in_seconds":3600},"secondary":{"used_percent":12,"window_minutes":10080,"resets_at":1776600000}}}}
+{"timestamp":"2026-04-16T10:05:00Z","type":"event_msg","payload":{"type":"token_count","info":null,"rate_limits":{"primary_used_percent":50,"secondary_used_percent":13,"primary_window_minutes":300,"secondary_window_minutes":10080}}}
+{"timestamp":"2026-04-16T10:06:00Z","type":"event_msg","payload":{"type":"token_count","info":null,"rate_limits":{"primary":{"used_percent":"high"}}}}
+{"timestamp":"2026-04-16T10:07:00Z","type":"event_msg","payload":{"type":"token_count","info":null,"rate_limits":null}}
+`
+	if err := os.WriteFile(filepath.Join(dir, "rollout-limits.jsonl"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshots, err := (&Provider{}).CollectRateLimits(baseDir, provider.UsageEventCollectOptions{
+		Since:    time.Date(2026, 4, 16, 0, 0, 0, 0, time.UTC),
+		Location: time.UTC,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(snapshots) != 2 {
+		t.Fatalf("snapshots = %+v, want the two valid in-range snapshots", snapshots)
+	}
+	first := snapshots[0]
+	if first.ProviderName != "codex" || first.SessionID != "limits" || first.Primary == nil || first.Secondary == nil {
+		t.Fatalf("first snapshot = %+v", first)
+	}
+	if first.Primary.UsedPercent != 42.5 || first.Primary.WindowMinutes != 300 || first.Primary.ResetsAt == nil || !first.Primary.ResetsAt.Equal(time.Date(2026, 4, 16, 11, 0, 0, 0, time.UTC)) {
+		t.Fatalf("primary = %+v, want reset resolved from resets_in_seconds", first.Primary)
+	}
+	if first.Secondary.ResetsAt == nil || !first.Secondary.ResetsAt.Equal(time.Unix(1776600000, 0)) {
+		t.Fatalf("secondary = %+v, want reset from resets_at", first.Secondary)
+	}
+	legacy := snapshots[1]
+	if legacy.Primary == nil || legacy.Primary.UsedPercent != 50 || legacy.Secondary == nil || legacy.Secondary.WindowMinutes != 10080 || legacy.Primary.ResetsAt != nil {
+		t.Fatalf("flat snapshot = %+v, %+v", legacy.Primary, legacy.Secondary)
+	}
+
+	events, err := parseCodexUsageEvents(filepath.Join(dir, "rollout-limits.jsonl"))
+	if err != nil || len(events) != 1 {
+		t.Fatalf("usage events = %+v, %v; want rate_limits to leave usage parsing alone", events, err)
+	}
+}
+
 func writeCodexSessionFile(t *testing.T, baseDir, year, month, day, name, sessionID, title string) string {
 	t.Helper()
 
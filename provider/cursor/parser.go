@@ -2,6 +2,7 @@ package cursor
 
 import (
 	"encoding/csv"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
@@ -20,6 +21,10 @@ func init() {
 // Provider implements provider.Provider for Cursor CSV exports.
 type Provider struct{}
 
+// defaultAccount labels rows outside profiles/<name>/, matching the profile
+// name 'codetok cursor' uses for its unnamed profile.
+const defaultAccount = "default"
+
 const (
 	cursorDateHeader             = "Date"
 	cursorKindHeader             = "Kind"
@@ -45,7 +50,7 @@ func (p *Provider) Name() string {
 }
 
 // CollectSessions scans baseDir for Cursor usage export CSV files and returns one
-// session-like record per CSV row.
+// session-like record per CSV row. Rows already read from another CSV are dropped.
 func (p *Provider) CollectSessions(baseDir string) ([]provider.SessionInfo, error) {
 	paths, err := resolveCursorCSVPaths(baseDir)
 	if err != nil {
@@ -53,12 +58,16 @@ func (p *Provider) CollectSessions(baseDir string) ([]provider.SessionInfo, erro
 	}
 
 	var sessions []provider.SessionInfo
-	for _, path := range paths {
-		parsed, err := parseUsageCSV(path)
+	deduper := newUsageRowDeduper()
+	for _, path := range preferSyncedCSVPaths(paths) {
+		rows, _, err := parseUsageCSVRows(path)
 		if err != nil {
 			continue
 		}
-		sessions = append(sessions, parsed...)
+		parsed, _ := deduper.filter(rows)
+		for _, row := range parsed {
+			sessions = append(sessions, row.session)
+		}
 	}
 
 	sort.Slice(sessions, func(i, j int) bool {
@@ -72,7 +81,7 @@ func (p *Provider) CollectSessions(baseDir string) ([]provider.SessionInfo, erro
 }
 
 // CollectUsageEvents scans Cursor CSV exports and returns one timestamped usage
-// event per valid CSV row.
+// event per valid CSV row. Rows already read from another CSV are dropped.
 func (p *Provider) CollectUsageEvents(baseDir string) ([]provider.UsageEvent, error) {
 	return p.collectUsageEvents(baseDir, provider.UsageEventCollectOptions{})
 }
@@ -88,19 +97,24 @@ func (p *Provider) collectUsageEvents(baseDir string, opts provider.UsageEventCo
 	}
 
 	var events []provider.UsageEvent
-	for _, path := range paths {
+	deduper := newUsageRowDeduper()
+	for _, path := range preferSyncedCSVPaths(paths) {
 		if opts.Metrics != nil {
 			opts.Metrics.ConsideredFiles++
 		}
 		if opts.Metrics != nil {
 			opts.Metrics.ParsedFiles++
 		}
-		parsed, err := parseUsageCSV(path)
+		rows, malformed, err := parseUsageCSVRows(path)
 		if err != nil {
+			opts.Diagnostics.AddFileError(p.Name(), path, err)
 			continue
 		}
-		for _, session := range parsed {
-			event := sessionUsageEvent(path, session)
+		opts.Diagnostics.AddMalformedLines(p.Name(), path, malformed)
+		parsed, duplicates := deduper.filter(rows)
+		opts.Diagnostics.AddDuplicateRows(p.Name(), path, duplicates)
+		for _, row := range parsed {
+			event := rowUsageEvent(path, row)
 			if !opts.ContainsTimestamp(event.Timestamp) {
 				continue
 			}
@@ -121,17 +135,19 @@ func (p *Provider) collectUsageEvents(baseDir string, opts provider.UsageEventCo
 	return events, nil
 }
 
-func sessionUsageEvent(sourcePath string, session provider.SessionInfo) provider.UsageEvent {
+func rowUsageEvent(sourcePath string, row usageRow) provider.UsageEvent {
+	session := row.session
 	return provider.UsageEvent{
 		ProviderName: session.ProviderName,
 		ModelName:    session.ModelName,
 		SessionID:    session.SessionID,
 		Title:        session.Title,
 		WorkDirHash:  session.WorkDirHash,
+		Account:      session.Account,
 		Timestamp:    session.StartTime,
 		TokenUsage:   session.TokenUsage,
 		SourcePath:   sourcePath,
-		EventID:      session.SessionID,
+		EventID:      row.eventID,
 	}
 }
 
@@ -180,6 +196,21 @@ func collectDefaultCursorCSVPaths(root string) ([]string, error) {
 		paths = append(paths, nested...)
 	}
 
+	profiles, err := os.ReadDir(filepath.Join(root, "profiles"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, entry := range profiles {
+		if !entry.IsDir() {
+			continue
+		}
+		nested, err := collectCSVPathsRecursiveIfExists(filepath.Join(root, "profiles", entry.Name(), "synced"))
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, nested...)
+	}
+
 	sort.Strings(paths)
 	return paths, nil
 }
@@ -213,11 +244,101 @@ func collectCSVPathsRecursive(baseDir string) ([]string, error) {
 	return paths, nil
 }
 
+// usageRow is one parsed CSV row. key identifies the same request of one
+// account when it appears in more than one export.
+type usageRow struct {
+	session provider.SessionInfo
+	key     string
+	// eventID follows the row's content and its occurrence among identical
+	// rows, not its line number, so it is stable when a sync inserts newer
+	// rows ahead of it or the row is read from another CSV.
+	eventID string
+}
+
+// usageRowDeduper drops rows that an earlier CSV already contributed, so a
+// manual export in imports/ that overlaps synced/usage.csv is counted once.
+// Identical rows within one file are separate requests, so each key is kept as
+// many times as the most any single file contains it.
+type usageRowDeduper struct {
+	kept map[string]int
+}
+
+func newUsageRowDeduper() *usageRowDeduper {
+	return &usageRowDeduper{kept: make(map[string]int)}
+}
+
+// filter returns the rows of one file that earlier files did not contain, and
+// how many rows were dropped as duplicates.
+func (d *usageRowDeduper) filter(rows []usageRow) ([]usageRow, int) {
+	seen := make(map[string]int, len(rows))
+	kept := make([]usageRow, 0, len(rows))
+	duplicates := 0
+	for _, row := range rows {
+		seen[row.key]++
+		if seen[row.key] <= d.kept[row.key] {
+			duplicates++
+			continue
+		}
+		kept = append(kept, row)
+	}
+	for key, n := range seen {
+		if n > d.kept[key] {
+			d.kept[key] = n
+		}
+	}
+	return kept, duplicates
+}
+
+// preferSyncedCSVPaths moves CSVs written by 'cursor sync' ahead of manual
+// imports and legacy exports, so overlapping rows keep their synced identity.
+func preferSyncedCSVPaths(paths []string) []string {
+	ordered := append([]string(nil), paths...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return isSyncedCSVPath(ordered[i]) && !isSyncedCSVPath(ordered[j])
+	})
+	return ordered
+}
+
+func isSyncedCSVPath(path string) bool {
+	for _, part := range strings.Split(filepath.ToSlash(filepath.Dir(path)), "/") {
+		if part == "synced" {
+			return true
+		}
+	}
+	return false
+}
+
+// cursorAccountForPath returns the profile a CSV belongs to: <name> for files
+// under profiles/<name>/synced/, otherwise the default account.
+func cursorAccountForPath(path string) string {
+	parts := strings.Split(filepath.ToSlash(filepath.Dir(path)), "/")
+	for i := 0; i+2 < len(parts); i++ {
+		if parts[i] == "profiles" && parts[i+1] != "" && parts[i+2] == "synced" {
+			return parts[i+1]
+		}
+	}
+	return defaultAccount
+}
+
 func parseUsageCSV(path string) ([]provider.SessionInfo, error) {
-	f, err := os.Open(path)
+	rows, _, err := parseUsageCSVRows(path)
 	if err != nil {
 		return nil, err
 	}
+	var sessions []provider.SessionInfo
+	for _, row := range rows {
+		sessions = append(sessions, row.session)
+	}
+	return sessions, nil
+}
+
+// parseUsageCSVRows parses a Cursor usage CSV export and also returns how many
+// data rows were skipped because they could not be parsed.
+func parseUsageCSVRows(path string) ([]usageRow, int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, 0, err
+	}
 	defer f.Close()
 
 	reader := csv.NewReader(f)
@@ -225,37 +346,52 @@ func parseUsageCSV(path string) ([]provider.SessionInfo, error) {
 
 	headerRecord, err := reader.Read()
 	if err == io.EOF {
-		return nil, nil
+		return nil, 0, nil
 	}
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	header := headerIndex(headerRecord)
 	for _, name := range cursorRequiredHeaders {
 		if _, ok := header[name]; !ok {
-			return nil, os.ErrInvalid
+			return nil, 0, fmt.Errorf("missing %q column: %w", name, os.ErrInvalid)
 		}
 	}
 
+	account := cursorAccountForPath(path)
 	baseName := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
-	var sessions []provider.SessionInfo
+	if account != defaultAccount {
+		// Every profile syncs to usage.csv; keep their session IDs apart.
+		baseName = account + "/" + baseName
+	}
+	var rows []usageRow
+	occurrences := make(map[string]int)
+	malformed := 0
 	for rowNumber := 1; ; rowNumber++ {
 		record, err := reader.Read()
 		if err == io.EOF {
 			break
 		}
 		if err != nil || !recordHasRequiredFields(header, record) {
+			malformed++
 			continue
 		}
-		session, ok := parseUsageRecord(baseName, rowNumber, header, record)
+		row, ok := parseUsageRecord(baseName, rowNumber, header, record)
 		if !ok {
+			malformed++
 			continue
 		}
-		sessions = append(sessions, session)
+		row.session.Account = account
+		// Profiles are separate accounts: the same row under two of them is two
+		// requests, so only overlaps within one account are merged.
+		row.key = account + "\x00" + row.key
+		occurrences[row.key]++
+		row.eventID = strings.ReplaceAll(row.key, "\x00", "|") + "#" + strconv.Itoa(occurrences[row.key])
+		rows = append(rows, row)
 	}
 
-	return sessions, nil
+	return rows, malformed, nil
 }
 
 func headerIndex(record []string) map[string]int {
@@ -270,7 +406,7 @@ func headerIndex(record []string) map[string]int {
 	return index
 }
 
-func parseUsageRecord(baseName string, rowNumber int, header map[string]int, record []string) (provider.SessionInfo, bool) {
+func parseUsageRecord(baseName string, rowNumber int, header map[string]int, record []string) (usageRow, bool) {
 	value := func(name string) string {
 		idx, ok := header[name]
 		if !ok || idx >= len(record) {
@@ -281,24 +417,24 @@ func parseUsageRecord(baseName string, rowNumber int, header map[string]int, rec
 
 	ts, err := time.Parse(time.RFC3339Nano, value(cursorDateHeader))
 	if err != nil {
-		return provider.SessionInfo{}, false
+		return usageRow{}, false
 	}
 
 	inputCacheCreate, err := parseCursorInt(value(cursorInputCacheCreateHeader))
 	if err != nil {
-		return provider.SessionInfo{}, false
+		return usageRow{}, false
 	}
 	inputOther, err := parseCursorInt(value(cursorInputOtherHeader))
 	if err != nil {
-		return provider.SessionInfo{}, false
+		return usageRow{}, false
 	}
 	inputCacheRead, err := parseCursorInt(value(cursorInputCacheReadHeader))
 	if err != nil {
-		return provider.SessionInfo{}, false
+		return usageRow{}, false
 	}
 	output, err := parseCursorInt(value(cursorOutputHeader))
 	if err != nil {
-		return provider.SessionInfo{}, false
+		return usageRow{}, false
 	}
 
 	kind := value(cursorKindHeader)
@@ -308,7 +444,13 @@ func parseUsageRecord(baseName string, rowNumber int, header map[string]int, rec
 		title = "Cursor usage export"
 	}
 
-	return provider.SessionInfo{
+	usage := provider.TokenUsage{
+		InputOther:       inputOther,
+		Output:           output,
+		InputCacheRead:   inputCacheRead,
+		InputCacheCreate: inputCacheCreate,
+	}
+	session := provider.SessionInfo{
 		ProviderName: "cursor",
 		ModelName:    model,
 		SessionID:    baseName + ":" + strconv.Itoa(rowNumber),
@@ -316,13 +458,18 @@ func parseUsageRecord(baseName string, rowNumber int, header map[string]int, rec
 		StartTime:    ts,
 		EndTime:      ts,
 		Turns:        1,
-		TokenUsage: provider.TokenUsage{
-			InputOther:       inputOther,
-			Output:           output,
-			InputCacheRead:   inputCacheRead,
-			InputCacheCreate: inputCacheCreate,
-		},
-	}, true
+		TokenUsage:   usage,
+	}
+	key := strings.Join([]string{
+		ts.UTC().Format(time.RFC3339Nano),
+		kind,
+		model,
+		strconv.Itoa(inputCacheCreate),
+		strconv.Itoa(inputOther),
+		strconv.Itoa(inputCacheRead),
+		strconv.Itoa(output),
+	}, "\x00")
+	return usageRow{session: session, key: key}, true
 }
 
 func recordHasRequiredFields(header map[string]int, record []string) bool {
@@ -1,6 +1,7 @@
 package cursor
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -114,8 +115,8 @@ func TestCollectUsageEvents_ValidExportMapsRowsToEvents(t *testing.T) {
 	if first.SessionID != "usage:1" {
 		t.Fatalf("SessionID = %q, want usage:1", first.SessionID)
 	}
-	if first.EventID != "usage:1" {
-		t.Fatalf("EventID = %q, want usage:1", first.EventID)
+	if want := "default|2026-02-17T10:00:00Z|Included|auto|28342|775|105891|21282#1"; first.EventID != want {
+		t.Fatalf("EventID = %q, want %q", first.EventID, want)
 	}
 	if first.SourcePath != path {
 		t.Fatalf("SourcePath = %q, want %q", first.SourcePath, path)
@@ -147,8 +148,8 @@ func TestCollectUsageEvents_ValidExportMapsRowsToEvents(t *testing.T) {
 	if second.SessionID != "usage:2" {
 		t.Fatalf("second SessionID = %q, want usage:2", second.SessionID)
 	}
-	if second.EventID != "usage:2" {
-		t.Fatalf("second EventID = %q, want usage:2", second.EventID)
+	if want := "default|2026-02-18T03:30:00Z|On-Demand|gpt-5-codex|0|8263|66964|1612#1"; second.EventID != want {
+		t.Fatalf("second EventID = %q, want %q", second.EventID, want)
 	}
 	if got, want := second.Timestamp.Format(time.RFC3339), "2026-02-18T11:30:00+08:00"; got != want {
 		t.Fatalf("second Timestamp = %q, want %q", got, want)
@@ -210,6 +211,124 @@ func TestCollectUsageEvents_SkipsInvalidCSVFileAndKeepsDeterministicOrder(t *tes
 	}
 }
 
+func TestCollectUsageEvents_DropsRowsDuplicatedAcrossSources(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	root := filepath.Join(home, ".codetok", "cursor")
+	shared := `"2026-02-18T10:00:00.000Z","Included","gpt-5","0","100","0","10"`
+	burst := `"2026-02-18T11:00:00Z","Included","gpt-5","0","5","0","1"`
+	syncedPath := filepath.Join(root, "synced", "usage.csv")
+	writeCursorCSVFixture(t, syncedPath, shared, burst, burst)
+	importPath := filepath.Join(root, "imports", "dashboard.csv")
+	writeCursorCSVFixture(t, importPath,
+		// Same request as the synced row, exported with a different timestamp precision.
+		`"2026-02-18T10:00:00Z","Included","gpt-5","0","100","0","10"`,
+		burst, burst, burst,
+		`"2026-02-18T10:00:00Z","On-Demand","gpt-5","0","100","0","10"`,
+	)
+
+	var diagnostics provider.Diagnostics
+	events, err := (&Provider{}).CollectUsageEventsInRange("", provider.UsageEventCollectOptions{Diagnostics: &diagnostics})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	total := 0
+	fromImport := 0
+	for _, event := range events {
+		total += event.TokenUsage.Total()
+		if event.SourcePath == importPath {
+			fromImport++
+		}
+	}
+	// synced: 110 + 2*6; import adds its third identical burst row and the On-Demand row.
+	if len(events) != 5 || total != 110+3*6+110 {
+		t.Fatalf("got %d events totaling %d, want 5 events totaling %d", len(events), total, 110+3*6+110)
+	}
+	if fromImport != 2 {
+		t.Fatalf("kept %d import rows, want 2", fromImport)
+	}
+	if diagnostics.DuplicateRows() != 3 {
+		t.Fatalf("DuplicateRows() = %d, want 3", diagnostics.DuplicateRows())
+	}
+	issues := diagnostics.Issues()
+	if len(issues) != 1 || issues[0].Path != importPath || issues[0].DuplicateRows != 3 {
+		t.Fatalf("issues = %#v, want duplicates attributed to the import", issues)
+	}
+	if skipped, malformed := diagnostics.Counts(); skipped != 0 || malformed != 0 {
+		t.Fatalf("Counts() = %d, %d; duplicates must not count as parse problems", skipped, malformed)
+	}
+
+	sessions, err := (&Provider{}).CollectSessions("")
+	if err != nil {
+		t.Fatalf("CollectSessions unexpected error: %v", err)
+	}
+	if len(sessions) != 5 {
+		t.Fatalf("CollectSessions got %d sessions, want 5", len(sessions))
+	}
+}
+
+func TestCollectUsageEvents_LabelsRowsWithProfileAccount(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	root := filepath.Join(home, ".codetok", "cursor")
+	writeCursorCSVFixture(t, filepath.Join(root, "synced", "usage.csv"),
+		`"2026-02-18T10:00:00Z","Included","gpt-5","0","100","0","10"`,
+	)
+	writeCursorCSVFixture(t, filepath.Join(root, "profiles", "work", "synced", "usage.csv"),
+		`"2026-02-19T10:00:00Z","Included","gpt-5","0","200","0","20"`,
+	)
+	// Snapshots are raw downloads already merged into the ledger.
+	writeCursorCSVFixture(t, filepath.Join(root, "profiles", "work", "snapshots", "usage-20260219T120000Z.csv"),
+		`"2026-02-19T10:00:00Z","Included","gpt-5","0","200","0","20"`,
+	)
+
+	events, err := (&Provider{}).CollectUsageEvents("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want one per ledger", len(events))
+	}
+	if events[0].Account != "default" || events[0].SessionID != "usage:1" {
+		t.Fatalf("default event = %+v, want account default and session usage:1", events[0])
+	}
+	if events[1].Account != "work" || events[1].SessionID != "work/usage:1" {
+		t.Fatalf("work event = %+v, want account work and session work/usage:1", events[1])
+	}
+}
+
+func TestCollectUsageEvents_KeepsSameRowUnderDifferentProfiles(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	root := filepath.Join(home, ".codetok", "cursor")
+	row := `"2026-02-18T10:00:00Z","Included","gpt-5","0","100","0","10"`
+	writeCursorCSVFixture(t, filepath.Join(root, "profiles", "personal", "synced", "usage.csv"), row)
+	writeCursorCSVFixture(t, filepath.Join(root, "profiles", "work", "synced", "usage.csv"), row)
+	// A manual import of the same row overlaps only the default account.
+	writeCursorCSVFixture(t, filepath.Join(root, "synced", "usage.csv"), row)
+	writeCursorCSVFixture(t, filepath.Join(root, "imports", "dashboard.csv"), row)
+
+	var diagnostics provider.Diagnostics
+	events, err := (&Provider{}).CollectUsageEventsInRange("", provider.UsageEventCollectOptions{Diagnostics: &diagnostics})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	accounts := make(map[string]int)
+	for _, event := range events {
+		accounts[event.Account]++
+	}
+	if len(events) != 3 || accounts["personal"] != 1 || accounts["work"] != 1 || accounts["default"] != 1 {
+		t.Fatalf("events by account = %v, want one row per account", accounts)
+	}
+	if diagnostics.DuplicateRows() != 1 {
+		t.Fatalf("DuplicateRows() = %d, want only the import overlap", diagnostics.DuplicateRows())
+	}
+}
+
 func TestCollectUsageEvents_DefaultRootAndExplicitDirRules(t *testing.T) {
 	home := t.TempDir()
 	t.Setenv("HOME", home)
@@ -322,6 +441,39 @@ func TestCollectUsageEventsInRange_MetricsCountParseAttempts(t *testing.T) {
 	}
 }
 
+func TestCollectUsageEventsInRange_ReportsDiagnostics(t *testing.T) {
+	baseDir := t.TempDir()
+	brokenPath := filepath.Join(baseDir, "broken.csv")
+	if err := os.WriteFile(brokenPath, []byte("Date,Model\nbad,cursor-auto\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	validPath := filepath.Join(baseDir, "valid.csv")
+	writeCursorCSVFixture(t, validPath,
+		`"2026-04-16T12:00:00Z","inside","cursor-auto","0","200","0","20"`,
+		`"not-a-date","bad","cursor-auto","0","200","0","20"`,
+	)
+
+	var diagnostics provider.Diagnostics
+	events, err := (&Provider{}).CollectUsageEventsInRange(baseDir, provider.UsageEventCollectOptions{Diagnostics: &diagnostics})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("got %d events, want one valid row", len(events))
+	}
+
+	issues := diagnostics.Issues()
+	if len(issues) != 2 {
+		t.Fatalf("issues = %#v, want broken file and malformed row", issues)
+	}
+	if issues[0].Path != brokenPath || !errors.Is(issues[0].Err, os.ErrInvalid) {
+		t.Errorf("issues[0] = %#v, want skipped broken.csv", issues[0])
+	}
+	if issues[1].Path != validPath || issues[1].Err != nil || issues[1].MalformedLines != 1 {
+		t.Errorf("issues[1] = %#v, want one malformed row in valid.csv", issues[1])
+	}
+}
+
 func TestCollectUsageEventsInRange_ExplicitDirRules(t *testing.T) {
 	defaultRoot := t.TempDir()
 	explicitDir := t.TempDir()
@@ -0,0 +1,125 @@
+package provider
+
+import (
+	"sort"
+	"sync"
+)
+
+// ParseErrorFunc receives an item that a parser failed on.
+type ParseErrorFunc func(path string, err error)
+
+// Diagnostics collects local files that could not be parsed, lines that were
+// skipped as malformed, and rows dropped as duplicates, so reports can say what
+// they left out. It is safe for concurrent use. A nil *Diagnostics discards
+// everything.
+type Diagnostics struct {
+	mu     sync.Mutex
+	issues map[string]*DiagnosticIssue
+}
+
+// DiagnosticIssue describes the problems found in one local file.
+type DiagnosticIssue struct {
+	Provider string
+	Path     string
+	// Err is why the whole file was skipped; nil when only some lines were.
+	Err            error
+	MalformedLines int
+	// DuplicateRows counts rows dropped because another source already had them.
+	DuplicateRows int
+}
+
+// AddFileError records that path was skipped because its parser failed.
+func (d *Diagnostics) AddFileError(providerName, path string, err error) {
+	if d == nil || err == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.issue(providerName, path).Err = err
+}
+
+// AddMalformedLines records n lines or rows of path that could not be decoded.
+func (d *Diagnostics) AddMalformedLines(providerName, path string, n int) {
+	if d == nil || n <= 0 {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.issue(providerName, path).MalformedLines += n
+}
+
+// AddDuplicateRows records n rows of path that were dropped as duplicates.
+func (d *Diagnostics) AddDuplicateRows(providerName, path string, n int) {
+	if d == nil || n <= 0 {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.issue(providerName, path).DuplicateRows += n
+}
+
+// FileErrorHandler returns a ParseErrorFunc that records failures for providerName.
+func (d *Diagnostics) FileErrorHandler(providerName string) ParseErrorFunc {
+	if d == nil {
+		return nil
+	}
+	return func(path string, err error) {
+		d.AddFileError(providerName, path, err)
+	}
+}
+
+// Issues returns every recorded issue, sorted by provider and path.
+func (d *Diagnostics) Issues() []DiagnosticIssue {
+	if d == nil {
+		return nil
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	issues := make([]DiagnosticIssue, 0, len(d.issues))
+	for _, issue := range d.issues {
+		issues = append(issues, *issue)
+	}
+	sort.Slice(issues, func(i, j int) bool {
+		if issues[i].Provider != issues[j].Provider {
+			return issues[i].Provider < issues[j].Provider
+		}
+		return issues[i].Path < issues[j].Path
+	})
+	return issues
+}
+
+// Counts returns how many files were skipped and how many malformed lines were
+// dropped from files that were otherwise parsed. Duplicate rows are expected
+// and not counted; see DuplicateRows.
+func (d *Diagnostics) Counts() (skippedFiles, malformedLines int) {
+	for _, issue := range d.Issues() {
+		if issue.Err != nil {
+			skippedFiles++
+			continue
+		}
+		malformedLines += issue.MalformedLines
+	}
+	return skippedFiles, malformedLines
+}
+
+// DuplicateRows returns the total number of rows dropped as duplicates.
+func (d *Diagnostics) DuplicateRows() int {
+	total := 0
+	for _, issue := range d.Issues() {
+		total += issue.DuplicateRows
+	}
+	return total
+}
+
+func (d *Diagnostics) issue(providerName, path string) *DiagnosticIssue {
+	if d.issues == nil {
+		d.issues = make(map[string]*DiagnosticIssue)
+	}
+	key := providerName + "\x00" + path
+	issue, ok := d.issues[key]
+	if !ok {
+		issue = &DiagnosticIssue{Provider: providerName, Path: path}
+		d.issues[key] = issue
+	}
+	return issue
+}
@@ -0,0 +1,427 @@
+package gemini
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/miss-you/codetok/provider"
+)
+
+func init() {
+	provider.Register(&Provider{})
+}
+
+// Provider implements provider.Provider for the Gemini CLI.
+type Provider struct{}
+
+// Name returns the provider name.
+func (p *Provider) Name() string {
+	return "gemini"
+}
+
+// conversationRecord represents one tmp/<project-hash>/chats/session-*.json file.
+type conversationRecord struct {
+	SessionID   string        `json:"sessionId"`
+	ProjectHash string        `json:"projectHash"`
+	StartTime   string        `json:"startTime"`
+	LastUpdated string        `json:"lastUpdated"`
+	Summary     string        `json:"summary"`
+	Messages    []chatMessage `json:"messages"`
+}
+
+// chatMessage is one recorded user or model message.
+type chatMessage struct {
+	ID            string          `json:"id"`
+	Timestamp     string          `json:"timestamp"`
+	Type          string          `json:"type"`
+	Content       json.RawMessage `json:"content"`
+	Model         string          `json:"model"`
+	Tokens        *geminiTokens   `json:"tokens"`
+	UsageMetadata *usageMetadata  `json:"usageMetadata"`
+}
+
+// geminiTokens is the token summary the Gemini CLI records on model messages.
+// Input is the full prompt size and already includes Cached.
+type geminiTokens struct {
+	Input    int `json:"input"`
+	Output   int `json:"output"`
+	Cached   int `json:"cached"`
+	Thoughts int `json:"thoughts"`
+	Tool     int `json:"tool"`
+	Total    int `json:"total"`
+}
+
+// usageMetadata is the raw Gemini API usage block, kept by some CLI versions
+// instead of the condensed tokens summary.
+type usageMetadata struct {
+	PromptTokenCount        int `json:"promptTokenCount"`
+	CandidatesTokenCount    int `json:"candidatesTokenCount"`
+	CachedContentTokenCount int `json:"cachedContentTokenCount"`
+	ThoughtsTokenCount      int `json:"thoughtsTokenCount"`
+	ToolUsePromptTokenCount int `json:"toolUsePromptTokenCount"`
+	TotalTokenCount         int `json:"totalTokenCount"`
+}
+
+type contentPart struct {
+	Text string `json:"text"`
+}
+
+// CollectSessions scans baseDir for Gemini CLI chat files and returns session info.
+// The expected layout is: baseDir/<project-hash>/chats/session-*.json, where baseDir
+// is the Gemini CLI tmp directory (default: ~/.gemini/tmp).
+func (p *Provider) CollectSessions(baseDir string) ([]provider.SessionInfo, error) {
+	paths, err := collectChatPaths(baseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := provider.ParseParallel(paths, 0, func(path string) (provider.SessionInfo, error) {
+		return parseSession(path)
+	}, nil)
+	return sessions, nil
+}
+
+// CollectUsageEvents scans baseDir for Gemini CLI chat files and returns one usage event per model message.
+func (p *Provider) CollectUsageEvents(baseDir string) ([]provider.UsageEvent, error) {
+	return p.collectUsageEvents(baseDir, provider.UsageEventCollectOptions{})
+}
+
+func (p *Provider) CollectUsageEventsInRange(baseDir string, opts provider.UsageEventCollectOptions) ([]provider.UsageEvent, error) {
+	return p.collectUsageEvents(baseDir, opts)
+}
+
+func (p *Provider) collectUsageEvents(baseDir string, opts provider.UsageEventCollectOptions) ([]provider.UsageEvent, error) {
+	paths, err := collectChatPaths(baseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	paths = filterChatPaths(paths, opts)
+	if opts.Metrics != nil {
+		opts.Metrics.ParsedFiles += len(paths)
+	}
+	events := provider.ParseUsageEventsParallel(paths, 0, func(path string) ([]provider.UsageEvent, error) {
+		return opts.Cache.ParseFile(p.Name(), usageEventCacheVersion, path, parseUsageEvents)
+	}, opts.Diagnostics.FileErrorHandler(p.Name()))
+	sortUsageEvents(events)
+	if opts.Metrics != nil {
+		opts.Metrics.EmittedEvents += len(events)
+	}
+	return events, nil
+}
+
+// filterChatPaths keeps chat files whose session started close enough to the
+// window to overlap it, and otherwise falls back to the file modification time.
+func filterChatPaths(paths []string, opts provider.UsageEventCollectOptions) []string {
+	if !opts.HasRange() {
+		if opts.Metrics != nil {
+			opts.Metrics.ConsideredFiles += len(paths)
+		}
+		return paths
+	}
+	filtered := make([]string, 0, len(paths))
+	for _, path := range paths {
+		if opts.Metrics != nil {
+			opts.Metrics.ConsideredFiles++
+		}
+		if chatPathMayOverlapRange(path, opts) {
+			filtered = append(filtered, path)
+			continue
+		}
+		info, err := os.Stat(path)
+		if err == nil && !opts.ShouldSkipFileByModTime(info.ModTime()) {
+			filtered = append(filtered, path)
+			continue
+		}
+		if opts.Metrics != nil {
+			opts.Metrics.SkippedFiles++
+		}
+	}
+	return filtered
+}
+
+func chatPathMayOverlapRange(path string, opts provider.UsageEventCollectOptions) bool {
+	if opts.Since.IsZero() {
+		return true
+	}
+	started, ok := chatPathStartTime(path)
+	if !ok {
+		return true
+	}
+	return !started.Before(opts.Since.AddDate(0, 0, -1))
+}
+
+// chatPathStartTime reads the session start minute encoded in file names like
+// session-2026-02-15T09-30-1a2b3c4d.json. The CLI writes that stamp in UTC.
+func chatPathStartTime(path string) (time.Time, bool) {
+	name := strings.TrimPrefix(filepath.Base(path), "session-")
+	if len(name) < len("2006-01-02T15-04") {
+		return time.Time{}, false
+	}
+	parsed, err := time.ParseInLocation("2006-01-02T15-04", name[:len("2006-01-02T15-04")], time.UTC)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return parsed, true
+}
+
+func resolveTmpDir(baseDir string) (string, error) {
+	if baseDir != "" {
+		return baseDir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".gemini", "tmp"), nil
+}
+
+// collectChatPaths returns every <project-hash>/chats/*.json file under the tmp
+// directory. Checkpoints (checkpoint-<tag>.json from /chat save, and the
+// checkpoints/ directory of --checkpointing) hold conversation history without
+// usage metadata, so they are not read.
+func collectChatPaths(baseDir string) ([]string, error) {
+	tmpDir, err := resolveTmpDir(baseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	projects, err := os.ReadDir(tmpDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, project := range projects {
+		if !project.IsDir() {
+			continue
+		}
+		chatsDir := filepath.Join(tmpDir, project.Name(), "chats")
+		entries, err := os.ReadDir(chatsDir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+				continue
+			}
+			paths = append(paths, filepath.Join(chatsDir, entry.Name()))
+		}
+	}
+	return paths, nil
+}
+
+func readConversation(path string) (conversationRecord, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return conversationRecord{}, err
+	}
+	var record conversationRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return conversationRecord{}, err
+	}
+	if strings.TrimSpace(record.SessionID) == "" {
+		record.SessionID = strings.TrimSuffix(filepath.Base(path), ".json")
+	}
+	if strings.TrimSpace(record.ProjectHash) == "" {
+		record.ProjectHash = filepath.Base(filepath.Dir(filepath.Dir(path)))
+	}
+	return record, nil
+}
+
+// parseSession folds one chat file into a SessionInfo.
+func parseSession(path string) (provider.SessionInfo, error) {
+	record, err := readConversation(path)
+	if err != nil {
+		return provider.SessionInfo{}, err
+	}
+
+	info := provider.SessionInfo{
+		ProviderName: "gemini",
+		SessionID:    record.SessionID,
+		Title:        conversationTitle(record),
+		WorkDirHash:  record.ProjectHash,
+		StartTime:    parseTimestamp(record.StartTime),
+		EndTime:      parseTimestamp(record.LastUpdated),
+	}
+	for _, msg := range record.Messages {
+		ts := parseTimestamp(msg.Timestamp)
+		if !ts.IsZero() {
+			if info.StartTime.IsZero() || ts.Before(info.StartTime) {
+				info.StartTime = ts
+			}
+			if ts.After(info.EndTime) {
+				info.EndTime = ts
+			}
+		}
+		switch msg.Type {
+		case "user":
+			info.Turns++
+		case "gemini":
+			if info.ModelName == "" {
+				info.ModelName = strings.TrimSpace(msg.Model)
+			}
+			if usage, ok := tokenUsageFromMessage(msg); ok {
+				info.TokenUsage.InputOther += usage.InputOther
+				info.TokenUsage.Output += usage.Output
+				info.TokenUsage.OutputReasoning += usage.OutputReasoning
+				info.TokenUsage.InputCacheRead += usage.InputCacheRead
+				info.TokenUsage.InputCacheCreate += usage.InputCacheCreate
+			}
+		}
+	}
+	return info, nil
+}
+
+// usageEventCacheVersion must change whenever parseUsageEvents output changes.
+const usageEventCacheVersion = "gemini/1"
+
+// parseUsageEvents returns one usage event per model message with token usage.
+func parseUsageEvents(path string) ([]provider.UsageEvent, error) {
+	record, err := readConversation(path)
+	if err != nil {
+		return nil, err
+	}
+
+	title := conversationTitle(record)
+	var events []provider.UsageEvent
+	for i, msg := range record.Messages {
+		if msg.Type != "gemini" {
+			continue
+		}
+		usage, ok := tokenUsageFromMessage(msg)
+		if !ok {
+			continue
+		}
+		ts := parseTimestamp(msg.Timestamp)
+		if ts.IsZero() {
+			continue
+		}
+		eventID := strings.TrimSpace(msg.ID)
+		if eventID == "" {
+			eventID = record.SessionID + ":" + strconv.Itoa(i)
+		}
+		events = append(events, provider.UsageEvent{
+			ProviderName: "gemini",
+			ModelName:    strings.TrimSpace(msg.Model),
+			SessionID:    record.SessionID,
+			Title:        title,
+			WorkDirHash:  record.ProjectHash,
+			Timestamp:    ts,
+			TokenUsage:   usage,
+			SourcePath:   path,
+			EventID:      eventID,
+		})
+	}
+	return events, nil
+}
+
+// tokenUsageFromMessage maps Gemini token counts into codetok fields.
+// The prompt count includes cached content, so cached tokens are moved to
+// cache read; thoughts are billed as output and tool-use prompts as input.
+func tokenUsageFromMessage(msg chatMessage) (provider.TokenUsage, bool) {
+	tokens := msg.Tokens
+	if tokens == nil && msg.UsageMetadata != nil {
+		tokens = &geminiTokens{
+			Input:    msg.UsageMetadata.PromptTokenCount,
+			Output:   msg.UsageMetadata.CandidatesTokenCount,
+			Cached:   msg.UsageMetadata.CachedContentTokenCount,
+			Thoughts: msg.UsageMetadata.ThoughtsTokenCount,
+			Tool:     msg.UsageMetadata.ToolUsePromptTokenCount,
+			Total:    msg.UsageMetadata.TotalTokenCount,
+		}
+	}
+	if tokens == nil {
+		return provider.TokenUsage{}, false
+	}
+
+	inputOther := tokens.Input - tokens.Cached
+	if inputOther < 0 {
+		inputOther = 0
+	}
+	usage := provider.TokenUsage{
+		InputOther:      inputOther + tokens.Tool,
+		InputCacheRead:  tokens.Cached,
+		Output:          tokens.Output + tokens.Thoughts,
+		OutputReasoning: tokens.Thoughts,
+	}
+	if usage.Total() == 0 {
+		return provider.TokenUsage{}, false
+	}
+	return usage, true
+}
+
+// conversationTitle prefers the CLI-generated summary and falls back to the first user prompt.
+func conversationTitle(record conversationRecord) string {
+	if summary := strings.TrimSpace(record.Summary); summary != "" {
+		return truncateTitle(summary, 80)
+	}
+	for _, msg := range record.Messages {
+		if msg.Type != "user" {
+			continue
+		}
+		if text := strings.TrimSpace(contentText(msg.Content)); text != "" {
+			return truncateTitle(text, 80)
+		}
+	}
+	return ""
+}
+
+// contentText extracts text from a message content that is either a plain
+// string or a list of parts.
+func contentText(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text
+	}
+	var parts []contentPart
+	if err := json.Unmarshal(raw, &parts); err == nil {
+		for _, part := range parts {
+			if part.Text != "" {
+				return part.Text
+			}
+		}
+	}
+	return ""
+}
+
+func parseTimestamp(value string) time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}
+	}
+	ts, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}
+	}
+	return ts
+}
+
+// truncateTitle truncates a string to max runes.
+func truncateTitle(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-3]) + "..."
+}
+
+func sortUsageEvents(events []provider.UsageEvent) {
+	sort.Slice(events, func(i, j int) bool {
+		if !events[i].Timestamp.Equal(events[j].Timestamp) {
+			return events[i].Timestamp.Before(events[j].Timestamp)
+		}
+		if events[i].SessionID != events[j].SessionID {
+			return events[i].SessionID < events[j].SessionID
+		}
+		return events[i].EventID < events[j].EventID
+	})
+}
@@ -0,0 +1,175 @@
+package gemini
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/miss-you/codetok/provider"
+)
+
+func testTmpDir(t *testing.T) string {
+	t.Helper()
+	dir, err := filepath.Abs(filepath.Join("testdata", "tmp"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestCollectUsageEvents_EmitsOneEventPerModelMessage(t *testing.T) {
+	events, err := (&Provider{}).CollectUsageEvents(testTmpDir(t))
+	if err != nil {
+		t.Fatalf("CollectUsageEvents returned error: %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("got %d events, want 3", len(events))
+	}
+
+	first := events[0]
+	if first.ProviderName != "gemini" {
+		t.Errorf("ProviderName = %q, want gemini", first.ProviderName)
+	}
+	if first.SessionID != "aaaa1111-0000-4000-8000-000000000001" || first.EventID != "m-002" {
+		t.Errorf("first event = %s/%s, want aaaa1111.../m-002", first.SessionID, first.EventID)
+	}
+	if first.ModelName != "gemini-2.5-pro" {
+		t.Errorf("ModelName = %q, want gemini-2.5-pro", first.ModelName)
+	}
+	if first.Title != "Explain the retry loop in fetcher.go" {
+		t.Errorf("Title = %q, want first user prompt", first.Title)
+	}
+	if first.WorkDirHash != "3f1a9c" {
+		t.Errorf("WorkDirHash = %q, want project hash", first.WorkDirHash)
+	}
+	if !first.Timestamp.Equal(time.Date(2026, 2, 15, 9, 30, 20, 0, time.UTC)) {
+		t.Errorf("Timestamp = %v, want message timestamp", first.Timestamp)
+	}
+	want := provider.TokenUsage{InputOther: 400, InputCacheRead: 800, Output: 450, OutputReasoning: 150}
+	if first.TokenUsage != want {
+		t.Errorf("TokenUsage = %+v, want %+v", first.TokenUsage, want)
+	}
+
+	second := events[1]
+	wantSecond := provider.TokenUsage{InputOther: 440, InputCacheRead: 1200, Output: 250, OutputReasoning: 50}
+	if second.TokenUsage != wantSecond {
+		t.Errorf("TokenUsage = %+v, want %+v (tool prompt counted as input)", second.TokenUsage, wantSecond)
+	}
+
+	last := events[2]
+	if last.ModelName != "gemini-2.5-flash" || last.Title != "Write release notes" {
+		t.Errorf("last event = %s/%q, want gemini-2.5-flash with summary title", last.ModelName, last.Title)
+	}
+	wantLast := provider.TokenUsage{InputOther: 500, Output: 150, OutputReasoning: 30}
+	if last.TokenUsage != wantLast {
+		t.Errorf("TokenUsage = %+v, want %+v from usageMetadata", last.TokenUsage, wantLast)
+	}
+}
+
+func TestCollectSessions_AggregatesChatFile(t *testing.T) {
+	sessions, err := (&Provider{}).CollectSessions(testTmpDir(t))
+	if err != nil {
+		t.Fatalf("CollectSessions returned error: %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("got %d sessions, want 2", len(sessions))
+	}
+
+	byID := make(map[string]provider.SessionInfo)
+	for _, s := range sessions {
+		byID[s.SessionID] = s
+	}
+	first, ok := byID["aaaa1111-0000-4000-8000-000000000001"]
+	if !ok {
+		t.Fatalf("missing first session in %+v", sessions)
+	}
+	if first.Turns != 2 {
+		t.Errorf("Turns = %d, want 2", first.Turns)
+	}
+	if first.TokenUsage.Total() != 3540 {
+		t.Errorf("Total = %d, want 3540", first.TokenUsage.Total())
+	}
+	if first.ModelName != "gemini-2.5-pro" {
+		t.Errorf("ModelName = %q, want gemini-2.5-pro", first.ModelName)
+	}
+	if !first.StartTime.Equal(time.Date(2026, 2, 15, 9, 30, 0, 0, time.UTC)) {
+		t.Errorf("StartTime = %v, want session start", first.StartTime)
+	}
+}
+
+func TestCollectUsageEvents_MissingDirReturnsNotExist(t *testing.T) {
+	_, err := (&Provider{}).CollectUsageEvents(filepath.Join(t.TempDir(), "missing"))
+	if !os.IsNotExist(err) {
+		t.Fatalf("err = %v, want not-exist error", err)
+	}
+}
+
+func TestCollectUsageEvents_SkipsMalformedChatFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	chatsDir := filepath.Join(tmpDir, "abc", "chats")
+	if err := os.MkdirAll(chatsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(chatsDir, "session-2026-02-15T10-00-bad.json"), []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	valid := `{"messages":[{"id":"m1","timestamp":"2026-02-15T10:00:00Z","type":"gemini","model":"gemini-2.5-pro","tokens":{"input":10,"output":5}}]}`
+	if err := os.WriteFile(filepath.Join(chatsDir, "session-2026-02-15T10-01-good.json"), []byte(valid), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := (&Provider{}).CollectUsageEvents(tmpDir)
+	if err != nil {
+		t.Fatalf("CollectUsageEvents returned error: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("events = %+v, want one event from the valid file", events)
+	}
+	if events[0].SessionID != "session-2026-02-15T10-01-good" || events[0].WorkDirHash != "abc" {
+		t.Fatalf("event = %+v, want file name session ID and directory project hash", events[0])
+	}
+}
+
+func TestCollectUsageEventsInRange_SkipsOldChatFilesByNameAndModTime(t *testing.T) {
+	tmpDir := t.TempDir()
+	chatsDir := filepath.Join(tmpDir, "abc", "chats")
+	if err := os.MkdirAll(chatsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := `{"messages":[{"id":"m1","timestamp":"%s","type":"gemini","tokens":{"input":10,"output":5}}]}`
+	oldPath := filepath.Join(chatsDir, "session-2026-04-01T10-00-old.json")
+	resumedPath := filepath.Join(chatsDir, "session-2026-04-02T10-00-resumed.json")
+	activePath := filepath.Join(chatsDir, "session-2026-04-16T10-00-active.json")
+	writeChatFile(t, oldPath, content, "2026-04-01T10:00:00Z", time.Date(2026, 4, 1, 11, 0, 0, 0, time.UTC))
+	writeChatFile(t, resumedPath, content, "2026-04-16T09:00:00Z", time.Date(2026, 4, 16, 9, 0, 0, 0, time.UTC))
+	writeChatFile(t, activePath, content, "2026-04-16T10:00:00Z", time.Date(2026, 4, 16, 11, 0, 0, 0, time.UTC))
+
+	var metrics provider.UsageEventCollectMetrics
+	events, err := (&Provider{}).CollectUsageEventsInRange(tmpDir, provider.UsageEventCollectOptions{
+		Since:    time.Date(2026, 4, 16, 0, 0, 0, 0, time.UTC),
+		Location: time.UTC,
+		Metrics:  &metrics,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("events = %#v, want resumed and active sessions", events)
+	}
+	if metrics.ConsideredFiles != 3 || metrics.SkippedFiles != 1 || metrics.ParsedFiles != 2 || metrics.EmittedEvents != 2 {
+		t.Fatalf("metrics = %+v, want considered=3 skipped=1 parsed=2 emitted=2", metrics)
+	}
+}
+
+func writeChatFile(t *testing.T, path, format, timestamp string, modTime time.Time) {
+	t.Helper()
+	content := []byte(fmt.Sprintf(format, timestamp))
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
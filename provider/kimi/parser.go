@@ -2,7 +2,9 @@ package kimi
 
 import (
 	"bufio"
+	"crypto/md5"
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -56,6 +58,14 @@ type tokenUsagePayload struct {
 	InputCacheCreation int `json:"input_cache_creation"`
 }
 
+// kimiConfig represents the kimi.json file next to the sessions directory.
+type kimiConfig struct {
+	WorkDirs []struct {
+		Path string `json:"path"`
+		Kaos string `json:"kaos"`
+	} `json:"work_dirs"`
+}
+
 // metadata represents the metadata.json file.
 type metadata struct {
 	SessionID string `json:"session_id"`
@@ -72,6 +82,7 @@ func (p *Provider) CollectSessions(baseDir string) ([]provider.SessionInfo, erro
 		baseDir = defaultKimiSessionsDir()
 	}
 	sessionModelIndex := loadSessionModelsFromLogs(detectKimiLogsDir(baseDir))
+	workDirPaths := loadWorkDirPaths(baseDir)
 
 	// Phase 1: Walk directories, collect all session paths (sequential, fast)
 	var paths []string
@@ -113,8 +124,10 @@ func (p *Provider) CollectSessions(baseDir string) ([]provider.SessionInfo, erro
 
 	// Phase 2: Parse all sessions in parallel
 	sessions := provider.ParseParallel(paths, 0, func(path string) (provider.SessionInfo, error) {
-		return parseSession(path, pathToHash[path], sessionModelIndex)
-	})
+		info, err := parseSession(path, pathToHash[path], sessionModelIndex)
+		info.ProjectPath = workDirPaths[pathToHash[path]]
+		return info, err
+	}, nil)
 
 	return sessions, nil
 }
@@ -133,15 +146,103 @@ func (p *Provider) collectUsageEvents(baseDir string, opts provider.UsageEventCo
 		baseDir = defaultKimiSessionsDir()
 	}
 	sessionModelIndex := loadSessionModelsFromLogs(detectKimiLogsDir(baseDir))
+	workDirPaths := loadWorkDirPaths(baseDir)
+
+	sessions, err := listKimiSessions(baseDir)
+	if err != nil {
+		return nil, err
+	}
 
 	var paths []string
 	pathToHash := make(map[string]string)
+	for _, session := range sessions {
+		if opts.Metrics != nil {
+			opts.Metrics.ConsideredFiles++
+		}
+		if shouldSkipKimiWirePath(session.wireModTime, opts) {
+			if opts.Metrics != nil {
+				opts.Metrics.SkippedFiles++
+			}
+			continue
+		}
+		paths = append(paths, session.path)
+		pathToHash[session.path] = session.workDirHash
+	}
+
+	if opts.Metrics != nil {
+		opts.Metrics.ParsedFiles += len(paths)
+	}
+	eventBatches := provider.ParseParallel(paths, 0, func(path string) ([]provider.UsageEvent, error) {
+		events, err := parseSessionUsageEvents(path, pathToHash[path], sessionModelIndex, opts.Cache, opts.Diagnostics)
+		for i := range events {
+			events[i].ProjectPath = workDirPaths[pathToHash[path]]
+		}
+		return events, err
+	}, opts.Diagnostics.FileErrorHandler(p.Name()))
+
+	var events []provider.UsageEvent
+	for _, batch := range eventBatches {
+		events = append(events, batch...)
+	}
+	if opts.Metrics != nil {
+		opts.Metrics.EmittedEvents += len(events)
+	}
+
+	return events, nil
+}
+
+// JSONLSourceLister lists Kimi wire logs under baseDir so they can be followed
+// while they grow. The model and work directory indexes are loaded once per
+// lister; session metadata is reapplied to the events on every read.
+func (p *Provider) JSONLSourceLister(baseDir string) func() ([]provider.JSONLSource, error) {
+	if baseDir == "" {
+		baseDir = defaultKimiSessionsDir()
+	}
+	sessionModelIndex := loadSessionModelsFromLogs(detectKimiLogsDir(baseDir))
+	workDirPaths := loadWorkDirPaths(baseDir)
+
+	return func() ([]provider.JSONLSource, error) {
+		sessions, err := listKimiSessions(baseDir)
+		if err != nil {
+			return nil, err
+		}
+		sources := make([]provider.JSONLSource, 0, len(sessions))
+		for _, session := range sessions {
+			session := session
+			wirePath := filepath.Join(session.path, "wire.jsonl")
+			sources = append(sources, provider.JSONLSource{
+				Path: wirePath,
+				NewParser: func() provider.JSONLUsageEventParser {
+					return newKimiWireParser(wirePath)
+				},
+				Label: func(events []provider.UsageEvent) []provider.UsageEvent {
+					events = applyKimiBaseEvent(events, kimiBaseEvent(session.path, session.workDirHash, sessionModelIndex, events))
+					for i := range events {
+						events[i].ProjectPath = workDirPaths[session.workDirHash]
+					}
+					return events
+				},
+			})
+		}
+		return sources, nil
+	}
+}
 
+type kimiSession struct {
+	path        string
+	workDirHash string
+	wireModTime time.Time
+}
+
+// listKimiSessions returns the session directories under baseDir that have a
+// wire log.
+func listKimiSessions(baseDir string) ([]kimiSession, error) {
 	workDirs, err := os.ReadDir(baseDir)
 	if err != nil {
 		return nil, err
 	}
 
+	var sessions []kimiSession
 	for _, wd := range workDirs {
 		if !wd.IsDir() {
 			continue
@@ -159,44 +260,14 @@ func (p *Provider) collectUsageEvents(baseDir string, opts provider.UsageEventCo
 				continue
 			}
 			sessionPath := filepath.Join(workDirPath, sd.Name())
-			wirePath := filepath.Join(sessionPath, "wire.jsonl")
-
-			info, err := os.Stat(wirePath)
+			info, err := os.Stat(filepath.Join(sessionPath, "wire.jsonl"))
 			if err != nil {
 				continue
 			}
-
-			if opts.Metrics != nil {
-				opts.Metrics.ConsideredFiles++
-			}
-			if shouldSkipKimiWirePath(info.ModTime(), opts) {
-				if opts.Metrics != nil {
-					opts.Metrics.SkippedFiles++
-				}
-				continue
-			}
-
-			paths = append(paths, sessionPath)
-			pathToHash[sessionPath] = workDirHash
+			sessions = append(sessions, kimiSession{path: sessionPath, workDirHash: workDirHash, wireModTime: info.ModTime()})
 		}
 	}
-
-	if opts.Metrics != nil {
-		opts.Metrics.ParsedFiles += len(paths)
-	}
-	eventBatches := provider.ParseParallel(paths, 0, func(path string) ([]provider.UsageEvent, error) {
-		return parseSessionUsageEvents(path, pathToHash[path], sessionModelIndex)
-	})
-
-	var events []provider.UsageEvent
-	for _, batch := range eventBatches {
-		events = append(events, batch...)
-	}
-	if opts.Metrics != nil {
-		opts.Metrics.EmittedEvents += len(events)
-	}
-
-	return events, nil
+	return sessions, nil
 }
 
 func shouldSkipKimiWirePath(modTime time.Time, opts provider.UsageEventCollectOptions) bool {
@@ -245,7 +316,22 @@ func parseSession(sessionPath, workDirHash string, sessionModelIndex map[string]
 	return info, nil
 }
 
-func parseSessionUsageEvents(sessionPath, workDirHash string, sessionModelIndex map[string]string) ([]provider.UsageEvent, error) {
+func parseSessionUsageEvents(sessionPath, workDirHash string, sessionModelIndex map[string]string, cache *provider.UsageEventCache, diagnostics *provider.Diagnostics) ([]provider.UsageEvent, error) {
+	wirePath := filepath.Join(sessionPath, "wire.jsonl")
+	events, err := cache.ParseJSONLFile("kimi", kimiUsageEventCacheVersion, wirePath, func() provider.JSONLUsageEventParser {
+		return newKimiWireParser(wirePath)
+	}, diagnostics)
+	if err != nil {
+		return nil, err
+	}
+
+	return applyKimiBaseEvent(events, kimiBaseEvent(sessionPath, workDirHash, sessionModelIndex, events)), nil
+}
+
+// kimiBaseEvent builds the session-level fields for a session's wire events
+// from its metadata.json, falling back to the wire log and Kimi's own logs for
+// the model name.
+func kimiBaseEvent(sessionPath, workDirHash string, sessionModelIndex map[string]string, events []provider.UsageEvent) provider.UsageEvent {
 	baseEvent := provider.UsageEvent{
 		ProviderName: "kimi",
 		WorkDirHash:  workDirHash,
@@ -260,26 +346,13 @@ func parseSessionUsageEvents(sessionPath, workDirHash string, sessionModelIndex
 		baseEvent.SessionID = filepath.Base(sessionPath)
 	}
 
-	wirePath := filepath.Join(sessionPath, "wire.jsonl")
-	events, modelName, err := parseKimiUsageEvents(wirePath, baseEvent)
-	if err != nil {
-		return nil, err
-	}
-
-	resolvedModelName := baseEvent.ModelName
-	if resolvedModelName == "" {
-		resolvedModelName = normalizeKimiModelName(modelName)
+	if baseEvent.ModelName == "" && len(events) > 0 {
+		baseEvent.ModelName = events[0].ModelName
 	}
-	if resolvedModelName == "" {
-		resolvedModelName = modelNameFromLogFallback(baseEvent.SessionID, sessionPath, sessionModelIndex)
+	if baseEvent.ModelName == "" {
+		baseEvent.ModelName = modelNameFromLogFallback(baseEvent.SessionID, sessionPath, sessionModelIndex)
 	}
-	if resolvedModelName != "" {
-		for i := range events {
-			events[i].ModelName = resolvedModelName
-		}
-	}
-
-	return events, nil
+	return baseEvent
 }
 
 // parseMetadata reads and parses a metadata.json file.
@@ -362,62 +435,93 @@ func parseWireJSONL(path string) (provider.TokenUsage, int, time.Time, time.Time
 }
 
 func parseKimiUsageEvents(wirePath string, baseEvent provider.UsageEvent) ([]provider.UsageEvent, string, error) {
-	f, err := os.Open(wirePath)
+	parser := newKimiWireParser(wirePath)
+	events, err := provider.ParseJSONLUsageEvents(wirePath, parser)
 	if err != nil {
 		return nil, "", err
 	}
-	defer f.Close()
+	return applyKimiBaseEvent(events, baseEvent), parser.ModelName, nil
+}
 
-	var events []provider.UsageEvent
-	var modelName string
+// kimiUsageEventCacheVersion must change whenever kimiWireParser output changes.
+const kimiUsageEventCacheVersion = "kimi/1"
 
-	scanner := bufio.NewScanner(f)
-	scanner.Buffer(make([]byte, 0, 1024*1024), 1024*1024)
+// kimiWireParser incrementally parses a wire.jsonl file. Its exported fields
+// are the resumable state kept by the usage event cache. Session metadata is
+// applied afterwards so metadata edits do not invalidate cached wire events.
+type kimiWireParser struct {
+	Path      string
+	LineNo    int
+	ModelName string
+	EventList []provider.UsageEvent
+}
 
-	lineNo := 0
-	for scanner.Scan() {
-		lineNo++
-		line := scanner.Bytes()
-		if len(line) == 0 {
-			continue
-		}
+func newKimiWireParser(wirePath string) *kimiWireParser {
+	return &kimiWireParser{Path: wirePath}
+}
 
-		var event wireEvent
-		if err := json.Unmarshal(line, &event); err != nil {
-			continue
-		}
-		if event.Message.Type != "StatusUpdate" {
-			continue
-		}
+// ParseLine consumes one JSONL line.
+func (p *kimiWireParser) ParseLine(line []byte) {
+	p.LineNo++
+	if len(line) == 0 {
+		return
+	}
 
-		var payload statusPayload
-		if err := json.Unmarshal(event.Message.Payload, &payload); err != nil {
-			continue
-		}
-		if modelName == "" {
-			modelName = firstNonEmpty(payload.ModelName, payload.Model, payload.ModelID)
-		}
+	var event wireEvent
+	if err := json.Unmarshal(line, &event); err != nil {
+		return
+	}
+	if event.Message.Type != "StatusUpdate" {
+		return
+	}
 
-		tokenUsage, ok := tokenUsageFromStatusPayload(payload)
-		if !ok {
-			continue
-		}
+	var payload statusPayload
+	if err := json.Unmarshal(event.Message.Payload, &payload); err != nil {
+		return
+	}
+	if p.ModelName == "" {
+		p.ModelName = firstNonEmpty(payload.ModelName, payload.Model, payload.ModelID)
+	}
 
-		usageEvent := baseEvent
-		if usageEvent.ProviderName == "" {
-			usageEvent.ProviderName = "kimi"
+	tokenUsage, ok := tokenUsageFromStatusPayload(payload)
+	if !ok {
+		return
+	}
+	p.EventList = append(p.EventList, provider.UsageEvent{
+		ProviderName: "kimi",
+		Timestamp:    timeFromUnix(event.Timestamp),
+		TokenUsage:   tokenUsage,
+		SourcePath:   p.Path,
+		EventID:      kimiUsageEventID(p.Path, p.LineNo, payload.MessageID),
+	})
+}
+
+// Events returns the usage events parsed so far, labeled with the first model
+// name the wire log reported.
+func (p *kimiWireParser) Events() []provider.UsageEvent {
+	events := append([]provider.UsageEvent(nil), p.EventList...)
+	modelName := normalizeKimiModelName(p.ModelName)
+	for i := range events {
+		events[i].ModelName = modelName
+	}
+	return events
+}
+
+// applyKimiBaseEvent copies session-level fields from baseEvent onto wire events.
+func applyKimiBaseEvent(events []provider.UsageEvent, baseEvent provider.UsageEvent) []provider.UsageEvent {
+	for i := range events {
+		if baseEvent.ProviderName != "" {
+			events[i].ProviderName = baseEvent.ProviderName
 		}
-		if usageEvent.ModelName == "" {
-			usageEvent.ModelName = normalizeKimiModelName(modelName)
+		events[i].SessionID = baseEvent.SessionID
+		events[i].Title = baseEvent.Title
+		events[i].WorkDirHash = baseEvent.WorkDirHash
+		events[i].ProjectPath = baseEvent.ProjectPath
+		if baseEvent.ModelName != "" {
+			events[i].ModelName = baseEvent.ModelName
 		}
-		usageEvent.Timestamp = timeFromUnix(event.Timestamp)
-		usageEvent.TokenUsage = tokenUsage
-		usageEvent.SourcePath = wirePath
-		usageEvent.EventID = kimiUsageEventID(wirePath, lineNo, payload.MessageID)
-		events = append(events, usageEvent)
 	}
-
-	return events, modelName, scanner.Err()
+	return events
 }
 
 func tokenUsageFromStatusPayload(payload statusPayload) (provider.TokenUsage, bool) {
@@ -517,6 +621,37 @@ func detectKimiLogsDir(baseDir string) string {
 	return ""
 }
 
+// loadWorkDirPaths maps work dir hashes to paths using the kimi.json next to
+// baseDir. Kimi names each session directory after the MD5 of the work dir path,
+// prefixed with the kaos name for non-local environments.
+func loadWorkDirPaths(baseDir string) map[string]string {
+	baseDir = strings.TrimSpace(baseDir)
+	if baseDir == "" {
+		return nil
+	}
+	data, err := os.ReadFile(filepath.Join(filepath.Dir(baseDir), "kimi.json"))
+	if err != nil {
+		return nil
+	}
+	var config kimiConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil
+	}
+
+	paths := make(map[string]string, len(config.WorkDirs))
+	for _, wd := range config.WorkDirs {
+		if wd.Path == "" {
+			continue
+		}
+		hash := fmt.Sprintf("%x", md5.Sum([]byte(wd.Path)))
+		if kaos := strings.TrimSpace(wd.Kaos); kaos != "" && kaos != "local" {
+			hash = kaos + "_" + hash
+		}
+		paths[hash] = wd.Path
+	}
+	return paths
+}
+
 func isDir(path string) bool {
 	info, err := os.Stat(path)
 	if err != nil {
@@ -1,6 +1,7 @@
 package kimi
 
 import (
+	"crypto/md5"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -209,6 +210,47 @@ func TestCollectSessions_MultipleSessionDirs(t *testing.T) {
 	}
 }
 
+func TestCollectKimiUsageEvents_ResolvesProjectPathFromKimiConfig(t *testing.T) {
+	root := t.TempDir()
+	baseDir := filepath.Join(root, "sessions")
+	localHash := fmt.Sprintf("%x", md5.Sum([]byte("/home/dev/codetok")))
+	remoteHash := "ssh_" + fmt.Sprintf("%x", md5.Sum([]byte("/srv/app")))
+	wireContent := `{"timestamp": 1770983426.420, "message": {"type": "StatusUpdate", "payload": {"token_usage": {"input_other": 100, "output": 50}, "message_id": "msg-1"}}}
+`
+	for _, hash := range []string{localHash, remoteHash, "unmapped"} {
+		dir := filepath.Join(baseDir, hash, "uuid-"+hash)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "wire.jsonl"), []byte(wireContent), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	config := `{"work_dirs": [{"path": "/home/dev/codetok", "kaos": "local"}, {"path": "/srv/app", "kaos": "ssh"}]}`
+	if err := os.WriteFile(filepath.Join(root, "kimi.json"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := (&Provider{}).CollectUsageEvents(baseDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := make(map[string]string)
+	for _, event := range events {
+		got[event.WorkDirHash] = event.ProjectPath
+	}
+	want := map[string]string{
+		localHash:  "/home/dev/codetok",
+		remoteHash: "/srv/app",
+		"unmapped": "",
+	}
+	for hash, path := range want {
+		if got[hash] != path {
+			t.Errorf("ProjectPath for %s = %q, want %q", hash, got[hash], path)
+		}
+	}
+}
+
 func TestTimestampExtraction(t *testing.T) {
 	usage, _, startTime, endTime, _, err := parseWireJSONL(filepath.Join("testdata", "wire.jsonl"))
 	if err != nil {
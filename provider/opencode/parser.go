@@ -0,0 +1,434 @@
+package opencode
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/miss-you/codetok/provider"
+)
+
+func init() {
+	provider.Register(&Provider{})
+}
+
+// Provider implements provider.Provider for OpenCode.
+type Provider struct{}
+
+// Name returns the provider name.
+func (p *Provider) Name() string {
+	return "opencode"
+}
+
+// sessionFile represents storage/session/<project-id>/<session-id>.json.
+type sessionFile struct {
+	ID        string `json:"id"`
+	ProjectID string `json:"projectID"`
+	Directory string `json:"directory"`
+	Title     string `json:"title"`
+}
+
+// projectFile represents storage/project/<project-id>.json.
+type projectFile struct {
+	ID       string `json:"id"`
+	Worktree string `json:"worktree"`
+}
+
+// messageFile represents storage/message/<session-id>/<message-id>.json.
+type messageFile struct {
+	ID        string `json:"id"`
+	SessionID string `json:"sessionID"`
+	Role      string `json:"role"`
+	ModelID   string `json:"modelID"`
+	Time      struct {
+		Created   int64 `json:"created"`
+		Completed int64 `json:"completed"`
+	} `json:"time"`
+	Path struct {
+		Cwd  string `json:"cwd"`
+		Root string `json:"root"`
+	} `json:"path"`
+	Tokens *messageTokens `json:"tokens"`
+}
+
+type messageTokens struct {
+	Input     int `json:"input"`
+	Output    int `json:"output"`
+	Reasoning int `json:"reasoning"`
+	Cache     struct {
+		Read  int `json:"read"`
+		Write int `json:"write"`
+	} `json:"cache"`
+}
+
+// sessionMeta carries session-level attributes resolved from session and project files.
+type sessionMeta struct {
+	Title   string
+	Project string
+}
+
+// CollectSessions scans baseDir for OpenCode message directories and returns session info.
+// The expected layout is: baseDir/{session,message,project}/..., where baseDir is
+// OpenCode's storage directory (default: ~/.local/share/opencode/storage).
+func (p *Provider) CollectSessions(baseDir string) ([]provider.SessionInfo, error) {
+	storageDir, err := resolveStorageDir(baseDir)
+	if err != nil {
+		return nil, err
+	}
+	dirs, err := collectMessageDirs(storageDir)
+	if err != nil {
+		return nil, err
+	}
+	metas := loadSessionMetas(storageDir)
+
+	sessions := provider.ParseParallel(dirs, 0, func(dir string) (provider.SessionInfo, error) {
+		return parseSession(dir, metas[filepath.Base(dir)])
+	}, nil)
+	return sessions, nil
+}
+
+// CollectUsageEvents scans baseDir for OpenCode messages and returns one usage event per assistant message.
+func (p *Provider) CollectUsageEvents(baseDir string) ([]provider.UsageEvent, error) {
+	return p.collectUsageEvents(baseDir, provider.UsageEventCollectOptions{})
+}
+
+func (p *Provider) CollectUsageEventsInRange(baseDir string, opts provider.UsageEventCollectOptions) ([]provider.UsageEvent, error) {
+	return p.collectUsageEvents(baseDir, opts)
+}
+
+func (p *Provider) collectUsageEvents(baseDir string, opts provider.UsageEventCollectOptions) ([]provider.UsageEvent, error) {
+	storageDir, err := resolveStorageDir(baseDir)
+	if err != nil {
+		return nil, err
+	}
+	dirs, err := collectMessageDirs(storageDir)
+	if err != nil {
+		return nil, err
+	}
+	metas := loadSessionMetas(storageDir)
+
+	dirs = filterMessageDirs(dirs, opts)
+	if opts.Metrics != nil {
+		opts.Metrics.ParsedFiles += len(dirs)
+	}
+	onError := opts.Diagnostics.FileErrorHandler(p.Name())
+	events := provider.ParseUsageEventsParallel(dirs, 0, func(dir string) ([]provider.UsageEvent, error) {
+		return parseSessionUsageEvents(dir, metas[filepath.Base(dir)], onError)
+	}, onError)
+	sortUsageEvents(events)
+	if opts.Metrics != nil {
+		opts.Metrics.EmittedEvents += len(events)
+	}
+	return events, nil
+}
+
+// filterMessageDirs drops session message directories whose newest message file
+// was written before the requested window.
+func filterMessageDirs(dirs []string, opts provider.UsageEventCollectOptions) []string {
+	if !opts.HasRange() {
+		if opts.Metrics != nil {
+			opts.Metrics.ConsideredFiles += len(dirs)
+		}
+		return dirs
+	}
+	filtered := make([]string, 0, len(dirs))
+	for _, dir := range dirs {
+		if opts.Metrics != nil {
+			opts.Metrics.ConsideredFiles++
+		}
+		modTime, ok := latestMessageModTime(dir)
+		if ok && opts.ShouldSkipFileByModTime(modTime) {
+			if opts.Metrics != nil {
+				opts.Metrics.SkippedFiles++
+			}
+			continue
+		}
+		filtered = append(filtered, dir)
+	}
+	return filtered
+}
+
+func latestMessageModTime(dir string) (time.Time, bool) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return time.Time{}, false
+	}
+	var latest time.Time
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return time.Time{}, false
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, !latest.IsZero()
+}
+
+func resolveStorageDir(baseDir string) (string, error) {
+	if baseDir != "" {
+		return baseDir, nil
+	}
+	if dataHome := strings.TrimSpace(os.Getenv("XDG_DATA_HOME")); dataHome != "" {
+		return filepath.Join(dataHome, "opencode", "storage"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "share", "opencode", "storage"), nil
+}
+
+// collectMessageDirs returns storage/message/<session-id> directories.
+func collectMessageDirs(storageDir string) ([]string, error) {
+	messageRoot := filepath.Join(storageDir, "message")
+	entries, err := os.ReadDir(messageRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	var dirs []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		dirs = append(dirs, filepath.Join(messageRoot, entry.Name()))
+	}
+	return dirs, nil
+}
+
+// loadSessionMetas indexes session titles and project paths by session ID.
+// Missing or malformed session and project files are ignored.
+func loadSessionMetas(storageDir string) map[string]sessionMeta {
+	projects := make(map[string]string)
+	projectPaths, _ := filepath.Glob(filepath.Join(storageDir, "project", "*.json"))
+	for _, path := range projectPaths {
+		var project projectFile
+		if err := readJSONFile(path, &project); err != nil {
+			continue
+		}
+		id := strings.TrimSpace(project.ID)
+		if id == "" {
+			id = strings.TrimSuffix(filepath.Base(path), ".json")
+		}
+		projects[id] = strings.TrimSpace(project.Worktree)
+	}
+
+	metas := make(map[string]sessionMeta)
+	sessionPaths, _ := filepath.Glob(filepath.Join(storageDir, "session", "*", "*.json"))
+	for _, path := range sessionPaths {
+		var session sessionFile
+		if err := readJSONFile(path, &session); err != nil {
+			continue
+		}
+		id := strings.TrimSpace(session.ID)
+		if id == "" {
+			id = strings.TrimSuffix(filepath.Base(path), ".json")
+		}
+		projectID := strings.TrimSpace(session.ProjectID)
+		if projectID == "" {
+			projectID = filepath.Base(filepath.Dir(path))
+		}
+		project := strings.TrimSpace(session.Directory)
+		if project == "" {
+			project = projects[projectID]
+		}
+		if project == "" {
+			project = projectID
+		}
+		metas[id] = sessionMeta{
+			Title:   strings.TrimSpace(session.Title),
+			Project: project,
+		}
+	}
+	return metas
+}
+
+// readMessages parses every message file in one session message directory.
+// Malformed message files are skipped and, when onError is set, reported to it.
+func readMessages(dir string, onError provider.ParseErrorFunc) ([]messageFile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []messageFile
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		var msg messageFile
+		path := filepath.Join(dir, entry.Name())
+		if err := readJSONFile(path, &msg); err != nil {
+			if onError != nil {
+				onError(path, err)
+			}
+			continue
+		}
+		if msg.ID == "" {
+			msg.ID = strings.TrimSuffix(entry.Name(), ".json")
+		}
+		messages = append(messages, msg)
+	}
+	return messages, nil
+}
+
+// parseSession folds one session message directory into a SessionInfo.
+func parseSession(dir string, meta sessionMeta) (provider.SessionInfo, error) {
+	messages, err := readMessages(dir, nil)
+	if err != nil {
+		return provider.SessionInfo{}, err
+	}
+
+	info := provider.SessionInfo{
+		ProviderName: "opencode",
+		SessionID:    filepath.Base(dir),
+		Title:        meta.Title,
+		WorkDirHash:  meta.Project,
+	}
+	for _, msg := range messages {
+		ts := messageTimestamp(msg)
+		if !ts.IsZero() {
+			if info.StartTime.IsZero() || ts.Before(info.StartTime) {
+				info.StartTime = ts
+			}
+			if ts.After(info.EndTime) {
+				info.EndTime = ts
+			}
+		}
+		switch msg.Role {
+		case "user":
+			info.Turns++
+		case "assistant":
+			if info.ModelName == "" {
+				info.ModelName = strings.TrimSpace(msg.ModelID)
+			}
+			if info.WorkDirHash == "" {
+				info.WorkDirHash = messageProject(msg)
+			}
+			if usage, ok := tokenUsageFromMessage(msg); ok {
+				info.TokenUsage.InputOther += usage.InputOther
+				info.TokenUsage.Output += usage.Output
+				info.TokenUsage.OutputReasoning += usage.OutputReasoning
+				info.TokenUsage.InputCacheRead += usage.InputCacheRead
+				info.TokenUsage.InputCacheCreate += usage.InputCacheCreate
+			}
+		}
+	}
+	info.ProjectPath = projectPath(info.WorkDirHash)
+	return info, nil
+}
+
+// parseSessionUsageEvents returns one usage event per assistant message with token usage.
+func parseSessionUsageEvents(dir string, meta sessionMeta, onError provider.ParseErrorFunc) ([]provider.UsageEvent, error) {
+	messages, err := readMessages(dir, onError)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionID := filepath.Base(dir)
+	var events []provider.UsageEvent
+	for _, msg := range messages {
+		if msg.Role != "assistant" {
+			continue
+		}
+		usage, ok := tokenUsageFromMessage(msg)
+		if !ok {
+			continue
+		}
+		ts := messageTimestamp(msg)
+		if ts.IsZero() {
+			continue
+		}
+		project := meta.Project
+		if project == "" {
+			project = messageProject(msg)
+		}
+		events = append(events, provider.UsageEvent{
+			ProviderName: "opencode",
+			ModelName:    strings.TrimSpace(msg.ModelID),
+			SessionID:    sessionID,
+			Title:        meta.Title,
+			WorkDirHash:  project,
+			ProjectPath:  projectPath(project),
+			Timestamp:    ts,
+			TokenUsage:   usage,
+			SourcePath:   filepath.Join(dir, msg.ID+".json"),
+			EventID:      msg.ID,
+		})
+	}
+	return events, nil
+}
+
+// tokenUsageFromMessage maps OpenCode token counts into codetok fields.
+// OpenCode reports reasoning separately from output, so both count as output.
+func tokenUsageFromMessage(msg messageFile) (provider.TokenUsage, bool) {
+	if msg.Tokens == nil {
+		return provider.TokenUsage{}, false
+	}
+	usage := provider.TokenUsage{
+		InputOther:       msg.Tokens.Input,
+		Output:           msg.Tokens.Output + msg.Tokens.Reasoning,
+		OutputReasoning:  msg.Tokens.Reasoning,
+		InputCacheRead:   msg.Tokens.Cache.Read,
+		InputCacheCreate: msg.Tokens.Cache.Write,
+	}
+	if usage.Total() == 0 {
+		return provider.TokenUsage{}, false
+	}
+	return usage, true
+}
+
+// messageTimestamp prefers the completion time, since that is when usage is final.
+func messageTimestamp(msg messageFile) time.Time {
+	ms := msg.Time.Completed
+	if ms <= 0 {
+		ms = msg.Time.Created
+	}
+	if ms <= 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
+
+// projectPath returns project when it is a directory rather than a bare project ID.
+func projectPath(project string) string {
+	if filepath.IsAbs(project) {
+		return project
+	}
+	return ""
+}
+
+func messageProject(msg messageFile) string {
+	if root := strings.TrimSpace(msg.Path.Root); root != "" && root != "/" {
+		return root
+	}
+	return strings.TrimSpace(msg.Path.Cwd)
+}
+
+func readJSONFile(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func sortUsageEvents(events []provider.UsageEvent) {
+	sort.Slice(events, func(i, j int) bool {
+		if !events[i].Timestamp.Equal(events[j].Timestamp) {
+			return events[i].Timestamp.Before(events[j].Timestamp)
+		}
+		if events[i].SessionID != events[j].SessionID {
+			return events[i].SessionID < events[j].SessionID
+		}
+		return events[i].EventID < events[j].EventID
+	})
+}
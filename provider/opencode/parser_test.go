@@ -0,0 +1,187 @@
+package opencode
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/miss-you/codetok/provider"
+)
+
+func testStorageDir(t *testing.T) string {
+	t.Helper()
+	dir, err := filepath.Abs(filepath.Join("testdata", "storage"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestCollectUsageEvents_EmitsOneEventPerAssistantMessage(t *testing.T) {
+	events, err := (&Provider{}).CollectUsageEvents(testStorageDir(t))
+	if err != nil {
+		t.Fatalf("CollectUsageEvents returned error: %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("got %d events, want 3 (zero-token message skipped)", len(events))
+	}
+
+	first := events[0]
+	if first.ProviderName != "opencode" {
+		t.Errorf("ProviderName = %q, want opencode", first.ProviderName)
+	}
+	if first.SessionID != "ses_alpha" || first.EventID != "msg_002" {
+		t.Errorf("first event = %s/%s, want ses_alpha/msg_002", first.SessionID, first.EventID)
+	}
+	if first.ModelName != "claude-sonnet-4-5" {
+		t.Errorf("ModelName = %q, want claude-sonnet-4-5", first.ModelName)
+	}
+	if first.Title != "Refactor parser" {
+		t.Errorf("Title = %q, want Refactor parser", first.Title)
+	}
+	if first.WorkDirHash != "/home/dev/project-a" {
+		t.Errorf("WorkDirHash = %q, want session directory", first.WorkDirHash)
+	}
+	wantTime := time.UnixMilli(1771149660000)
+	if !first.Timestamp.Equal(wantTime) {
+		t.Errorf("Timestamp = %v, want completion time %v", first.Timestamp, wantTime)
+	}
+	want := provider.TokenUsage{InputOther: 100, Output: 50, OutputReasoning: 10, InputCacheRead: 500, InputCacheCreate: 50}
+	if first.TokenUsage != want {
+		t.Errorf("TokenUsage = %+v, want %+v", first.TokenUsage, want)
+	}
+	if filepath.Base(first.SourcePath) != "msg_002.json" {
+		t.Errorf("SourcePath = %q, want message file", first.SourcePath)
+	}
+
+	last := events[2]
+	if last.SessionID != "ses_beta" || last.ModelName != "gpt-5" {
+		t.Errorf("last event = %s/%s, want ses_beta/gpt-5", last.SessionID, last.ModelName)
+	}
+	if last.WorkDirHash != "/home/dev/project-a" {
+		t.Errorf("WorkDirHash = %q, want project worktree fallback", last.WorkDirHash)
+	}
+	if !last.Timestamp.Equal(time.UnixMilli(1771236001000)) {
+		t.Errorf("Timestamp = %v, want created time fallback", last.Timestamp)
+	}
+}
+
+func TestCollectSessions_AggregatesMessages(t *testing.T) {
+	sessions, err := (&Provider{}).CollectSessions(testStorageDir(t))
+	if err != nil {
+		t.Fatalf("CollectSessions returned error: %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("got %d sessions, want 2", len(sessions))
+	}
+
+	byID := make(map[string]provider.SessionInfo)
+	for _, s := range sessions {
+		byID[s.SessionID] = s
+	}
+	alpha, ok := byID["ses_alpha"]
+	if !ok {
+		t.Fatalf("missing ses_alpha in %+v", sessions)
+	}
+	if alpha.Turns != 2 {
+		t.Errorf("Turns = %d, want 2", alpha.Turns)
+	}
+	if alpha.TokenUsage.Total() != 1760 {
+		t.Errorf("Total = %d, want 1760", alpha.TokenUsage.Total())
+	}
+	if alpha.Title != "Refactor parser" || alpha.ModelName != "claude-sonnet-4-5" {
+		t.Errorf("session = %+v, want title and model", alpha)
+	}
+	if !alpha.EndTime.After(alpha.StartTime) {
+		t.Errorf("EndTime %v should be after StartTime %v", alpha.EndTime, alpha.StartTime)
+	}
+}
+
+func TestCollectUsageEvents_MissingStorageReturnsNotExist(t *testing.T) {
+	_, err := (&Provider{}).CollectUsageEvents(filepath.Join(t.TempDir(), "missing"))
+	if !os.IsNotExist(err) {
+		t.Fatalf("err = %v, want not-exist error", err)
+	}
+}
+
+func TestCollectUsageEvents_SkipsMalformedMessageFiles(t *testing.T) {
+	storageDir := t.TempDir()
+	messageDir := filepath.Join(storageDir, "message", "ses_bad")
+	if err := os.MkdirAll(messageDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(messageDir, "msg_1.json"), []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	valid := `{"id":"msg_2","sessionID":"ses_bad","role":"assistant","time":{"created":1771149600000},"modelID":"gpt-5","tokens":{"input":10,"output":5,"reasoning":0,"cache":{"read":0,"write":0}}}`
+	if err := os.WriteFile(filepath.Join(messageDir, "msg_2.json"), []byte(valid), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := (&Provider{}).CollectUsageEvents(storageDir)
+	if err != nil {
+		t.Fatalf("CollectUsageEvents returned error: %v", err)
+	}
+	if len(events) != 1 || events[0].EventID != "msg_2" {
+		t.Fatalf("events = %+v, want only msg_2", events)
+	}
+	if events[0].Title != "" {
+		t.Fatalf("Title = %q, want empty without session file", events[0].Title)
+	}
+}
+
+func TestCollectUsageEventsInRange_SkipsInactiveSessionsByModTime(t *testing.T) {
+	storageDir := t.TempDir()
+	oldDir := writeOpenCodeMessage(t, storageDir, "ses_old", "msg_1", time.Date(2026, 4, 1, 10, 0, 0, 0, time.UTC))
+	activeDir := writeOpenCodeMessage(t, storageDir, "ses_active", "msg_2", time.Date(2026, 4, 16, 10, 0, 0, 0, time.UTC))
+	setMessageDirModTime(t, oldDir, time.Date(2026, 4, 1, 11, 0, 0, 0, time.UTC))
+	setMessageDirModTime(t, activeDir, time.Date(2026, 4, 16, 11, 0, 0, 0, time.UTC))
+
+	var metrics provider.UsageEventCollectMetrics
+	events, err := (&Provider{}).CollectUsageEventsInRange(storageDir, provider.UsageEventCollectOptions{
+		Since:    time.Date(2026, 4, 16, 0, 0, 0, 0, time.UTC),
+		Location: time.UTC,
+		Metrics:  &metrics,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 1 || events[0].SessionID != "ses_active" {
+		t.Fatalf("events = %#v, want only ses_active", events)
+	}
+	if metrics.ConsideredFiles != 2 || metrics.SkippedFiles != 1 || metrics.ParsedFiles != 1 || metrics.EmittedEvents != 1 {
+		t.Fatalf("metrics = %+v, want considered=2 skipped=1 parsed=1 emitted=1", metrics)
+	}
+}
+
+func writeOpenCodeMessage(t *testing.T, storageDir, sessionID, messageID string, created time.Time) string {
+	t.Helper()
+	dir := filepath.Join(storageDir, "message", sessionID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := fmt.Sprintf(`{"id":"%s","sessionID":"%s","role":"assistant","time":{"created":%d},"modelID":"gpt-5","tokens":{"input":10,"output":5,"reasoning":0,"cache":{"read":0,"write":0}}}`,
+		messageID,
+		sessionID,
+		created.UnixMilli(),
+	)
+	if err := os.WriteFile(filepath.Join(dir, messageID+".json"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func setMessageDirModTime(t *testing.T, dir string, modTime time.Time) {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if err := os.Chtimes(filepath.Join(dir, entry.Name()), modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -15,8 +15,9 @@ type UsageEventParseFunc func(path string) ([]UsageEvent, error)
 
 // ParseParallel parses multiple items in parallel with bounded concurrency.
 // maxWorkers <= 0 means use default (from CODETOK_WORKERS env or runtime.NumCPU()).
-// Items that return errors are silently skipped.
-func ParseParallel[T any](items []string, maxWorkers int, parseFn ParseFunc[T]) []T {
+// Items that return errors are skipped and, when onError is set, reported to it;
+// onError may be called concurrently.
+func ParseParallel[T any](items []string, maxWorkers int, parseFn ParseFunc[T], onError ParseErrorFunc) []T {
 	if maxWorkers <= 0 {
 		maxWorkers = defaultWorkers()
 	}
@@ -40,7 +41,10 @@ func ParseParallel[T any](items []string, maxWorkers int, parseFn ParseFunc[T])
 			defer func() { <-sem }()
 			info, err := parseFn(path)
 			if err != nil {
-				return // skip failed items
+				if onError != nil {
+					onError(path, err)
+				}
+				return
 			}
 			mu.Lock()
 			results = append(results, info)
@@ -53,8 +57,9 @@ func ParseParallel[T any](items []string, maxWorkers int, parseFn ParseFunc[T])
 
 // ParseUsageEventsParallel parses multiple items into usage events with bounded concurrency.
 // maxWorkers <= 0 means use default (from CODETOK_WORKERS env or runtime.NumCPU()).
-// Items that return errors are silently skipped.
-func ParseUsageEventsParallel(items []string, maxWorkers int, parseFn UsageEventParseFunc) []UsageEvent {
+// Items that return errors are skipped and, when onError is set, reported to it;
+// onError may be called concurrently.
+func ParseUsageEventsParallel(items []string, maxWorkers int, parseFn UsageEventParseFunc, onError ParseErrorFunc) []UsageEvent {
 	if maxWorkers <= 0 {
 		maxWorkers = defaultWorkers()
 	}
@@ -78,7 +83,10 @@ func ParseUsageEventsParallel(items []string, maxWorkers int, parseFn UsageEvent
 			defer func() { <-sem }()
 			events, err := parseFn(path)
 			if err != nil {
-				return // skip failed items
+				if onError != nil {
+					onError(path, err)
+				}
+				return
 			}
 			mu.Lock()
 			results = append(results, events...)
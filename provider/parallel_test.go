@@ -20,7 +20,7 @@ func TestParseParallel_BasicConcurrency(t *testing.T) {
 		}, nil
 	}
 
-	results := ParseParallel(items, 4, parseFn)
+	results := ParseParallel(items, 4, parseFn, nil)
 	if len(results) != 20 {
 		t.Errorf("expected 20 results, got %d", len(results))
 	}
@@ -47,7 +47,7 @@ func TestParseParallel_ErrorSkipped(t *testing.T) {
 		return SessionInfo{SessionID: path}, nil
 	}
 
-	results := ParseParallel(items, 2, parseFn)
+	results := ParseParallel(items, 2, parseFn, nil)
 	if len(results) != 3 {
 		t.Errorf("expected 3 results (errors skipped), got %d", len(results))
 	}
@@ -67,7 +67,7 @@ func TestParseParallel_GenericResultType(t *testing.T) {
 	items := []string{"a", "b"}
 	results := ParseParallel(items, 2, func(path string) ([]UsageEvent, error) {
 		return []UsageEvent{{SessionID: path}}, nil
-	})
+	}, nil)
 
 	if len(results) != 2 {
 		t.Fatalf("got %d result batches, want 2", len(results))
@@ -91,7 +91,7 @@ func TestParseParallel_EmptyInput(t *testing.T) {
 	results := ParseParallel(nil, 4, func(path string) (SessionInfo, error) {
 		t.Error("parseFn should not be called for empty input")
 		return SessionInfo{}, nil
-	})
+	}, nil)
 	if results != nil {
 		t.Errorf("expected nil for empty input, got %v", results)
 	}
@@ -99,7 +99,7 @@ func TestParseParallel_EmptyInput(t *testing.T) {
 	results = ParseParallel([]string{}, 4, func(path string) (SessionInfo, error) {
 		t.Error("parseFn should not be called for empty input")
 		return SessionInfo{}, nil
-	})
+	}, nil)
 	if results != nil {
 		t.Errorf("expected nil for empty slice, got %v", results)
 	}
@@ -109,7 +109,7 @@ func TestParseUsageEventsParallel_EmptyInput(t *testing.T) {
 	results := ParseUsageEventsParallel(nil, 4, func(path string) ([]UsageEvent, error) {
 		t.Error("parseFn should not be called for empty input")
 		return nil, nil
-	})
+	}, nil)
 	if results != nil {
 		t.Errorf("expected nil for empty input, got %v", results)
 	}
@@ -117,7 +117,7 @@ func TestParseUsageEventsParallel_EmptyInput(t *testing.T) {
 	results = ParseUsageEventsParallel([]string{}, 4, func(path string) ([]UsageEvent, error) {
 		t.Error("parseFn should not be called for empty input")
 		return nil, nil
-	})
+	}, nil)
 	if results != nil {
 		t.Errorf("expected nil for empty slice, got %v", results)
 	}
@@ -133,7 +133,7 @@ func TestParseUsageEventsParallel_ErrorSkipped(t *testing.T) {
 		return []UsageEvent{{SessionID: path}}, nil
 	}
 
-	results := ParseUsageEventsParallel(items, 2, parseFn)
+	results := ParseUsageEventsParallel(items, 2, parseFn, nil)
 	if len(results) != 3 {
 		t.Errorf("expected 3 results (errors skipped), got %d", len(results))
 	}
@@ -149,6 +149,29 @@ func TestParseUsageEventsParallel_ErrorSkipped(t *testing.T) {
 	}
 }
 
+func TestParseUsageEventsParallel_ReportsErrors(t *testing.T) {
+	items := []string{"ok-1", "fail-1", "ok-2", "fail-2"}
+	var diagnostics Diagnostics
+
+	ParseUsageEventsParallel(items, 2, func(path string) ([]UsageEvent, error) {
+		if path[0:4] == "fail" {
+			return nil, fmt.Errorf("parse error for %s", path)
+		}
+		return []UsageEvent{{SessionID: path}}, nil
+	}, diagnostics.FileErrorHandler("test"))
+
+	issues := diagnostics.Issues()
+	if len(issues) != 2 || issues[0].Path != "fail-1" || issues[1].Path != "fail-2" {
+		t.Fatalf("issues = %#v, want both failed items", issues)
+	}
+	if issues[0].Provider != "test" || issues[0].Err == nil {
+		t.Errorf("issue = %#v, want provider and error recorded", issues[0])
+	}
+	if skipped, malformed := diagnostics.Counts(); skipped != 2 || malformed != 0 {
+		t.Errorf("Counts() = %d, %d; want 2, 0", skipped, malformed)
+	}
+}
+
 func TestParseParallel_WorkerLimit(t *testing.T) {
 	const maxWorkers = 3
 	const totalItems = 20
@@ -176,7 +199,7 @@ func TestParseParallel_WorkerLimit(t *testing.T) {
 		return SessionInfo{SessionID: path}, nil
 	}
 
-	results := ParseParallel(items, maxWorkers, parseFn)
+	results := ParseParallel(items, maxWorkers, parseFn, nil)
 	if len(results) != totalItems {
 		t.Errorf("expected %d results, got %d", totalItems, len(results))
 	}
@@ -215,7 +238,7 @@ func TestParseUsageEventsParallel_WorkerLimit(t *testing.T) {
 		return []UsageEvent{{SessionID: path}}, nil
 	}
 
-	results := ParseUsageEventsParallel(items, maxWorkers, parseFn)
+	results := ParseUsageEventsParallel(items, maxWorkers, parseFn, nil)
 	if len(results) != totalItems {
 		t.Errorf("expected %d results, got %d", totalItems, len(results))
 	}
@@ -232,7 +255,7 @@ func TestParseUsageEventsParallel_WorkerLimit(t *testing.T) {
 func TestParseParallel_SingleItem(t *testing.T) {
 	results := ParseParallel([]string{"only-one"}, 4, func(path string) (SessionInfo, error) {
 		return SessionInfo{SessionID: path}, nil
-	})
+	}, nil)
 	if len(results) != 1 {
 		t.Errorf("expected 1 result, got %d", len(results))
 	}
@@ -246,7 +269,7 @@ func TestParseParallel_DefaultWorkers(t *testing.T) {
 	items := []string{"a", "b", "c"}
 	results := ParseParallel(items, 0, func(path string) (SessionInfo, error) {
 		return SessionInfo{SessionID: path}, nil
-	})
+	}, nil)
 	if len(results) != 3 {
 		t.Errorf("expected 3 results with default workers, got %d", len(results))
 	}
@@ -1,11 +1,19 @@
 package provider
 
-import "time"
+import (
+	"encoding/json"
+	"math"
+	"sort"
+	"time"
+)
 
 // TokenUsage holds token counts from a session or aggregation.
+// OutputReasoning is the part of Output spent on hidden reasoning/thinking;
+// it is already counted in Output and therefore in Total.
 type TokenUsage struct {
 	InputOther       int `json:"input_other"`
 	Output           int `json:"output"`
+	OutputReasoning  int `json:"output_reasoning"`
 	InputCacheRead   int `json:"input_cache_read"`
 	InputCacheCreate int `json:"input_cache_creation"`
 }
@@ -27,36 +35,96 @@ type SessionInfo struct {
 	SessionID    string
 	Title        string
 	WorkDirHash  string
+	ProjectPath  string
+	Account      string
 	StartTime    time.Time
 	EndTime      time.Time
 	Turns        int
 	TokenUsage   TokenUsage
+	// Cost is filled in by reporting commands; providers leave it empty.
+	Cost CostEstimate
 }
 
 // UsageEvent represents a timestamped token usage delta from a provider log.
+// WorkDirHash is the provider's own project key (hash, slug, or path);
+// ProjectPath is the working directory it refers to, when the provider can resolve it.
+// Account names the provider account the usage belongs to, for providers that
+// track several (Cursor profiles); it is empty otherwise.
 type UsageEvent struct {
 	ProviderName string
 	ModelName    string
 	SessionID    string
 	Title        string
 	WorkDirHash  string
+	ProjectPath  string
+	Account      string
 	Timestamp    time.Time
 	TokenUsage   TokenUsage
 	SourcePath   string
 	EventID      string
 }
 
+// Cost estimate statuses.
+const (
+	CostStatusKnown   = "known"
+	CostStatusPartial = "partial"
+	CostStatusUnknown = "unknown"
+)
+
+// CostEstimate holds an estimated USD cost for some token usage.
+// Status is "known" when every contributing model had a price, "unknown" when
+// none did, and "partial" otherwise. USD only covers priced usage.
+type CostEstimate struct {
+	USD            float64  `json:"usd"`
+	Status         string   `json:"status"`
+	UnpricedModels []string `json:"unpriced_models,omitempty"`
+}
+
+// Add merges another estimate into c.
+func (c *CostEstimate) Add(other CostEstimate) {
+	c.USD += other.USD
+	switch {
+	case other.Status == "":
+	case c.Status == "":
+		c.Status = other.Status
+	case c.Status != other.Status:
+		c.Status = CostStatusPartial
+	}
+	for _, model := range other.UnpricedModels {
+		i := sort.SearchStrings(c.UnpricedModels, model)
+		if i < len(c.UnpricedModels) && c.UnpricedModels[i] == model {
+			continue
+		}
+		c.UnpricedModels = append(c.UnpricedModels, "")
+		copy(c.UnpricedModels[i+1:], c.UnpricedModels[i:])
+		c.UnpricedModels[i] = model
+	}
+}
+
+// MarshalJSON rounds USD to micro-dollars so float sums stay readable.
+func (c CostEstimate) MarshalJSON() ([]byte, error) {
+	type costEstimateJSON CostEstimate
+	out := costEstimateJSON(c)
+	out.USD = math.Round(out.USD*1e6) / 1e6
+	return json.Marshal(out)
+}
+
 // DailyStats represents aggregated token usage for a single day.
 type DailyStats struct {
 	Date string `json:"date"` // "2006-01-02"
 	// ProviderName always represents the CLI/provider dimension.
 	// It may be empty when a non-provider grouping spans multiple providers.
-	ProviderName string     `json:"provider"`
-	GroupBy      string     `json:"group_by"`
-	Group        string     `json:"group"`
-	Providers    []string   `json:"providers,omitempty"`
-	Sessions     int        `json:"sessions"`
-	TokenUsage   TokenUsage `json:"token_usage"`
+	ProviderName string `json:"provider"`
+	GroupBy      string `json:"group_by"`
+	Group        string `json:"group"`
+	// Groups maps each dimension of a composite GroupBy ("cli,model") to its
+	// value; Group joins the values with " / ". It is omitted for a single
+	// dimension, whose value is Group.
+	Groups     map[string]string `json:"groups,omitempty"`
+	Providers  []string          `json:"providers,omitempty"`
+	Sessions   int               `json:"sessions"`
+	TokenUsage TokenUsage        `json:"token_usage"`
+	Cost       CostEstimate      `json:"cost"`
 }
 
 // Provider defines the interface for collecting session data from a CLI tool.
@@ -79,6 +147,10 @@ type UsageEventCollectOptions struct {
 	Until    time.Time
 	Location *time.Location
 	Metrics  *UsageEventCollectMetrics
+	// Cache, when set, lets providers reuse events parsed by earlier runs.
+	Cache *UsageEventCache
+	// Diagnostics, when set, receives files and lines that providers skipped.
+	Diagnostics *Diagnostics
 }
 
 // UsageEventCollectMetrics records candidate filtering work for tests and benchmarks.
@@ -73,3 +73,40 @@ func TestDailyStatsJSON_MultiProviderGroup(t *testing.T) {
 		t.Fatalf("providers = %v, want [claude codex]", decoded.Providers)
 	}
 }
+
+func TestCostEstimateAdd_MergesStatusAndUnpricedModels(t *testing.T) {
+	var total CostEstimate
+	total.Add(CostEstimate{USD: 1.5, Status: CostStatusKnown})
+	if total.Status != CostStatusKnown {
+		t.Fatalf("Status = %q, want known", total.Status)
+	}
+
+	total.Add(CostEstimate{Status: CostStatusUnknown, UnpricedModels: []string{"zeta"}})
+	total.Add(CostEstimate{Status: CostStatusUnknown, UnpricedModels: []string{"alpha", "zeta"}})
+	if total.Status != CostStatusPartial {
+		t.Fatalf("Status = %q, want partial", total.Status)
+	}
+	if total.USD != 1.5 {
+		t.Fatalf("USD = %v, want 1.5", total.USD)
+	}
+	if strings.Join(total.UnpricedModels, ",") != "alpha,zeta" {
+		t.Fatalf("UnpricedModels = %v, want sorted unique [alpha zeta]", total.UnpricedModels)
+	}
+
+	var unknown CostEstimate
+	unknown.Add(CostEstimate{Status: CostStatusUnknown, UnpricedModels: []string{"m"}})
+	unknown.Add(CostEstimate{Status: CostStatusUnknown, UnpricedModels: []string{"m"}})
+	if unknown.Status != CostStatusUnknown {
+		t.Fatalf("Status = %q, want unknown when nothing is priced", unknown.Status)
+	}
+}
+
+func TestCostEstimateJSON_RoundsToMicroDollars(t *testing.T) {
+	b, err := json.Marshal(CostEstimate{USD: 0.1 + 0.2, Status: CostStatusKnown})
+	if err != nil {
+		t.Fatalf("marshal CostEstimate: %v", err)
+	}
+	if got := string(b); got != `{"usd":0.3,"status":"known"}` {
+		t.Fatalf("json = %s, want rounded usd without unpriced_models", got)
+	}
+}
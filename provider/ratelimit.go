@@ -0,0 +1,29 @@
+package provider
+
+import "time"
+
+// RateLimitWindow is the utilization of one rate-limit window as reported by
+// the provider. ResetsAt is nil when the provider did not say.
+type RateLimitWindow struct {
+	UsedPercent   float64    `json:"used_percent"`
+	WindowMinutes int        `json:"window_minutes,omitempty"`
+	ResetsAt      *time.Time `json:"resets_at,omitempty"`
+}
+
+// RateLimitSnapshot is the rate-limit state a provider logged at one point in
+// a session. Primary is the short window (5 hours for Codex), Secondary the
+// long one (weekly for Codex); either may be missing.
+type RateLimitSnapshot struct {
+	ProviderName string           `json:"provider"`
+	SessionID    string           `json:"session_id"`
+	Timestamp    time.Time        `json:"timestamp"`
+	Primary      *RateLimitWindow `json:"primary,omitempty"`
+	Secondary    *RateLimitWindow `json:"secondary,omitempty"`
+}
+
+// RateLimitProvider is implemented by providers whose logs record rate-limit
+// utilization. Snapshots outside opts' date window may be omitted.
+type RateLimitProvider interface {
+	Provider
+	CollectRateLimits(baseDir string, opts UsageEventCollectOptions) ([]RateLimitSnapshot, error)
+}
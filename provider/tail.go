@@ -0,0 +1,110 @@
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+)
+
+// JSONLSource is one append-only JSONL log together with the parser that reads
+// it incrementally.
+type JSONLSource struct {
+	Path      string
+	NewParser func() JSONLUsageEventParser
+	// Label, when set, fills session-level fields (session ID, title, model)
+	// that live outside the log into the parsed events.
+	Label func([]UsageEvent) []UsageEvent
+}
+
+// JSONLSourceProvider is implemented by providers that record usage in
+// append-only JSONL logs, so their sessions can be followed as they grow.
+type JSONLSourceProvider interface {
+	Provider
+	// JSONLSourceLister returns a function that lists the logs under baseDir.
+	// Indexes shared by all logs are loaded once, when the lister is created,
+	// so the function can be called again to discover new logs.
+	JSONLSourceLister(baseDir string) func() ([]JSONLSource, error)
+}
+
+// JSONLTail follows a JSONLSource, feeding only complete lines appended since
+// the previous poll to its parser.
+type JSONLTail struct {
+	source JSONLSource
+	parser JSONLUsageEventParser
+	offset int64
+	// skipped counts complete lines too long for the JSONL parsers.
+	skipped int
+}
+
+// NewJSONLTail returns a tail positioned at the start of source.
+func NewJSONLTail(source JSONLSource) *JSONLTail {
+	return &JSONLTail{source: source, parser: source.NewParser()}
+}
+
+// Path returns the followed log's path.
+func (t *JSONLTail) Path() string {
+	return t.source.Path
+}
+
+// Poll parses the complete lines appended since the last poll and reports
+// whether there were any. A line still being written is left for the next
+// poll, and a line of maxJSONLLineSize or more is skipped and counted in
+// SkippedLines. A log that shrank was rewritten and is parsed again from the
+// start.
+func (t *JSONLTail) Poll() (bool, error) {
+	f, err := os.Open(t.source.Path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return false, err
+	}
+	if info.Size() < t.offset {
+		t.parser = t.source.NewParser()
+		t.offset = 0
+	}
+	if info.Size() == t.offset {
+		return false, nil
+	}
+	if _, err := f.Seek(t.offset, io.SeekStart); err != nil {
+		return false, err
+	}
+
+	read := false
+	r := bufio.NewReaderSize(f, 64*1024)
+	for {
+		raw, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			return read, nil
+		}
+		if err != nil {
+			return read, err
+		}
+		t.offset += int64(len(raw))
+		line := bytes.TrimSuffix(bytes.TrimSuffix(raw, []byte("\n")), []byte("\r"))
+		if len(line) >= maxJSONLLineSize {
+			t.skipped++
+			continue
+		}
+		t.parser.ParseLine(line)
+		read = true
+	}
+}
+
+// SkippedLines returns how many lines were skipped for exceeding the maximum
+// JSONL line size.
+func (t *JSONLTail) SkippedLines() int {
+	return t.skipped
+}
+
+// Events returns the usage events parsed so far.
+func (t *JSONLTail) Events() []UsageEvent {
+	events := t.parser.Events()
+	if t.source.Label != nil {
+		events = t.source.Label(events)
+	}
+	return events
+}
@@ -0,0 +1,104 @@
+package provider
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJSONLTail_ParsesOnlyCompleteAppendedLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	modTime := time.Date(2026, 4, 16, 10, 0, 0, 0, time.UTC)
+	writeTestFile(t, path, "a\nb\npartial", modTime)
+
+	parsed := 0
+	tail := NewJSONLTail(JSONLSource{
+		Path: path,
+		NewParser: func() JSONLUsageEventParser {
+			return &lineParser{parsed: &parsed}
+		},
+		Label: func(events []UsageEvent) []UsageEvent {
+			for i := range events {
+				events[i].SessionID = "labeled"
+			}
+			return events
+		},
+	})
+
+	if read, err := tail.Poll(); err != nil || !read {
+		t.Fatalf("first poll = %v, %v; want lines read", read, err)
+	}
+	if got := eventIDs(tail.Events()); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Fatalf("events after first poll = %v, want the complete lines only", got)
+	}
+	if read, err := tail.Poll(); err != nil || read {
+		t.Fatalf("poll without new complete lines = %v, %v; want nothing read", read, err)
+	}
+
+	appendTestFile(t, path, "-line\nc\n", modTime.Add(time.Second))
+	if _, err := tail.Poll(); err != nil {
+		t.Fatalf("poll after append: %v", err)
+	}
+	events := tail.Events()
+	if got := eventIDs(events); !reflect.DeepEqual(got, []string{"a", "b", "partial-line", "c"}) {
+		t.Fatalf("events after append = %v", got)
+	}
+	if events[0].SessionID != "labeled" {
+		t.Fatalf("event = %+v, want Label applied", events[0])
+	}
+	if parsed != 4 {
+		t.Fatalf("ParseLine calls = %d, want each line parsed once", parsed)
+	}
+}
+
+func TestJSONLTail_RereadsRewrittenLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	modTime := time.Date(2026, 4, 16, 10, 0, 0, 0, time.UTC)
+	writeTestFile(t, path, "first\nsecond\n", modTime)
+
+	parsed := 0
+	tail := NewJSONLTail(JSONLSource{Path: path, NewParser: func() JSONLUsageEventParser {
+		return &lineParser{parsed: &parsed}
+	}})
+	if _, err := tail.Poll(); err != nil {
+		t.Fatalf("first poll: %v", err)
+	}
+
+	writeTestFile(t, path, "new\n", modTime.Add(time.Minute))
+	if read, err := tail.Poll(); err != nil || !read {
+		t.Fatalf("poll after rewrite = %v, %v; want lines read", read, err)
+	}
+	if got := eventIDs(tail.Events()); !reflect.DeepEqual(got, []string{"new"}) {
+		t.Fatalf("events after rewrite = %v, want only the new content", got)
+	}
+}
+
+func TestJSONLTail_SkipsOversizedLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	modTime := time.Date(2026, 4, 16, 10, 0, 0, 0, time.UTC)
+	writeTestFile(t, path, "a\n"+strings.Repeat("x", maxJSONLLineSize)+"\nb\n", modTime)
+
+	parsed := 0
+	tail := NewJSONLTail(JSONLSource{Path: path, NewParser: func() JSONLUsageEventParser {
+		return &lineParser{parsed: &parsed}
+	}})
+	if read, err := tail.Poll(); err != nil || !read {
+		t.Fatalf("poll = %v, %v; want the lines around the oversized one read", read, err)
+	}
+	if got := eventIDs(tail.Events()); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Fatalf("events = %v, want the oversized line skipped", got)
+	}
+	if tail.SkippedLines() != 1 {
+		t.Fatalf("SkippedLines() = %d, want 1", tail.SkippedLines())
+	}
+
+	appendTestFile(t, path, "c\n", modTime.Add(time.Second))
+	if _, err := tail.Poll(); err != nil {
+		t.Fatalf("poll after append: %v", err)
+	}
+	if got := eventIDs(tail.Events()); !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+		t.Fatalf("events after append = %v, want the log still followed", got)
+	}
+}
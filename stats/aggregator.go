@@ -16,6 +16,10 @@ const (
 	AggregateDimensionCLI AggregateDimension = "cli"
 	// AggregateDimensionModel groups by model name.
 	AggregateDimensionModel AggregateDimension = "model"
+	// AggregateDimensionProject groups by project path, falling back to the provider's project key.
+	AggregateDimensionProject AggregateDimension = "project"
+	// AggregateDimensionAccount groups by provider account, such as a Cursor profile.
+	AggregateDimensionAccount AggregateDimension = "account"
 )
 
 // AggregateByDay groups sessions by date and CLI provider (backward-compatible default).
@@ -47,7 +51,9 @@ func AggregateByDayWithDimension(sessions []provider.SessionInfo, dimension Aggr
 		if !s.StartTime.IsZero() {
 			date = s.StartTime.Format("2006-01-02")
 		}
-		group := groupNameForDimension(s, dimension)
+		group, values := compositeGroup(dimension, func(d AggregateDimension) string {
+			return groupNameForDimension(s, d)
+		})
 		key := dayKey{date: date, group: group}
 		agg, ok := dayMap[key]
 		if !ok {
@@ -56,6 +62,7 @@ func AggregateByDayWithDimension(sessions []provider.SessionInfo, dimension Aggr
 					Date:    date,
 					GroupBy: string(dimension),
 					Group:   group,
+					Groups:  compositeGroups(dimension, values),
 				},
 				providers: make(map[string]struct{}),
 			}
@@ -67,6 +74,7 @@ func AggregateByDayWithDimension(sessions []provider.SessionInfo, dimension Aggr
 		agg.stats.Sessions++
 		agg.stats.TokenUsage.InputOther += s.TokenUsage.InputOther
 		agg.stats.TokenUsage.Output += s.TokenUsage.Output
+		agg.stats.TokenUsage.OutputReasoning += s.TokenUsage.OutputReasoning
 		agg.stats.TokenUsage.InputCacheRead += s.TokenUsage.InputCacheRead
 		agg.stats.TokenUsage.InputCacheCreate += s.TokenUsage.InputCacheCreate
 	}
@@ -104,10 +112,52 @@ func AggregateByDayWithDimension(sessions []provider.SessionInfo, dimension Aggr
 	return result
 }
 
+// CompositeDimension joins dimensions into one composite dimension that groups
+// by the tuple of their values (e.g. "cli,model").
+func CompositeDimension(dimensions ...AggregateDimension) AggregateDimension {
+	parts := make([]string, 0, len(dimensions))
+	for _, d := range dimensions {
+		parts = append(parts, string(d))
+	}
+	return normalizeAggregateDimension(AggregateDimension(strings.Join(parts, ",")))
+}
+
+// Components returns the single dimensions that make up d, in order.
+// A non-composite dimension returns itself.
+func (d AggregateDimension) Components() []AggregateDimension {
+	parts := strings.Split(string(normalizeAggregateDimension(d)), ",")
+	components := make([]AggregateDimension, 0, len(parts))
+	for _, part := range parts {
+		components = append(components, AggregateDimension(part))
+	}
+	return components
+}
+
 func normalizeAggregateDimension(dimension AggregateDimension) AggregateDimension {
+	if !strings.Contains(string(dimension), ",") {
+		return normalizeSingleAggregateDimension(dimension)
+	}
+	seen := make(map[AggregateDimension]struct{})
+	var parts []string
+	for _, part := range strings.Split(string(dimension), ",") {
+		d := normalizeSingleAggregateDimension(AggregateDimension(strings.TrimSpace(part)))
+		if _, ok := seen[d]; ok {
+			continue
+		}
+		seen[d] = struct{}{}
+		parts = append(parts, string(d))
+	}
+	return AggregateDimension(strings.Join(parts, ","))
+}
+
+func normalizeSingleAggregateDimension(dimension AggregateDimension) AggregateDimension {
 	switch dimension {
 	case AggregateDimensionModel:
 		return AggregateDimensionModel
+	case AggregateDimensionProject:
+		return AggregateDimensionProject
+	case AggregateDimensionAccount:
+		return AggregateDimensionAccount
 	case AggregateDimensionCLI, "":
 		return AggregateDimensionCLI
 	default:
@@ -116,10 +166,43 @@ func normalizeAggregateDimension(dimension AggregateDimension) AggregateDimensio
 	}
 }
 
+// compositeGroup builds the group label for a normalized dimension. Labels of
+// composite dimensions join their values with " / "; the values are also
+// returned for compositeGroups. A single dimension returns no values.
+func compositeGroup(dimension AggregateDimension, valueFor func(AggregateDimension) string) (string, []string) {
+	if !strings.Contains(string(dimension), ",") {
+		return valueFor(dimension), nil
+	}
+	components := strings.Split(string(dimension), ",")
+	values := make([]string, len(components))
+	for i, d := range components {
+		values[i] = valueFor(AggregateDimension(d))
+	}
+	return strings.Join(values, " / "), values
+}
+
+// compositeGroups maps each component of a composite dimension to the value
+// compositeGroup returned for it. It is nil for a single dimension, whose value
+// is the group label itself.
+func compositeGroups(dimension AggregateDimension, values []string) map[string]string {
+	if len(values) == 0 {
+		return nil
+	}
+	groups := make(map[string]string, len(values))
+	for i, d := range strings.Split(string(dimension), ",") {
+		groups[d] = values[i]
+	}
+	return groups
+}
+
 func groupNameForDimension(s provider.SessionInfo, dimension AggregateDimension) string {
 	switch dimension {
 	case AggregateDimensionModel:
 		return normalizeModelName(s.ModelName, s.ProviderName)
+	case AggregateDimensionProject:
+		return ProjectName(s.ProjectPath, s.WorkDirHash, s.ProviderName)
+	case AggregateDimensionAccount:
+		return AccountName(s.Account, s.ProviderName)
 	case AggregateDimensionCLI, "":
 		return s.ProviderName
 	default:
@@ -140,6 +223,35 @@ func sortedProviderNames(providerSet map[string]struct{}) []string {
 	return names
 }
 
+// ProjectName returns the project group label: the project path when known,
+// else the provider's project key, else "unknown (<provider>)".
+func ProjectName(projectPath, workDirHash, providerName string) string {
+	if projectPath = strings.TrimSpace(projectPath); projectPath != "" {
+		return projectPath
+	}
+	if workDirHash = strings.TrimSpace(workDirHash); workDirHash != "" {
+		return workDirHash
+	}
+	providerName = strings.TrimSpace(providerName)
+	if providerName == "" {
+		providerName = "unknown"
+	}
+	return "unknown (" + providerName + ")"
+}
+
+// AccountName returns the account group label: the account when known, else
+// "unknown (<provider>)" for providers without account separation.
+func AccountName(account, providerName string) string {
+	if account = strings.TrimSpace(account); account != "" {
+		return account
+	}
+	providerName = strings.TrimSpace(providerName)
+	if providerName == "" {
+		providerName = "unknown"
+	}
+	return "unknown (" + providerName + ")"
+}
+
 func normalizeModelName(name, providerName string) string {
 	name = strings.TrimSpace(name)
 	if name == "" {
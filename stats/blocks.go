@@ -0,0 +1,122 @@
+package stats
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/miss-you/codetok/pricing"
+	"github.com/miss-you/codetok/provider"
+)
+
+// DefaultBlockWindow matches the rolling usage window of Claude and Codex
+// subscriptions.
+const DefaultBlockWindow = 5 * time.Hour
+
+// UsageBlock is one provider's usage within a single rate-limit window.
+type UsageBlock struct {
+	ProviderName  string                `json:"provider"`
+	Start         time.Time             `json:"start"`
+	End           time.Time             `json:"end"`
+	FirstActivity time.Time             `json:"first_activity"`
+	LastActivity  time.Time             `json:"last_activity"`
+	Models        []string              `json:"models"`
+	Sessions      int                   `json:"sessions"`
+	Events        int                   `json:"events"`
+	TokenUsage    provider.TokenUsage   `json:"token_usage"`
+	Cost          provider.CostEstimate `json:"cost"`
+	// Active is set for the block whose window contains now. Only active blocks
+	// carry a burn rate and projection.
+	Active          bool                   `json:"active"`
+	TokensPerMinute float64                `json:"tokens_per_minute,omitempty"`
+	ProjectedTokens int                    `json:"projected_tokens,omitempty"`
+	ProjectedCost   *provider.CostEstimate `json:"projected_cost,omitempty"`
+}
+
+// BuildUsageBlocks splits each provider's events into windows of the given
+// length. A block starts at the hour of the first event after the previous
+// block ended, the way subscription windows open on the first message. Blocks
+// are sorted by start time, then provider.
+//
+// For the block active at now, the burn rate is its tokens per minute since
+// its first activity, and the projection extends that rate to the block end.
+func BuildUsageBlocks(events []provider.UsageEvent, window time.Duration, now time.Time, prices *pricing.Table) []UsageBlock {
+	if window <= 0 {
+		window = DefaultBlockWindow
+	}
+	sorted := append([]provider.UsageEvent(nil), events...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	type blockState struct {
+		block    UsageBlock
+		models   map[string]struct{}
+		sessions map[string]struct{}
+	}
+	var blocks []*blockState
+	current := make(map[string]*blockState)
+	for _, e := range sorted {
+		providerName := normalizedEventProviderName(e)
+		state := current[providerName]
+		if state == nil || !e.Timestamp.Before(state.block.End) {
+			start := e.Timestamp.Truncate(time.Hour)
+			state = &blockState{
+				block: UsageBlock{
+					ProviderName:  providerName,
+					Start:         start,
+					End:           start.Add(window),
+					FirstActivity: e.Timestamp,
+				},
+				models:   make(map[string]struct{}),
+				sessions: make(map[string]struct{}),
+			}
+			current[providerName] = state
+			blocks = append(blocks, state)
+		}
+		state.block.LastActivity = e.Timestamp
+		state.block.Events++
+		state.models[normalizeModelName(e.ModelName, e.ProviderName)] = struct{}{}
+		state.sessions[eventSessionKey(e)] = struct{}{}
+		addTokenUsage(&state.block.TokenUsage, e.TokenUsage)
+		state.block.Cost.Add(EstimateEventCost(prices, e))
+	}
+
+	result := make([]UsageBlock, 0, len(blocks))
+	for _, state := range blocks {
+		block := state.block
+		block.Models = make([]string, 0, len(state.models))
+		for model := range state.models {
+			block.Models = append(block.Models, model)
+		}
+		sort.Strings(block.Models)
+		block.Sessions = len(state.sessions)
+		if !now.Before(block.Start) && now.Before(block.End) {
+			projectBlock(&block, now)
+		}
+		result = append(result, block)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if !result[i].Start.Equal(result[j].Start) {
+			return result[i].Start.Before(result[j].Start)
+		}
+		return result[i].ProviderName < result[j].ProviderName
+	})
+	return result
+}
+
+// projectBlock marks block active and fills its burn rate and projection.
+func projectBlock(block *UsageBlock, now time.Time) {
+	block.Active = true
+	tokens := block.TokenUsage.Total()
+	// Count at least a minute so a block that just opened does not report a
+	// huge rate.
+	elapsed := math.Max(now.Sub(block.FirstActivity).Minutes(), 1)
+	block.TokensPerMinute = float64(tokens) / elapsed
+	block.ProjectedTokens = tokens + int(math.Round(block.TokensPerMinute*block.End.Sub(now).Minutes()))
+	if tokens > 0 && block.Cost.Status != "" && block.Cost.Status != provider.CostStatusUnknown {
+		projected := block.Cost
+		projected.USD = block.Cost.USD * float64(block.ProjectedTokens) / float64(tokens)
+		block.ProjectedCost = &projected
+	}
+}
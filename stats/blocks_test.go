@@ -0,0 +1,71 @@
+package stats
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/miss-you/codetok/provider"
+)
+
+func TestBuildUsageBlocks_SplitsWindowsPerProvider(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2026, 4, 16, hour, minute, 0, 0, time.UTC)
+	}
+	events := []provider.UsageEvent{
+		{ProviderName: "claude", ModelName: "claude-sonnet-4-5", SessionID: "a", Timestamp: at(9, 40), TokenUsage: provider.TokenUsage{InputOther: 100}},
+		{ProviderName: "codex", ModelName: "gpt-5.4", SessionID: "x", Timestamp: at(10, 5), TokenUsage: provider.TokenUsage{InputOther: 7}},
+		{ProviderName: "claude", ModelName: "claude-opus-4-1", SessionID: "b", Timestamp: at(13, 59), TokenUsage: provider.TokenUsage{Output: 50}},
+		// 14:00 is the end of the 09:00 block, so a new claude block opens at 14:00.
+		{ProviderName: "claude", ModelName: "claude-sonnet-4-5", SessionID: "b", Timestamp: at(14, 0), TokenUsage: provider.TokenUsage{InputOther: 10}},
+		{ProviderName: "claude", ModelName: "claude-sonnet-4-5", SessionID: "b", Timestamp: at(20, 30), TokenUsage: provider.TokenUsage{InputOther: 1}},
+	}
+
+	blocks := BuildUsageBlocks(events, 5*time.Hour, at(23, 0), nil)
+	if len(blocks) != 4 {
+		t.Fatalf("blocks = %+v, want 4", blocks)
+	}
+	first := blocks[0]
+	if first.ProviderName != "claude" || !first.Start.Equal(at(9, 0)) || !first.End.Equal(at(14, 0)) || !first.LastActivity.Equal(at(13, 59)) {
+		t.Fatalf("first block = %+v, want claude 09:00-14:00", first)
+	}
+	if first.Events != 2 || first.Sessions != 2 || first.TokenUsage.Total() != 150 ||
+		!reflect.DeepEqual(first.Models, []string{"claude-opus-4-1", "claude-sonnet-4-5"}) {
+		t.Fatalf("first block totals = %+v", first)
+	}
+	if blocks[1].ProviderName != "codex" || !blocks[1].Start.Equal(at(10, 0)) {
+		t.Fatalf("second block = %+v, want codex in its own window", blocks[1])
+	}
+	if !blocks[2].Start.Equal(at(14, 0)) || blocks[2].TokenUsage.Total() != 10 {
+		t.Fatalf("third block = %+v, want the 14:00 claude block", blocks[2])
+	}
+	if !blocks[3].Start.Equal(at(20, 0)) || !blocks[3].Active {
+		t.Fatalf("last block = %+v, want the active 20:00 block", blocks[3])
+	}
+	for _, block := range blocks[:3] {
+		if block.Active || block.TokensPerMinute != 0 || block.ProjectedTokens != 0 {
+			t.Fatalf("finished block %+v, want no burn rate", block)
+		}
+	}
+}
+
+func TestBuildUsageBlocks_ProjectsActiveBlock(t *testing.T) {
+	start := time.Date(2026, 4, 16, 9, 0, 0, 0, time.UTC)
+	events := []provider.UsageEvent{
+		{ProviderName: "claude", ModelName: "claude-sonnet-4-5", SessionID: "a", Timestamp: start.Add(30 * time.Minute), TokenUsage: provider.TokenUsage{InputOther: 3000}},
+		{ProviderName: "claude", ModelName: "claude-sonnet-4-5", SessionID: "a", Timestamp: start.Add(50 * time.Minute), TokenUsage: provider.TokenUsage{Output: 3000}},
+	}
+
+	// 60 minutes after the first activity, with 3.5 hours left in the window.
+	blocks := BuildUsageBlocks(events, 5*time.Hour, start.Add(90*time.Minute), nil)
+	if len(blocks) != 1 {
+		t.Fatalf("blocks = %+v", blocks)
+	}
+	block := blocks[0]
+	if !block.Active || block.TokensPerMinute != 100 || block.ProjectedTokens != 6000+210*100 {
+		t.Fatalf("active block = %+v, want 100 tok/min projected to %d", block, 6000+210*100)
+	}
+	if block.ProjectedCost != nil {
+		t.Fatalf("projected cost = %+v, want none without pricing", block.ProjectedCost)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/miss-you/codetok/pricing"
 	"github.com/miss-you/codetok/provider"
 )
 
@@ -20,9 +21,13 @@ type dailyEventAggregate struct {
 }
 
 // DailyEventAggregator incrementally groups usage events by localized event date and dimension.
+// SetPeriod widens the date key to weeks or months.
 type DailyEventAggregator struct {
 	dimension AggregateDimension
 	loc       *time.Location
+	bucket    PeriodBucket
+	weekStart time.Weekday
+	pricing   *pricing.Table
 	dayMap    map[dailyEventKey]*dailyEventAggregate
 }
 
@@ -31,10 +36,30 @@ func NewDailyEventAggregator(dimension AggregateDimension, loc *time.Location) *
 	return &DailyEventAggregator{
 		dimension: normalizeAggregateDimension(dimension),
 		loc:       normalizeEventLocation(loc),
+		bucket:    PeriodDay,
+		weekStart: time.Monday,
 		dayMap:    make(map[dailyEventKey]*dailyEventAggregate),
 	}
 }
 
+// SetPricing enables cost estimation for events added after the call.
+func (a *DailyEventAggregator) SetPricing(table *pricing.Table) {
+	if a == nil {
+		return
+	}
+	a.pricing = table
+}
+
+// SetPeriod changes the date key for events added after the call. weekStart is
+// only used by PeriodWeek.
+func (a *DailyEventAggregator) SetPeriod(bucket PeriodBucket, weekStart time.Weekday) {
+	if a == nil {
+		return
+	}
+	a.bucket = normalizePeriodBucket(bucket)
+	a.weekStart = weekStart
+}
+
 // Add includes one event in the daily aggregate.
 func (a *DailyEventAggregator) Add(e provider.UsageEvent) {
 	if a == nil {
@@ -45,8 +70,10 @@ func (a *DailyEventAggregator) Add(e provider.UsageEvent) {
 	if a.dayMap == nil {
 		a.dayMap = make(map[dailyEventKey]*dailyEventAggregate)
 	}
-	date := e.Timestamp.In(a.loc).Format("2006-01-02")
-	group := eventGroupNameForDimension(e, a.dimension)
+	date := PeriodKey(e.Timestamp.In(a.loc), normalizePeriodBucket(a.bucket), a.weekStart)
+	group, values := compositeGroup(a.dimension, func(d AggregateDimension) string {
+		return eventGroupNameForDimension(e, d)
+	})
 	key := dailyEventKey{date: date, group: group}
 	agg, ok := a.dayMap[key]
 	if !ok {
@@ -55,6 +82,7 @@ func (a *DailyEventAggregator) Add(e provider.UsageEvent) {
 				Date:    date,
 				GroupBy: string(a.dimension),
 				Group:   group,
+				Groups:  compositeGroups(a.dimension, values),
 			},
 			providers:   make(map[string]struct{}),
 			sessionKeys: make(map[string]struct{}),
@@ -70,6 +98,9 @@ func (a *DailyEventAggregator) Add(e provider.UsageEvent) {
 		agg.stats.Sessions++
 	}
 	addTokenUsage(&agg.stats.TokenUsage, e.TokenUsage)
+	if a.pricing != nil {
+		agg.stats.Cost.Add(EstimateEventCost(a.pricing, e))
+	}
 }
 
 // Results returns sorted daily stats for all events added so far.
@@ -177,6 +208,15 @@ func EventInDateRange(e provider.UsageEvent, sinceDate, untilDate string, loc *t
 	return NewEventDateRangeFilter(sinceDate, untilDate, loc).Contains(e)
 }
 
+// EstimateEventCost prices one event by its normalized model name.
+// A nil table yields an empty estimate.
+func EstimateEventCost(table *pricing.Table, e provider.UsageEvent) provider.CostEstimate {
+	if table == nil {
+		return provider.CostEstimate{}
+	}
+	return table.Estimate(normalizeModelName(e.ModelName, e.ProviderName), e.TokenUsage)
+}
+
 func normalizeEventLocation(loc *time.Location) *time.Location {
 	if loc == nil {
 		return time.Local
@@ -188,6 +228,10 @@ func eventGroupNameForDimension(e provider.UsageEvent, dimension AggregateDimens
 	switch dimension {
 	case AggregateDimensionModel:
 		return normalizeModelName(e.ModelName, e.ProviderName)
+	case AggregateDimensionProject:
+		return ProjectName(e.ProjectPath, e.WorkDirHash, e.ProviderName)
+	case AggregateDimensionAccount:
+		return AccountName(e.Account, e.ProviderName)
 	case AggregateDimensionCLI, "":
 		return normalizedEventProviderName(e)
 	default:
@@ -213,6 +257,7 @@ func eventSessionKey(e provider.UsageEvent) string {
 func addTokenUsage(dst *provider.TokenUsage, src provider.TokenUsage) {
 	dst.InputOther += src.InputOther
 	dst.Output += src.Output
+	dst.OutputReasoning += src.OutputReasoning
 	dst.InputCacheRead += src.InputCacheRead
 	dst.InputCacheCreate += src.InputCacheCreate
 }
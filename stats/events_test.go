@@ -1,10 +1,14 @@
 package stats
 
 import (
+	"encoding/json"
+	"math"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/miss-you/codetok/pricing"
 	"github.com/miss-you/codetok/provider"
 )
 
@@ -47,6 +51,25 @@ func TestAggregateEventsByDayWithDimension_SplitsSameSessionAcrossDays(t *testin
 	}
 }
 
+func TestAggregateEventsByDayWithDimension_SumsReasoningOutput(t *testing.T) {
+	first := makeUsageEvent("s1", "codex", "gpt-5.4", time.Date(2026, 4, 16, 9, 0, 0, 0, time.UTC), 100, 40)
+	first.TokenUsage.OutputReasoning = 15
+	second := makeUsageEvent("s1", "codex", "gpt-5.4", time.Date(2026, 4, 16, 10, 0, 0, 0, time.UTC), 100, 60)
+	second.TokenUsage.OutputReasoning = 20
+
+	got := AggregateEventsByDayWithDimension([]provider.UsageEvent{first, second}, AggregateDimensionCLI, time.UTC)
+
+	if len(got) != 1 {
+		t.Fatalf("got %d rows, want 1: %#v", len(got), got)
+	}
+	if got[0].TokenUsage.OutputReasoning != 35 || got[0].TokenUsage.Output != 100 {
+		t.Fatalf("token usage = %+v, want reasoning 35 within output 100", got[0].TokenUsage)
+	}
+	if got[0].TokenUsage.Total() != 300 {
+		t.Fatalf("total = %d, want 300 (reasoning not double counted)", got[0].TokenUsage.Total())
+	}
+}
+
 func TestAggregateEventsByDayWithDimension_UsesRequestedTimezone(t *testing.T) {
 	utc := time.UTC
 	shanghai := time.FixedZone("UTC+8", 8*3600)
@@ -175,6 +198,136 @@ func TestAggregateEventsByDayWithDimension_ModelAcrossProviders(t *testing.T) {
 	}
 }
 
+func TestAggregateEventsByDayWithDimension_ProjectPrefersPathOverHash(t *testing.T) {
+	ts := time.Date(2026, 4, 16, 9, 0, 0, 0, time.UTC)
+	claude := makeUsageEvent("s1", "claude", "", ts, 100, 10)
+	claude.WorkDirHash = "-home-dev-codetok"
+	claude.ProjectPath = "/home/dev/codetok"
+	codex := makeUsageEvent("s2", "codex", "", ts, 200, 20)
+	codex.ProjectPath = "/home/dev/codetok"
+	gemini := makeUsageEvent("s3", "gemini", "", ts, 300, 30)
+	gemini.WorkDirHash = "3f1a9c"
+	cursor := makeUsageEvent("s4", "cursor", "", ts, 400, 40)
+
+	got := AggregateEventsByDayWithDimension([]provider.UsageEvent{claude, codex, gemini, cursor}, AggregateDimensionProject, time.UTC)
+
+	if len(got) != 3 {
+		t.Fatalf("got %d rows, want 3: %#v", len(got), got)
+	}
+	byGroup := make(map[string]provider.DailyStats)
+	for _, row := range got {
+		if row.GroupBy != "project" {
+			t.Fatalf("GroupBy = %q, want project", row.GroupBy)
+		}
+		byGroup[row.Group] = row
+	}
+	shared := byGroup["/home/dev/codetok"]
+	if shared.Sessions != 2 || shared.TokenUsage.Total() != 330 || len(shared.Providers) != 2 {
+		t.Fatalf("shared project row = %#v, want two providers and two sessions totaling 330", shared)
+	}
+	if _, ok := byGroup["3f1a9c"]; !ok {
+		t.Fatalf("missing hash fallback group in %#v", got)
+	}
+	if _, ok := byGroup["unknown (cursor)"]; !ok {
+		t.Fatalf("missing unknown project group in %#v", got)
+	}
+}
+
+func TestAggregateEventsByDayWithDimension_AccountSeparatesCursorProfiles(t *testing.T) {
+	ts := time.Date(2026, 4, 16, 9, 0, 0, 0, time.UTC)
+	work := makeUsageEvent("work/usage:1", "cursor", "gpt-5", ts, 100, 10)
+	work.Account = "work"
+	personal := makeUsageEvent("usage:1", "cursor", "gpt-5", ts, 200, 20)
+	personal.Account = "default"
+	claude := makeUsageEvent("s1", "claude", "", ts, 300, 30)
+
+	got := AggregateEventsByDayWithDimension([]provider.UsageEvent{work, personal, claude}, AggregateDimensionAccount, time.UTC)
+
+	byGroup := make(map[string]provider.DailyStats)
+	for _, row := range got {
+		if row.GroupBy != "account" {
+			t.Fatalf("GroupBy = %q, want account", row.GroupBy)
+		}
+		byGroup[row.Group] = row
+	}
+	if len(byGroup) != 3 || byGroup["work"].TokenUsage.Total() != 110 || byGroup["default"].TokenUsage.Total() != 220 {
+		t.Fatalf("rows = %#v, want work and default Cursor accounts kept apart", got)
+	}
+	if _, ok := byGroup["unknown (claude)"]; !ok {
+		t.Fatalf("missing unknown account group in %#v", got)
+	}
+}
+
+func TestAggregateEventsByDayWithDimension_CompositeKeysOnTuple(t *testing.T) {
+	ts := time.Date(2026, 4, 16, 9, 0, 0, 0, time.UTC)
+	events := []provider.UsageEvent{
+		makeUsageEvent("s1", "claude", "claude-opus-4-1", ts, 100, 10),
+		makeUsageEvent("s2", "claude", "claude-sonnet-4-5", ts, 200, 20),
+		makeUsageEvent("s3", "codex", "gpt-5", ts, 300, 30),
+		makeUsageEvent("s4", "codex", "gpt-5", ts, 400, 40),
+	}
+
+	dimension := CompositeDimension(AggregateDimensionCLI, AggregateDimensionModel)
+	got := AggregateEventsByDayWithDimension(events, dimension, time.UTC)
+
+	want := []struct {
+		group    string
+		model    string
+		sessions int
+	}{
+		{group: "claude / claude-opus-4-1", model: "claude-opus-4-1", sessions: 1},
+		{group: "claude / claude-sonnet-4-5", model: "claude-sonnet-4-5", sessions: 1},
+		{group: "codex / gpt-5", model: "gpt-5", sessions: 2},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d rows, want %d: %#v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		row := got[i]
+		if row.GroupBy != "cli,model" || row.Group != w.group || row.Sessions != w.sessions {
+			t.Fatalf("row %d = %#v, want group %q with %d sessions", i, row, w.group, w.sessions)
+		}
+		if row.Groups["cli"] != row.ProviderName || row.Groups["model"] != w.model {
+			t.Fatalf("row %d groups = %#v, want cli=%q model=%q", i, row.Groups, row.ProviderName, w.model)
+		}
+	}
+}
+
+func TestAggregateEventsByDayWithDimension_SingleDimensionOmitsGroups(t *testing.T) {
+	ts := time.Date(2026, 4, 16, 9, 0, 0, 0, time.UTC)
+	events := []provider.UsageEvent{
+		makeUsageEvent("s1", "claude", "claude-sonnet-4-5", ts, 100, 10),
+		makeUsageEvent("s2", "codex", "gpt-5", ts, 200, 20),
+	}
+
+	for _, row := range AggregateEventsByDayWithDimension(events, AggregateDimensionModel, time.UTC) {
+		if row.Groups != nil {
+			t.Fatalf("row %#v has groups, want them only for composite dimensions", row)
+		}
+		data, err := json.Marshal(row)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(data), `"groups"`) {
+			t.Fatalf("daily JSON row = %s, want no groups field", data)
+		}
+	}
+}
+
+func TestCompositeDimension_NormalizesComponents(t *testing.T) {
+	got := CompositeDimension(AggregateDimensionProject, AggregateDimensionModel, AggregateDimensionProject)
+	if got != "project,model" {
+		t.Fatalf("CompositeDimension = %q, want project,model", got)
+	}
+	components := got.Components()
+	if len(components) != 2 || components[0] != AggregateDimensionProject || components[1] != AggregateDimensionModel {
+		t.Fatalf("Components = %#v, want [project model]", components)
+	}
+	if single := AggregateDimensionModel.Components(); len(single) != 1 || single[0] != AggregateDimensionModel {
+		t.Fatalf("single Components = %#v, want [model]", single)
+	}
+}
+
 func TestDailyEventAggregator_MatchesMaterializedAggregation(t *testing.T) {
 	loc := time.FixedZone("UTC+8", 8*3600)
 	events := []provider.UsageEvent{
@@ -212,6 +365,40 @@ func TestDailyEventAggregator_AddNormalizesLocationWithExistingMap(t *testing.T)
 	}
 }
 
+func TestDailyEventAggregator_SetPeriodCountsSessionsOncePerPeriod(t *testing.T) {
+	loc := time.FixedZone("UTC+8", 8*3600)
+	events := []provider.UsageEvent{
+		makeUsageEvent("s1", "codex", "", time.Date(2026, 4, 13, 9, 0, 0, 0, loc), 100, 10),
+		makeUsageEvent("s1", "codex", "", time.Date(2026, 4, 19, 23, 0, 0, 0, loc), 200, 20),
+		makeUsageEvent("s2", "codex", "", time.Date(2026, 4, 20, 0, 30, 0, 0, loc), 300, 30),
+	}
+
+	weekly := NewDailyEventAggregator(AggregateDimensionCLI, loc)
+	weekly.SetPeriod(PeriodWeek, time.Monday)
+	monthly := NewDailyEventAggregator(AggregateDimensionCLI, loc)
+	monthly.SetPeriod(PeriodMonth, time.Monday)
+	for _, event := range events {
+		weekly.Add(event)
+		monthly.Add(event)
+	}
+
+	gotWeekly := weekly.Results()
+	if len(gotWeekly) != 2 {
+		t.Fatalf("got %d weekly rows, want 2: %#v", len(gotWeekly), gotWeekly)
+	}
+	if gotWeekly[0].Date != "2026-W16" || gotWeekly[0].Sessions != 1 || gotWeekly[0].TokenUsage.Total() != 330 {
+		t.Fatalf("first week = %#v, want 2026-W16 with one session totaling 330", gotWeekly[0])
+	}
+	if gotWeekly[1].Date != "2026-W17" || gotWeekly[1].Sessions != 1 {
+		t.Fatalf("second week = %#v, want 2026-W17 with one session", gotWeekly[1])
+	}
+
+	gotMonthly := monthly.Results()
+	if len(gotMonthly) != 1 || gotMonthly[0].Date != "2026-04" || gotMonthly[0].Sessions != 2 {
+		t.Fatalf("monthly = %#v, want one 2026-04 row with two sessions", gotMonthly)
+	}
+}
+
 func TestEventDateRangeFilter_MatchesEventInDateRange(t *testing.T) {
 	loc := time.FixedZone("UTC+8", 8*3600)
 	event := makeUsageEvent("s1", "codex", "", time.Date(2026, 4, 15, 16, 30, 0, 0, time.UTC), 100, 10)
@@ -244,3 +431,34 @@ func TestFilterEventsByDateRange_UsesLocalizedInclusiveDateKeys(t *testing.T) {
 		t.Fatalf("filtered sessions = [%s %s], want [inside-1 inside-2]", got[0].SessionID, got[1].SessionID)
 	}
 }
+
+func TestDailyEventAggregator_EstimatesCostPerEventModel(t *testing.T) {
+	aggregator := NewDailyEventAggregator(AggregateDimensionCLI, time.UTC)
+	aggregator.SetPricing(pricing.Default())
+
+	day := time.Date(2026, 4, 16, 9, 0, 0, 0, time.UTC)
+	aggregator.Add(makeUsageEvent("s1", "claude", "claude-sonnet-4-5-20250929", day, 1_000_000, 0))
+	aggregator.Add(makeUsageEvent("s2", "claude", "claude-haiku-4-5", day, 1_000_000, 0))
+	aggregator.Add(makeUsageEvent("s3", "codex", "in-house-model", day, 1_000_000, 0))
+
+	got := aggregator.Results()
+	if len(got) != 2 {
+		t.Fatalf("got %d rows, want 2: %#v", len(got), got)
+	}
+	claude, codex := got[0], got[1]
+	if claude.Cost.Status != provider.CostStatusKnown || math.Abs(claude.Cost.USD-4) > 1e-9 {
+		t.Fatalf("claude cost = %+v, want $4 known", claude.Cost)
+	}
+	if codex.Cost.Status != provider.CostStatusUnknown || len(codex.Cost.UnpricedModels) != 1 {
+		t.Fatalf("codex cost = %+v, want unknown with unpriced model", codex.Cost)
+	}
+}
+
+func TestDailyEventAggregator_WithoutPricingLeavesCostEmpty(t *testing.T) {
+	got := AggregateEventsByDayWithDimension([]provider.UsageEvent{
+		makeUsageEvent("s1", "claude", "claude-sonnet-4-5", time.Date(2026, 4, 16, 9, 0, 0, 0, time.UTC), 100, 10),
+	}, AggregateDimensionCLI, time.UTC)
+	if got[0].Cost.Status != "" || got[0].Cost.USD != 0 {
+		t.Fatalf("cost = %+v, want empty estimate without pricing", got[0].Cost)
+	}
+}
@@ -0,0 +1,64 @@
+package stats
+
+import (
+	"sort"
+	"time"
+
+	"github.com/miss-you/codetok/provider"
+)
+
+// HeatmapWeekdays orders heatmap rows, Monday first.
+var HeatmapWeekdays = [7]time.Weekday{
+	time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday,
+}
+
+// Heatmap holds token totals by weekday and hour of day for one group.
+type Heatmap struct {
+	Group  string            `json:"group"`
+	Groups map[string]string `json:"groups,omitempty"`
+	// Tokens[d][h] counts tokens at hour h on weekday HeatmapWeekdays[d].
+	Tokens [7][24]int `json:"tokens"`
+	Events int        `json:"events"`
+	Total  int        `json:"total"`
+}
+
+// BuildHeatmaps buckets events by weekday and hour in loc. With an empty
+// dimension all events go into one heatmap named "all"; otherwise there is one
+// heatmap per group, sorted by total tokens descending.
+func BuildHeatmaps(events []provider.UsageEvent, dimension AggregateDimension, loc *time.Location) []Heatmap {
+	loc = normalizeEventLocation(loc)
+	if dimension != "" {
+		dimension = normalizeAggregateDimension(dimension)
+	}
+	byGroup := make(map[string]*Heatmap)
+	for _, e := range events {
+		group, values := "all", []string(nil)
+		if dimension != "" {
+			group, values = compositeGroup(dimension, func(d AggregateDimension) string {
+				return eventGroupNameForDimension(e, d)
+			})
+		}
+		heatmap, ok := byGroup[group]
+		if !ok {
+			heatmap = &Heatmap{Group: group, Groups: compositeGroups(dimension, values)}
+			byGroup[group] = heatmap
+		}
+		local := e.Timestamp.In(loc)
+		tokens := e.TokenUsage.Total()
+		heatmap.Tokens[(int(local.Weekday())+6)%7][local.Hour()] += tokens
+		heatmap.Events++
+		heatmap.Total += tokens
+	}
+
+	heatmaps := make([]Heatmap, 0, len(byGroup))
+	for _, heatmap := range byGroup {
+		heatmaps = append(heatmaps, *heatmap)
+	}
+	sort.Slice(heatmaps, func(i, j int) bool {
+		if heatmaps[i].Total != heatmaps[j].Total {
+			return heatmaps[i].Total > heatmaps[j].Total
+		}
+		return heatmaps[i].Group < heatmaps[j].Group
+	})
+	return heatmaps
+}
@@ -0,0 +1,38 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/miss-you/codetok/provider"
+)
+
+func TestBuildHeatmaps_BucketsByLocalWeekdayAndHour(t *testing.T) {
+	loc := time.FixedZone("UTC+8", 8*3600)
+	events := []provider.UsageEvent{
+		// Wednesday 2026-04-15 20:30 UTC is Thursday 04:30 in UTC+8.
+		{ProviderName: "codex", ModelName: "gpt-5.4", Timestamp: time.Date(2026, 4, 15, 20, 30, 0, 0, time.UTC), TokenUsage: provider.TokenUsage{InputOther: 100}},
+		{ProviderName: "claude", ModelName: "claude-sonnet-4-5", Timestamp: time.Date(2026, 4, 19, 14, 10, 0, 0, loc), TokenUsage: provider.TokenUsage{Output: 30}},
+		{ProviderName: "claude", ModelName: "claude-sonnet-4-5", Timestamp: time.Date(2026, 4, 19, 14, 50, 0, 0, loc), TokenUsage: provider.TokenUsage{Output: 20}},
+	}
+
+	all := BuildHeatmaps(events, "", loc)
+	if len(all) != 1 || all[0].Group != "all" || all[0].Total != 150 || all[0].Events != 3 {
+		t.Fatalf("heatmaps = %+v, want one combined heatmap", all)
+	}
+	if got := all[0].Tokens[3][4]; got != 100 {
+		t.Fatalf("Thursday 04:00 = %d, want the codex event in local time", got)
+	}
+	if got := all[0].Tokens[6][14]; got != 50 {
+		t.Fatalf("Sunday 14:00 = %d, want both claude events", got)
+	}
+
+	byCLI := BuildHeatmaps(events, AggregateDimensionCLI, loc)
+	if len(byCLI) != 2 || byCLI[0].Group != "codex" || byCLI[1].Group != "claude" || byCLI[1].Groups != nil {
+		t.Fatalf("heatmaps by cli = %+v, want codex then claude by total", byCLI)
+	}
+	byCLIModel := BuildHeatmaps(events, CompositeDimension(AggregateDimensionCLI, AggregateDimensionModel), loc)
+	if byCLIModel[1].Group != "claude / claude-sonnet-4-5" || byCLIModel[1].Groups["model"] != "claude-sonnet-4-5" {
+		t.Fatalf("composite group = %q %v", byCLIModel[1].Group, byCLIModel[1].Groups)
+	}
+}
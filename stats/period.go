@@ -0,0 +1,71 @@
+package stats
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PeriodBucket defines the calendar period used as the date key of aggregated usage.
+type PeriodBucket string
+
+const (
+	// PeriodDay keys usage by calendar day (2006-01-02).
+	PeriodDay PeriodBucket = "day"
+	// PeriodWeek keys usage by week. Monday-start weeks use ISO week keys (2006-W01);
+	// other week starts use the date of the first day of the week (2006-01-02).
+	PeriodWeek PeriodBucket = "week"
+	// PeriodMonth keys usage by calendar month (2006-01).
+	PeriodMonth PeriodBucket = "month"
+)
+
+// ParseWeekday parses an English weekday name or its three-letter abbreviation.
+func ParseWeekday(name string) (time.Weekday, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		full := strings.ToLower(d.String())
+		if name == full || name == full[:3] {
+			return d, nil
+		}
+	}
+	return time.Sunday, fmt.Errorf("unknown weekday %q", name)
+}
+
+// PeriodStart returns midnight at the start of the period containing t, in t's location.
+func PeriodStart(t time.Time, bucket PeriodBucket, weekStart time.Weekday) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	switch bucket {
+	case PeriodWeek:
+		offset := (int(day.Weekday()) - int(weekStart) + 7) % 7
+		return day.AddDate(0, 0, -offset)
+	case PeriodMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	default:
+		return day
+	}
+}
+
+// PeriodKey returns the date key of the period containing t, in t's location.
+func PeriodKey(t time.Time, bucket PeriodBucket, weekStart time.Weekday) string {
+	switch bucket {
+	case PeriodWeek:
+		if weekStart == time.Monday {
+			year, week := t.ISOWeek()
+			return fmt.Sprintf("%04d-W%02d", year, week)
+		}
+		return PeriodStart(t, bucket, weekStart).Format("2006-01-02")
+	case PeriodMonth:
+		return t.Format("2006-01")
+	default:
+		return t.Format("2006-01-02")
+	}
+}
+
+func normalizePeriodBucket(bucket PeriodBucket) PeriodBucket {
+	switch bucket {
+	case PeriodWeek, PeriodMonth:
+		return bucket
+	default:
+		return PeriodDay
+	}
+}
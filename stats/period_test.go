@@ -0,0 +1,62 @@
+package stats
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPeriodKey(t *testing.T) {
+	tests := []struct {
+		name      string
+		at        time.Time
+		bucket    PeriodBucket
+		weekStart time.Weekday
+		want      string
+	}{
+		{name: "day", at: time.Date(2026, 4, 16, 23, 0, 0, 0, time.UTC), bucket: PeriodDay, want: "2026-04-16"},
+		{name: "iso week", at: time.Date(2026, 4, 16, 0, 0, 0, 0, time.UTC), bucket: PeriodWeek, weekStart: time.Monday, want: "2026-W16"},
+		{name: "iso week belongs to next year", at: time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC), bucket: PeriodWeek, weekStart: time.Monday, want: "2025-W01"},
+		{name: "iso week belongs to previous year", at: time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC), bucket: PeriodWeek, weekStart: time.Monday, want: "2026-W53"},
+		{name: "sunday week start", at: time.Date(2026, 4, 16, 0, 0, 0, 0, time.UTC), bucket: PeriodWeek, weekStart: time.Sunday, want: "2026-04-12"},
+		{name: "sunday is its own week start", at: time.Date(2026, 4, 19, 8, 0, 0, 0, time.UTC), bucket: PeriodWeek, weekStart: time.Sunday, want: "2026-04-19"},
+		{name: "month", at: time.Date(2026, 4, 30, 23, 59, 0, 0, time.UTC), bucket: PeriodMonth, want: "2026-04"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PeriodKey(tt.at, tt.bucket, tt.weekStart); got != tt.want {
+				t.Fatalf("PeriodKey = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPeriodStart(t *testing.T) {
+	loc := time.FixedZone("UTC+8", 8*3600)
+	at := time.Date(2026, 4, 16, 15, 30, 0, 0, loc)
+
+	if got, want := PeriodStart(at, PeriodWeek, time.Monday), time.Date(2026, 4, 13, 0, 0, 0, 0, loc); !got.Equal(want) {
+		t.Errorf("week start = %v, want %v", got, want)
+	}
+	if got, want := PeriodStart(at, PeriodWeek, time.Saturday), time.Date(2026, 4, 11, 0, 0, 0, 0, loc); !got.Equal(want) {
+		t.Errorf("saturday week start = %v, want %v", got, want)
+	}
+	if got, want := PeriodStart(at, PeriodMonth, time.Monday), time.Date(2026, 4, 1, 0, 0, 0, 0, loc); !got.Equal(want) {
+		t.Errorf("month start = %v, want %v", got, want)
+	}
+}
+
+func TestParseWeekday(t *testing.T) {
+	for input, want := range map[string]time.Weekday{
+		"monday": time.Monday,
+		"Sun":    time.Sunday,
+		" SAT ":  time.Saturday,
+	} {
+		got, err := ParseWeekday(input)
+		if err != nil || got != want {
+			t.Errorf("ParseWeekday(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+	if _, err := ParseWeekday("someday"); err == nil {
+		t.Fatal("expected error for unknown weekday")
+	}
+}